@@ -7,6 +7,8 @@ import (
 	pgbuilder "dynamic-sqlbuilder/querybuilder/pgBuilder"
 	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
 	"fmt"
+	"os"
+	"strings"
 )
 
 func buildBalanceSheetQuery() {
@@ -14,7 +16,8 @@ func buildBalanceSheetQuery() {
 
 	// Create date range condition for YTD
 	ytdConfig := daterange.DateConfig{
-		Type: daterange.YTD,
+		Type:    daterange.YTD,
+		Columns: &daterange.PeriodColumns{Year: "t.period_year", Month: "t.period_month"},
 	}
 	dateCondition := daterange.NewDateRangeCondition(ytdConfig)
 
@@ -23,13 +26,16 @@ func buildBalanceSheetQuery() {
 
 	query, args, err := builder.SelectAggregate().
 		AddRegularField("coa.coadescription").
-		AddAggregate(aggregate.Sum, "amount", "total_amount").
-		From("transactions").
+		AddAggregate(aggregate.Sum, "t.amount", "total_amount").
+		AutoGroupBy().
+		FromAs("transactions", "t").
+		Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+			fields.NewColumnCondition("t.account_code", fields.Equals, "coa.account_code")).
 		Where(dateCondition).
 		And().
-		Where(fields.NewFieldCondition("account_code", fields.In, activeAccounts)).
+		Where(fields.NewFieldCondition("t.account_code", fields.In, activeAccounts)).
 		And().
-		Where(fields.NewFieldCondition("is_active", fields.Equals, true)).
+		Where(fields.NewFieldCondition("t.is_active", fields.Equals, true)).
 		Build()
 
 	if err != nil {
@@ -65,6 +71,7 @@ func buildProfitLossQuery() {
 		Where(dateCondition).
 		And().
 		Where(fields.NewFieldCondition("account_type", fields.In, accountTypes)).
+		GroupBy("account_type", "department").
 		Build()
 
 	if err != nil {
@@ -96,6 +103,7 @@ func buildCashFlowQuery() {
 		AddRegularField("transaction_type").
 		AddAggregate(aggregate.Sum, "inflow", "total_inflow").
 		AddAggregate(aggregate.Sum, "outflow", "total_outflow").
+		AutoGroupBy().
 		From("cash_transactions").
 		Where(dateCondition).
 		WhereGroup(querybuilder.OR, func(group *querybuilder.WhereGroup) {
@@ -130,6 +138,7 @@ func buildCustomReportQuery() {
 		AddAggregate(aggregate.Sum, "revenue", "total_revenue").
 		AddAggregate(aggregate.Sum, "expense", "total_expense").
 		AddAggregate(aggregate.Avg, "profit_margin", "avg_margin").
+		AutoGroupBy().
 		From("financial_metrics").
 		Where(dateCondition).
 		WhereGroup(querybuilder.AND, func(group *querybuilder.WhereGroup) {
@@ -147,7 +156,46 @@ func buildCustomReportQuery() {
 	fmt.Printf("Arguments: %v\n", args)
 }
 
+func buildPeriodQuery(expr string) {
+	builder := pgbuilder.NewPostgresQueryBuilder()
+
+	// Parse a compact period expression such as "last 3 months" or "Q1 2024"
+	config, err := daterange.ParseExpression(expr, nil)
+	if err != nil {
+		fmt.Printf("Error parsing period: %v\n", err)
+		return
+	}
+	canonical, err := config.Expression()
+	if err != nil {
+		fmt.Printf("Error formatting period: %v\n", err)
+		return
+	}
+
+	query, args, err := builder.SelectAggregate().
+		AddRegularField("account_type").
+		AddAggregate(aggregate.Sum, "amount", "total_amount").
+		AutoGroupBy().
+		From("financial_transactions").
+		Where(daterange.NewDateRangeCondition(config)).
+		Build()
+
+	if err != nil {
+		fmt.Printf("Error building period query: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Period: %s\n", canonical)
+	fmt.Printf("Period Query: %s\n", query)
+	fmt.Printf("Arguments: %v\n", args)
+}
+
 func main() {
+	// A period expression argument builds a single report for that period
+	if len(os.Args) > 1 {
+		buildPeriodQuery(strings.Join(os.Args[1:], " "))
+		return
+	}
+
 	fmt.Println("=== Building Various Financial Queries ===")
 
 	fmt.Println("1. Balance Sheet Query:")
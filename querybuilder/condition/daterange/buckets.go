@@ -0,0 +1,120 @@
+package daterange
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bucket granularities, next to MonthGranularity and DayGranularity
+const (
+	// QuarterGranularity covers whole quarters, fiscal quarters under a fiscal calendar
+	QuarterGranularity Granularity = "QUARTER"
+	// YearGranularity covers whole years, fiscal years under a fiscal calendar
+	YearGranularity Granularity = "YEAR"
+)
+
+// Bucket is one period of a date range split by a granularity
+type Bucket struct {
+	// Key names the period and sorts in period order, e.g. 2024-01, FY2024-P05,
+	// 2024-Q1, FY2024-Q3, 2024, FY2024 or 2024-05-15
+	Key string
+	// Alias is a column name for the period, e.g. m_2024_01, q_fy2024_3 or d_2024_05_15
+	Alias string
+	// Condition filters on the period, with the columns and calendar of the range
+	Condition *DateRangeCondition
+}
+
+// Buckets splits the resolved range into periods of the granularity, e.g. the
+// months of a year to date. Buckets at the edges are cut to the range, so a
+// year to date by quarter ends with the quarter to date. Ranges with day
+// precision can only be split into days, and day buckets need the DATE column mode.
+func (d *DateRangeCondition) Buckets(granularity Granularity) ([]Bucket, error) {
+	if err := d.DateConfig.validate(false); err != nil {
+		return nil, err
+	}
+	r, err := d.resolvePeriods()
+	if err != nil {
+		return nil, err
+	}
+
+	if granularity == DayGranularity {
+		return d.dayBuckets(r.start(), r.end()), nil
+	}
+	if r.days {
+		return nil, fmt.Errorf("%s has day precision and can only be bucketed by %s", d.DateConfig.Type, DayGranularity)
+	}
+
+	var buckets []Bucket
+	for first := r.first; first <= r.last; {
+		var last int
+		var key, alias string
+		switch granularity {
+		case MonthGranularity:
+			last = first
+			key, alias = periodKey(r.calendar, first)
+		case QuarterGranularity:
+			start := quarterStart(r.calendar, first)
+			last = start + 2
+			quarter := (start-r.calendar.yearStart(start))/3 + 1
+			year := r.calendar.yearName(start)
+			key = fmt.Sprintf("%s-Q%d", year, quarter)
+			alias = fmt.Sprintf("q_%s_%d", strings.ToLower(year), quarter)
+		case YearGranularity:
+			last = r.calendar.yearStart(first) + 11
+			key = r.calendar.yearName(first)
+			alias = "y_" + strings.ToLower(key)
+		default:
+			return nil, fmt.Errorf("invalid bucket granularity: %s", granularity)
+		}
+		if last > r.last {
+			last = r.last
+		}
+
+		start, end := periodLabel(first), periodLabel(last)
+		buckets = append(buckets, d.bucket(key, alias, DateConfig{
+			Type:       SpecificRange,
+			Parameters: DateParameters{Start: &start, End: &end},
+		}))
+		first = last + 1
+	}
+	return buckets, nil
+}
+
+// periodKey names a single period, a calendar month or a fiscal period
+func periodKey(cal calendar, period int) (key, alias string) {
+	label := periodLabel(period)
+	if _, ok := cal.(weekCalendar); ok {
+		return fmt.Sprintf("FY%d-P%02d", label.Year, label.Month), fmt.Sprintf("p_fy%d_%02d", label.Year, label.Month)
+	}
+	return formatMonth(label), fmt.Sprintf("m_%d_%02d", label.Year, label.Month)
+}
+
+func (d *DateRangeCondition) dayBuckets(from, until time.Time) []Bucket {
+	var buckets []Bucket
+	for day := from; day.Before(until); day = day.AddDate(0, 0, 1) {
+		date := day.Format(AsOfLayout)
+		buckets = append(buckets, d.bucket(date, "d_"+strings.ReplaceAll(date, "-", "_"), DateConfig{
+			Type:       SpecificDateRange,
+			Parameters: DateParameters{StartDate: &date, EndDate: &date},
+		}))
+	}
+	return buckets
+}
+
+// bucket keeps the columns, calendar and location of the range for the period in config
+func (d *DateRangeCondition) bucket(key, alias string, config DateConfig) Bucket {
+	config.Timezone = d.DateConfig.Timezone
+	config.Predicate = d.DateConfig.Predicate
+	config.Columns = d.DateConfig.Columns
+	config.Fiscal = d.DateConfig.Fiscal
+	return Bucket{
+		Key:   key,
+		Alias: alias,
+		Condition: &DateRangeCondition{
+			DateConfig: config,
+			location:   d.location,
+			clock:      d.clock,
+		},
+	}
+}
@@ -0,0 +1,120 @@
+package daterange
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangeCondition_Buckets(t *testing.T) {
+	jakarta := mustLoad(t, DefaultTimezone)
+	may2024 := WithAsOf(time.Date(2024, 5, 15, 0, 0, 0, 0, jakarta))
+	dateColumn := &PeriodColumns{Mode: DateColumn, Column: "posted_at"}
+
+	tests := []struct {
+		name        string
+		config      DateConfig
+		granularity Granularity
+		keys        []string
+		aliases     []string
+	}{
+		{
+			name:        "Months of a year to date",
+			config:      DateConfig{Type: YTD},
+			granularity: MonthGranularity,
+			keys:        []string{"2024-01", "2024-02", "2024-03", "2024-04", "2024-05"},
+			aliases:     []string{"m_2024_01", "m_2024_02", "m_2024_03", "m_2024_04", "m_2024_05"},
+		},
+		{
+			name:        "Quarters cut to the range",
+			config:      DateConfig{Type: RelativeRange, Parameters: DateParameters{StartBackMonths: intPointer(5), EndBackMonths: intPointer(0)}},
+			granularity: QuarterGranularity,
+			keys:        []string{"2023-Q4", "2024-Q1", "2024-Q2"},
+			aliases:     []string{"q_2023_4", "q_2024_1", "q_2024_2"},
+		},
+		{
+			name:        "Fiscal quarters",
+			config:      DateConfig{Type: FiscalYear, Fiscal: &FiscalCalendar{StartMonth: 7}},
+			granularity: QuarterGranularity,
+			keys:        []string{"FY2024-Q1", "FY2024-Q2", "FY2024-Q3", "FY2024-Q4"},
+			aliases:     []string{"q_fy2024_1", "q_fy2024_2", "q_fy2024_3", "q_fy2024_4"},
+		},
+		{
+			name:        "Years of a trailing twelve months",
+			config:      DateConfig{Type: TTM},
+			granularity: YearGranularity,
+			keys:        []string{"2023", "2024"},
+			aliases:     []string{"y_2023", "y_2024"},
+		},
+		{
+			name:        "Fiscal periods",
+			config:      DateConfig{Type: QTD, Fiscal: &FiscalCalendar{WeekPattern: Pattern445}},
+			granularity: MonthGranularity,
+			keys:        []string{"FY2024-P04", "FY2024-P05"},
+			aliases:     []string{"p_fy2024_04", "p_fy2024_05"},
+		},
+		{
+			name:        "Days of a month",
+			config:      DateConfig{Type: LastNDays, Parameters: DateParameters{Days: intPointer(3)}, Columns: dateColumn},
+			granularity: DayGranularity,
+			keys:        []string{"2024-05-13", "2024-05-14", "2024-05-15"},
+			aliases:     []string{"d_2024_05_13", "d_2024_05_14", "d_2024_05_15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets, err := NewDateRangeCondition(tt.config, may2024).Buckets(tt.granularity)
+			require.NoError(t, err)
+
+			var keys, aliases []string
+			for _, bucket := range buckets {
+				keys = append(keys, bucket.Key)
+				aliases = append(aliases, bucket.Alias)
+			}
+			assert.Equal(t, tt.keys, keys)
+			assert.Equal(t, tt.aliases, aliases)
+		})
+	}
+}
+
+func TestDateRangeCondition_BucketConditions(t *testing.T) {
+	jakarta := mustLoad(t, DefaultTimezone)
+	condition := NewDateRangeCondition(DateConfig{
+		Type:    RelativeRange,
+		Columns: &PeriodColumns{Mode: PeriodKeyColumn, Column: "period_key"},
+		Parameters: DateParameters{
+			StartBackMonths: intPointer(4),
+			EndBackMonths:   intPointer(0),
+		},
+	}, WithClock(FixedClock(time.Date(2024, 5, 15, 0, 0, 0, 0, jakarta))))
+
+	buckets, err := condition.Buckets(QuarterGranularity)
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+
+	// The first quarter starts at the range, not at the quarter
+	sql, params, err := buckets[0].Condition.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `"period_key" BETWEEN $1 AND $2`, sql)
+	assert.Equal(t, []interface{}{202401, 202403}, params)
+
+	sql, params, err = buckets[1].Condition.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `"period_key" BETWEEN $1 AND $2`, sql)
+	assert.Equal(t, []interface{}{202404, 202405}, params)
+}
+
+func TestDateRangeCondition_BucketErrors(t *testing.T) {
+	_, err := NewDateRangeCondition(DateConfig{Type: Today}).Buckets(MonthGranularity)
+	assert.EqualError(t, err, "TODAY has day precision and can only be bucketed by DAY")
+
+	_, err = NewDateRangeCondition(DateConfig{Type: YTD}).Buckets("WEEK")
+	assert.EqualError(t, err, "invalid bucket granularity: WEEK")
+
+	_, err = NewDateRangeCondition(DateConfig{Type: BackMonth}).Buckets(MonthGranularity)
+	assert.EqualError(t, err, "parameters.months: months parameter required for BACK_MONTH")
+}
@@ -0,0 +1,20 @@
+package daterange
+
+import "time"
+
+// Clock supplies the current time relative periods are computed from
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock reads the system time
+var SystemClock Clock = systemClock{}
+
+// FixedClock always returns the same instant, e.g. for an "as of" report or tests
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time { return time.Time(c) }
@@ -0,0 +1,87 @@
+package daterange
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ComparisonType defines how a comparison range is derived from a date range
+type ComparisonType string
+
+const (
+	// PreviousPeriod is the range of the same length right before the date range.
+	// Example: YTD in May 2024 (January to May) compares with August to December 2023.
+	PreviousPeriod ComparisonType = "PREVIOUS_PERIOD"
+
+	// SamePeriodLastYear is the date range one year earlier.
+	// Example: QTD in May 2024 (April to May) compares with April to May 2023.
+	SamePeriodLastYear ComparisonType = "SAME_PERIOD_LAST_YEAR"
+
+	// PeriodsBack shifts the date range back by the 'Periods' number of months,
+	// or days for day precision types.
+	// Example: CURRENT_MONTH in May 2024 with Periods=3 compares with February 2024.
+	PeriodsBack ComparisonType = "PERIODS_BACK"
+)
+
+// Comparison describes the range a date range is compared with. Under a fiscal
+// calendar months and years are fiscal periods and fiscal years.
+type Comparison struct {
+	Type    ComparisonType `json:"type" yaml:"type"`
+	Periods int            `json:"periods,omitempty" yaml:"periods,omitempty"` // Used with PeriodsBack
+}
+
+// CompareTo returns a copy of the config that resolves to the comparison range
+// of this config, e.g. the same period last year. An existing comparison is replaced.
+func (c DateConfig) CompareTo(comparison Comparison) DateConfig {
+	c.Comparison = &comparison
+	return c
+}
+
+// CompareTo returns a condition on the comparison range of this condition. It
+// keeps the location and clock options, so both ranges are resolved from the
+// same point in time.
+func (d *DateRangeCondition) CompareTo(comparison Comparison) *DateRangeCondition {
+	return &DateRangeCondition{
+		DateConfig: d.DateConfig.CompareTo(comparison),
+		location:   d.location,
+		clock:      d.clock,
+	}
+}
+
+// shift moves the range back as described by comparison
+func (r periodRange) shift(comparison Comparison) (periodRange, error) {
+	if r.days {
+		switch comparison.Type {
+		case PreviousPeriod:
+			length := days(r.from, r.until)
+			r.from, r.until = r.from.AddDate(0, 0, -length), r.until.AddDate(0, 0, -length)
+		case SamePeriodLastYear:
+			r.from, r.until = r.from.AddDate(-1, 0, 0), r.until.AddDate(-1, 0, 0)
+		case PeriodsBack:
+			if comparison.Periods < 1 {
+				return periodRange{}, errors.New("positive periods required for PERIODS_BACK comparison")
+			}
+			r.from, r.until = r.from.AddDate(0, 0, -comparison.Periods), r.until.AddDate(0, 0, -comparison.Periods)
+		default:
+			return periodRange{}, fmt.Errorf("invalid comparison type: %s", comparison.Type)
+		}
+		return r, nil
+	}
+
+	var periods int
+	switch comparison.Type {
+	case PreviousPeriod:
+		periods = r.last - r.first + 1
+	case SamePeriodLastYear:
+		periods = 12
+	case PeriodsBack:
+		if comparison.Periods < 1 {
+			return periodRange{}, errors.New("positive periods required for PERIODS_BACK comparison")
+		}
+		periods = comparison.Periods
+	default:
+		return periodRange{}, fmt.Errorf("invalid comparison type: %s", comparison.Type)
+	}
+	r.first, r.last = r.first-periods, r.last-periods
+	return r, nil
+}
@@ -0,0 +1,124 @@
+package daterange
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangeCondition_CompareTo(t *testing.T) {
+	jakarta := mustLoad(t, DefaultTimezone)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, jakarta)
+	}
+	stringPointer := func(s string) *string { return &s }
+	may2024 := date(2024, 5, 15)
+
+	tests := []struct {
+		name          string
+		config        DateConfig
+		comparison    Comparison
+		expectedStart time.Time
+		expectedUntil time.Time
+	}{
+		{
+			name:          "YTD previous period",
+			config:        DateConfig{Type: YTD},
+			comparison:    Comparison{Type: PreviousPeriod},
+			expectedStart: date(2023, 8, 1),
+			expectedUntil: date(2024, 1, 1),
+		},
+		{
+			name:          "QTD same period last year",
+			config:        DateConfig{Type: QTD},
+			comparison:    Comparison{Type: SamePeriodLastYear},
+			expectedStart: date(2023, 4, 1),
+			expectedUntil: date(2023, 6, 1),
+		},
+		{
+			name:          "Current month three periods back",
+			config:        DateConfig{Type: CurrentMonth},
+			comparison:    Comparison{Type: PeriodsBack, Periods: 3},
+			expectedStart: date(2024, 2, 1),
+			expectedUntil: date(2024, 3, 1),
+		},
+		{
+			name:          "Fiscal YTD same period last year",
+			config:        DateConfig{Type: YTD, Fiscal: &FiscalCalendar{StartMonth: 7}},
+			comparison:    Comparison{Type: SamePeriodLastYear},
+			expectedStart: date(2022, 7, 1),
+			expectedUntil: date(2023, 6, 1),
+		},
+		{
+			name:          "Week based previous period",
+			config:        DateConfig{Type: CurrentMonth, Fiscal: &FiscalCalendar{WeekPattern: Pattern445}},
+			comparison:    Comparison{Type: PreviousPeriod},
+			expectedStart: date(2024, 3, 31),
+			expectedUntil: date(2024, 4, 28),
+		},
+		{
+			name:          "Last 7 days previous period",
+			config:        DateConfig{Type: LastNDays, Parameters: DateParameters{Days: intPointer(7)}},
+			comparison:    Comparison{Type: PreviousPeriod},
+			expectedStart: date(2024, 5, 2),
+			expectedUntil: date(2024, 5, 9),
+		},
+		{
+			name: "Date range same period last year",
+			config: DateConfig{Type: SpecificDateRange,
+				Parameters: DateParameters{StartDate: stringPointer("2024-03-01"), EndDate: stringPointer("2024-03-10")}},
+			comparison:    Comparison{Type: SamePeriodLastYear},
+			expectedStart: date(2023, 3, 1),
+			expectedUntil: date(2023, 3, 11),
+		},
+		{
+			name:          "Yesterday one day back",
+			config:        DateConfig{Type: Yesterday},
+			comparison:    Comparison{Type: PeriodsBack, Periods: 1},
+			expectedStart: date(2024, 5, 13),
+			expectedUntil: date(2024, 5, 14),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := NewDateRangeCondition(tt.config, WithAsOf(may2024))
+			startDate, endDate, err := current.CompareTo(tt.comparison).calculateDateRange()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStart, startDate)
+			assert.Equal(t, tt.expectedUntil.Add(-time.Second), endDate)
+
+			// The derived config resolves to the same range on its own
+			config := tt.config.CompareTo(tt.comparison)
+			startDate, endDate, err = NewDateRangeCondition(config, WithAsOf(may2024)).calculateDateRange()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStart, startDate)
+			assert.Equal(t, tt.expectedUntil.Add(-time.Second), endDate)
+		})
+	}
+}
+
+func TestDateConfig_ComparisonJSON(t *testing.T) {
+	var config DateConfig
+	require.NoError(t, json.Unmarshal([]byte(
+		`{"type":"CURRENT_MONTH","as_of":"2024-05-15","comparison":{"type":"SAME_PERIOD_LAST_YEAR"}}`), &config))
+
+	sql, params, err := NewDateRangeCondition(config).Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `"period_year" = $1 AND "period_month" BETWEEN $2 AND $3`, sql)
+	assert.Equal(t, []interface{}{2023, 5, 5}, params)
+}
+
+func TestDateRangeCondition_CompareToErrors(t *testing.T) {
+	current := NewDateRangeCondition(DateConfig{Type: CurrentMonth})
+
+	_, _, err := current.CompareTo(Comparison{Type: PeriodsBack}).calculateDateRange()
+	assert.EqualError(t, err, "comparison.periods: positive periods required for PERIODS_BACK comparison")
+
+	_, _, err = current.CompareTo(Comparison{Type: "NEXT_YEAR"}).calculateDateRange()
+	assert.EqualError(t, err, "comparison.type: invalid comparison type: NEXT_YEAR")
+}
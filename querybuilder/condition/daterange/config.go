@@ -23,7 +23,8 @@ const (
 	// Example: In April 2024, returns January 1, 2024 to April 30, 2024.
 	YTD DateConfigType = "YTD"
 
-	// PreviousYear represents the complete previous calendar year.
+	// PreviousYear represents the complete previous calendar year, or the
+	// previous fiscal year under a fiscal calendar.
 	// Used for year-over-year comparisons and annual reporting.
 	// Example: In 2024, returns January 1, 2023 to December 31, 2023.
 	PreviousYear DateConfigType = "PREVIOUS_YEAR"
@@ -57,6 +58,44 @@ const (
 	// Used for quarter-over-quarter comparisons and quarterly trend analysis.
 	// Example: In Q2 2024, returns January 1, 2024 to March 31, 2024 (Q1).
 	PreviousQuarter DateConfigType = "PREVIOUS_QUARTER"
+
+	// FiscalYear represents the complete current fiscal year.
+	// Without a fiscal calendar this is the current calendar year.
+	// Example: With a July fiscal year in April 2024, returns July 1, 2023 to June 30, 2024.
+	FiscalYear DateConfigType = "FISCAL_YEAR"
+)
+
+// Day precision types resolve to whole days instead of whole months. They can
+// only be used with the DATE column mode, since a range such as the last 7 days
+// cannot be expressed on year and month columns.
+const (
+	// Today represents the current day.
+	// Example: On April 15, 2024, returns April 15, 2024.
+	Today DateConfigType = "TODAY"
+
+	// Yesterday represents the day before the current day.
+	// Example: On April 15, 2024, returns April 14, 2024.
+	Yesterday DateConfigType = "YESTERDAY"
+
+	// LastNDays represents the given number of days ending with the current day.
+	// Requires a 'Days' parameter.
+	// Example: With Days=7 on April 15, 2024, returns April 9 to April 15, 2024.
+	LastNDays DateConfigType = "LAST_N_DAYS"
+
+	// WeekToDate represents the current week up to and including the current day.
+	// Weeks start on the 'WeekStart' parameter, Monday by default.
+	// Example: On Wednesday April 17, 2024, returns Monday April 15 to April 17, 2024.
+	WeekToDate DateConfigType = "WEEK_TO_DATE"
+
+	// PreviousWeek represents the complete week before the current week.
+	// Weeks start on the 'WeekStart' parameter, Monday by default.
+	// Example: On Wednesday April 17, 2024, returns Monday April 8 to Sunday April 14, 2024.
+	PreviousWeek DateConfigType = "PREVIOUS_WEEK"
+
+	// SpecificDateRange allows custom ranges with day precision.
+	// Requires 'StartDate' and 'EndDate' parameters (YYYY-MM-DD), both inclusive.
+	// Example: StartDate=2024-04-10, EndDate=2024-04-20 returns April 10 to April 20, 2024.
+	SpecificDateRange DateConfigType = "SPECIFIC_DATE_RANGE"
 )
 
 // DateParameters defines the configuration options for various date range calculations
@@ -66,39 +105,54 @@ type DateParameters struct {
 	// Months specifies the number of months to look back from the current month.
 	// Used with DateConfigType.BackMonth to retrieve data for a specific past month.
 	// Example: A value of 3 in April 2024 would target January 2024.
-	Months *int `json:"months,omitempty"`
+	Months *int `json:"months,omitempty" yaml:"months,omitempty"`
 
 	// StartBackMonths defines the starting point for a relative date range,
 	// expressed as the number of months back from the current month.
 	// Used with DateConfigType.RelativeRange in conjunction with EndBackMonths.
 	// Example: A value of 6 in April 2024 would start the range from October 2023.
-	StartBackMonths *int `json:"start_back_months,omitempty"`
+	StartBackMonths *int `json:"start_back_months,omitempty" yaml:"start_back_months,omitempty"`
 
 	// EndBackMonths defines the ending point for a relative date range,
 	// expressed as the number of months back from the current month.
 	// Used with DateConfigType.RelativeRange in conjunction with StartBackMonths.
 	// Example: A value of 3 in April 2024 would end the range at January 2024.
-	EndBackMonths *int `json:"end_back_months,omitempty"`
+	EndBackMonths *int `json:"end_back_months,omitempty" yaml:"end_back_months,omitempty"`
 
 	// Month specifies a particular month (1-12) for date range calculations.
 	// Used with DateConfigType.SpecificMonth along with Year to target a specific month.
 	// Example: A value of 3 represents March.
-	Month *int `json:"month,omitempty"`
+	Month *int `json:"month,omitempty" yaml:"month,omitempty"`
 
 	// Year specifies the target year for date range calculations.
 	// Used with DateConfigType.SpecificMonth along with Month to target a specific month.
 	// Example: A value of 2024 represents the year 2024.
-	Year *int `json:"year,omitempty"`
+	Year *int `json:"year,omitempty" yaml:"year,omitempty"`
 
 	// Start defines the beginning of a custom date range using a MonthYear structure.
 	// Used with DateConfigType.SpecificRange to set a precise starting point.
 	// The MonthYear structure contains both month and year values.
-	Start *MonthYear `json:"start,omitempty"`
+	Start *MonthYear `json:"start,omitempty" yaml:"start,omitempty"`
 
 	// End defines the conclusion of a custom date range using a MonthYear structure.
 	// Used with DateConfigType.SpecificRange to set a precise ending point.
 	// The MonthYear structure contains both month and year values.
-	End *MonthYear `json:"end,omitempty"`
+	End *MonthYear `json:"end,omitempty" yaml:"end,omitempty"`
+
+	// Days specifies the number of days, including the current day.
+	// Used with DateConfigType.LastNDays.
+	// Example: A value of 7 covers the current day and the 6 days before it.
+	Days *int `json:"days,omitempty" yaml:"days,omitempty"`
+
+	// WeekStart names the first day of the week, e.g. Sunday. Defaults to Monday.
+	// Used with DateConfigType.WeekToDate and DateConfigType.PreviousWeek.
+	WeekStart *string `json:"week_start,omitempty" yaml:"week_start,omitempty"`
+
+	// StartDate and EndDate define an inclusive range of days as YYYY-MM-DD.
+	// Used with DateConfigType.SpecificDateRange.
+	// Example: 2024-04-10 and 2024-04-20 cover 11 days.
+	StartDate *string `json:"start_date,omitempty" yaml:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty" yaml:"end_date,omitempty"`
 }
 
 // All fields in DateParameters are pointers to allow for optional values in JSON
@@ -107,11 +161,166 @@ type DateParameters struct {
 // intentionally omitted based on the selected DateConfigType.
 
 type MonthYear struct {
-	Month int `json:"month"`
-	Year  int `json:"year"`
+	Month int `json:"month" yaml:"month"`
+	Year  int `json:"year" yaml:"year"`
+}
+
+// ColumnMode describes how the filtered table stores its period
+type ColumnMode string
+
+const (
+	// YearMonthColumns filters separate integer year and month columns,
+	// period_year and period_month unless configured otherwise. This is the default.
+	YearMonthColumns ColumnMode = "YEAR_MONTH"
+
+	// DateColumn filters a date or timestamp column with a half-open range,
+	// e.g. posted_at >= 2024-01-01 AND posted_at < 2024-04-01. The bounds are
+	// passed as time.Time in the configured timezone.
+	DateColumn ColumnMode = "DATE"
+
+	// PeriodKeyColumn filters a single integer column holding yyyymm,
+	// e.g. period BETWEEN 202401 AND 202403.
+	PeriodKeyColumn ColumnMode = "YYYYMM"
+)
+
+// Default column names used in YearMonthColumns mode
+const (
+	DefaultYearColumn  = "period_year"
+	DefaultMonthColumn = "period_month"
+)
+
+// PeriodColumns configures the columns a date range is matched against
+type PeriodColumns struct {
+	// Mode selects how the period is stored. Defaults to YearMonthColumns.
+	Mode ColumnMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+
+	// Year and Month name the integer columns in YearMonthColumns mode.
+	// They default to period_year and period_month.
+	Year  string `json:"year,omitempty" yaml:"year,omitempty"`
+	Month string `json:"month,omitempty" yaml:"month,omitempty"`
+
+	// Column names the single column used in DateColumn and PeriodKeyColumn mode
+	Column string `json:"column,omitempty" yaml:"column,omitempty"`
+
+	// FiscalPeriods declares that the year and month or yyyymm columns hold
+	// fiscal year and period numbers. It is required to filter them under a
+	// week based fiscal calendar, whose periods are not calendar months.
+	FiscalPeriods bool `json:"fiscal_periods,omitempty" yaml:"fiscal_periods,omitempty"`
+}
+
+// PredicateStyle selects how a date range is turned into a predicate on the
+// year and month columns in YearMonthColumns mode
+type PredicateStyle string
+
+const (
+	// PredicateSplit compares year and month separately. A range spanning
+	// several years becomes a partial first year, the full years in between and
+	// a partial last year, e.g. for 2021-11 to 2024-02:
+	// ((period_year = 2021 AND period_month >= 11) OR (period_year BETWEEN 2022 AND 2023)
+	// OR (period_year = 2024 AND period_month <= 2)). This is the default.
+	PredicateSplit PredicateStyle = "SPLIT"
+
+	// PredicateComposite compares a single yyyymm key, e.g.
+	// (period_year * 100 + period_month) BETWEEN 202111 AND 202402. It is shorter
+	// for long spans but cannot use an index on the period columns.
+	PredicateComposite PredicateStyle = "COMPOSITE"
+)
+
+// WeekPattern is the number of weeks in the three periods of each fiscal quarter
+type WeekPattern string
+
+const (
+	Pattern445 WeekPattern = "4-4-5"
+	Pattern454 WeekPattern = "4-5-4"
+	Pattern544 WeekPattern = "5-4-4"
+)
+
+// YearEndRule decides on which day a week based fiscal year ends
+type YearEndRule string
+
+const (
+	// LastWeekday ends the fiscal year on the last year end weekday of the month
+	// before the start month. This is the default.
+	LastWeekday YearEndRule = "LAST"
+
+	// NearestWeekday ends the fiscal year on the year end weekday nearest to the
+	// end of the month before the start month, which may fall in the start month.
+	NearestWeekday YearEndRule = "NEAREST"
+)
+
+// FiscalCalendar describes a fiscal year. Every month based DateConfigType is resolved
+// against it: years, quarters and months become fiscal years, fiscal quarters
+// and fiscal periods.
+//
+// Without a week pattern the periods are calendar months and only the year
+// start moves, e.g. StartMonth 7 makes YTD in May 2024 run from July 2023.
+// With a week pattern the fiscal year has 52 or 53 weeks, numbered by the
+// calendar year it ends in, and is split into 12 periods of 4 or 5 weeks.
+// Parameters such as SPECIFIC_MONTH then refer to fiscal periods 1-12. Use the
+// DATE column mode, or columns holding fiscal year and period numbers marked
+// with PeriodColumns.FiscalPeriods: the default period_year and period_month
+// are calendar based and would match the wrong weeks.
+type FiscalCalendar struct {
+	// StartMonth is the first month of the fiscal year (1-12). Defaults to January.
+	StartMonth int `json:"start_month,omitempty" yaml:"start_month,omitempty"`
+
+	// WeekPattern switches to a 52/53-week calendar, e.g. 4-4-5
+	WeekPattern WeekPattern `json:"week_pattern,omitempty" yaml:"week_pattern,omitempty"`
+
+	// YearEndWeekday is the weekday a week based fiscal year ends on, e.g.
+	// Sunday. Defaults to Saturday.
+	YearEndWeekday string `json:"year_end_weekday,omitempty" yaml:"year_end_weekday,omitempty"`
+
+	// YearEnd picks the last or nearest year end weekday. Defaults to LastWeekday.
+	YearEnd YearEndRule `json:"year_end,omitempty" yaml:"year_end,omitempty"`
 }
 
+// AsOfLayout is the date format of DateConfig.AsOf
+const AsOfLayout = "2006-01-02"
+
 type DateConfig struct {
-	Type       DateConfigType `json:"type"`
-	Parameters DateParameters `json:"parameters"`
+	Type       DateConfigType `json:"type" yaml:"type"`
+	Parameters DateParameters `json:"parameters" yaml:"parameters"`
+
+	// Timezone is the IANA name of the zone periods are resolved in, e.g.
+	// Asia/Singapore. Defaults to Asia/Jakarta.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+
+	// AsOf is the reference date (YYYY-MM-DD) relative periods such as
+	// CURRENT_MONTH or YTD are computed from. Defaults to today.
+	AsOf string `json:"as_of,omitempty" yaml:"as_of,omitempty"`
+
+	// Predicate selects the predicate style. Defaults to PredicateSplit.
+	Predicate PredicateStyle `json:"predicate,omitempty" yaml:"predicate,omitempty"`
+
+	// Columns configures the filtered columns. Defaults to the period_year and
+	// period_month integer columns.
+	Columns *PeriodColumns `json:"columns,omitempty" yaml:"columns,omitempty"`
+
+	// Fiscal configures the fiscal calendar. Defaults to calendar years.
+	Fiscal *FiscalCalendar `json:"fiscal,omitempty" yaml:"fiscal,omitempty"`
+
+	// Comparison shifts the resolved range back, e.g. to the same period last
+	// year. Set it with CompareTo.
+	Comparison *Comparison `json:"comparison,omitempty" yaml:"comparison,omitempty"`
+}
+
+// columns returns the configured columns with defaults applied
+func (c DateConfig) columns() PeriodColumns {
+	var columns PeriodColumns
+	if c.Columns != nil {
+		columns = *c.Columns
+	}
+	if columns.Mode == "" {
+		columns.Mode = YearMonthColumns
+	}
+	if columns.Mode == YearMonthColumns {
+		if columns.Year == "" {
+			columns.Year = DefaultYearColumn
+		}
+		if columns.Month == "" {
+			columns.Month = DefaultMonthColumn
+		}
+	}
+	return columns
 }
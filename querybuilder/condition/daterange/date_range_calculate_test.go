@@ -21,8 +21,9 @@ func (dr DateRange) String() string {
 }
 
 func TestCalculateDateRange(t *testing.T) {
-	jakartaLocation := getJakartaLocation()
-	now := time.Now().In(jakartaLocation)
+	jakartaLocation, err := time.LoadLocation(DefaultTimezone)
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, jakartaLocation)
 	currentYear := now.Year()
 	currentMonth := int(now.Month())
 
@@ -49,7 +50,7 @@ func TestCalculateDateRange(t *testing.T) {
 				endDate:   time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation).AddDate(0, 1, 0).Add(-time.Second),
 			},
 			description: fmt.Sprintf("Should return the complete current month range (1-%s-%d to end of month)",
-				time.Now().Month().String(), currentYear),
+				now.Month().String(), currentYear),
 		},
 		{
 			name: "Back Month - One Month",
@@ -64,7 +65,7 @@ func TestCalculateDateRange(t *testing.T) {
 				endDate:   time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation).Add(-time.Second),
 			},
 			description: fmt.Sprintf("Should return the complete previous month range when looking back 1 month from %s %d",
-				time.Now().Month().String(), currentYear),
+				now.Month().String(), currentYear),
 		},
 		{
 			name: "Year to Date (YTD)",
@@ -98,7 +99,7 @@ func TestCalculateDateRange(t *testing.T) {
 				endDate:   time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation).AddDate(0, 1, 0).Add(-time.Second),
 			},
 			description: fmt.Sprintf("Should return from start of current quarter to end of %s %d",
-				time.Now().Month().String(), currentYear),
+				now.Month().String(), currentYear),
 		},
 		{
 			name: "Trailing Twelve Months (TTM)",
@@ -110,7 +111,7 @@ func TestCalculateDateRange(t *testing.T) {
 				endDate:   time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation).AddDate(0, 1, 0).Add(-time.Second),
 			},
 			description: fmt.Sprintf("Should return last 12 months ending with current month (%s %d)",
-				time.Now().Month().String(), currentYear),
+				now.Month().String(), currentYear),
 		},
 		{
 			name: "Month over Month (MoM)",
@@ -122,7 +123,7 @@ func TestCalculateDateRange(t *testing.T) {
 				endDate:   time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation).AddDate(0, 1, 0).Add(-time.Second),
 			},
 			description: fmt.Sprintf("Should return previous month when comparing month-over-month in %s %d",
-				time.Now().Month().String(), currentYear),
+				now.Month().String(), currentYear),
 		},
 		{
 			name: "Previous Quarter",
@@ -257,7 +258,7 @@ func TestCalculateDateRange(t *testing.T) {
 		for _, tt := range successTests {
 			t.Run(tt.name, func(t *testing.T) {
 				t.Log(tt.description)
-				dateRange := NewDateRangeCondition(tt.config)
+				dateRange := NewDateRangeCondition(tt.config, WithAsOf(now))
 				startDate, endDate, err := dateRange.calculateDateRange()
 				require.NoError(t, err)
 
@@ -287,7 +288,7 @@ func TestCalculateDateRange(t *testing.T) {
 		for _, tt := range failureTests {
 			t.Run(tt.name, func(t *testing.T) {
 				t.Log(tt.description)
-				dateRange := NewDateRangeCondition(tt.config)
+				dateRange := NewDateRangeCondition(tt.config, WithAsOf(now))
 				_, _, err := dateRange.calculateDateRange()
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedErr,
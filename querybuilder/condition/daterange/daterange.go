@@ -2,144 +2,419 @@ package daterange
 
 import (
 	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
-const jakartaTimezone = "Asia/Jakarta"
-
-func getJakartaLocation() *time.Location {
-	loc, _ := time.LoadLocation(jakartaTimezone)
-	return loc
-}
+// DefaultTimezone is used when neither an option nor the config sets a timezone
+const DefaultTimezone = "Asia/Jakarta"
 
 // DateRangeCondition specifically for date filtering using your existing date d.DateConfig
 type DateRangeCondition struct {
 	DateConfig DateConfig
+	location   *time.Location // Overrides DateConfig.Timezone
+	clock      Clock          // Overrides DateConfig.AsOf
 }
 
 // Verify interface implementation at compile time
 var _ querybuilder.QueryCondition = (*DateRangeCondition)(nil)
 
-func NewDateRangeCondition(config DateConfig) *DateRangeCondition {
-	return &DateRangeCondition{
+// Option configures a DateRangeCondition
+type Option func(*DateRangeCondition)
+
+// WithLocation resolves periods in the given location instead of the configured timezone
+func WithLocation(location *time.Location) Option {
+	return func(d *DateRangeCondition) {
+		d.location = location
+	}
+}
+
+// WithClock takes the current time from clock instead of the configured as-of date
+func WithClock(clock Clock) Option {
+	return func(d *DateRangeCondition) {
+		d.clock = clock
+	}
+}
+
+// WithAsOf computes relative periods as if the current time were asOf
+func WithAsOf(asOf time.Time) Option {
+	return WithClock(FixedClock(asOf))
+}
+
+// NewDateRangeCondition creates a condition without checking the config, an
+// invalid config fails Build. Use NewValidatedDateRangeCondition for configs
+// that come from user input.
+func NewDateRangeCondition(config DateConfig, opts ...Option) *DateRangeCondition {
+	d := &DateRangeCondition{
 		DateConfig: config,
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// NewValidatedDateRangeCondition creates a condition and validates its config,
+// returning every failure as FieldErrors, see DateConfig.Validate
+func NewValidatedDateRangeCondition(config DateConfig, opts ...Option) (*DateRangeCondition, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return NewDateRangeCondition(config, opts...), nil
+}
+
+// Location returns the location periods are resolved in: the WithLocation
+// option, then DateConfig.Timezone, then DefaultTimezone
+func (d *DateRangeCondition) Location() (*time.Location, error) {
+	if d.location != nil {
+		return d.location, nil
+	}
+	timezone := d.DateConfig.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return location, nil
 }
-func (d *DateRangeCondition) calculateDateRange() (startDate, endDate time.Time, err error) {
-	jakartaLocation := getJakartaLocation()
-	now := time.Now().In(jakartaLocation)
-	currentYear := now.Year()
-	currentMonth := int(now.Month())
+
+// now returns the reference time in location: the WithClock option, then
+// DateConfig.AsOf, then the system clock
+func (d *DateRangeCondition) now(location *time.Location) (time.Time, error) {
+	if d.clock != nil {
+		return d.clock.Now().In(location), nil
+	}
+	if d.DateConfig.AsOf != "" {
+		asOf, err := time.ParseInLocation(AsOfLayout, d.DateConfig.AsOf, location)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid as_of date %q, expected YYYY-MM-DD", d.DateConfig.AsOf)
+		}
+		return asOf, nil
+	}
+	return SystemClock.Now().In(location), nil
+}
+
+// Config returns the date config with the location and fixed clock set through
+// options folded in, so the condition can be serialized and rebuilt
+func (d *DateRangeCondition) Config() DateConfig {
+	config := d.DateConfig
+	if d.location != nil {
+		config.Timezone = d.location.String()
+	}
+	if fixed, ok := d.clock.(FixedClock); ok {
+		location, err := d.Location()
+		if err == nil {
+			config.AsOf = fixed.Now().In(location).Format(AsOfLayout)
+		}
+	}
+	return config
+}
+
+// periodRange is a resolved range of whole periods, first and last inclusive,
+// or of whole days from and before until for day precision types
+type periodRange struct {
+	calendar    calendar
+	first, last int
+
+	days        bool
+	from, until time.Time
+}
+
+func (r periodRange) start() time.Time {
+	if r.days {
+		return r.from
+	}
+	start, _ := r.calendar.bounds(r.first)
+	return start
+}
+
+func (r periodRange) end() time.Time {
+	if r.days {
+		return r.until
+	}
+	_, until := r.calendar.bounds(r.last)
+	return until
+}
+
+func (d *DateRangeCondition) resolvePeriods() (periodRange, error) {
+	location, err := d.Location()
+	if err != nil {
+		return periodRange{}, err
+	}
+	now, err := d.now(location)
+	if err != nil {
+		return periodRange{}, err
+	}
+	cal, err := newCalendar(d.DateConfig.Fiscal, location)
+	if err != nil {
+		return periodRange{}, err
+	}
+
+	current := cal.current(now)
+	params := d.DateConfig.Parameters
+	r := periodRange{calendar: cal}
 
 	switch d.DateConfig.Type {
 	case CurrentMonth:
-		startDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = startDate.AddDate(0, 1, 0).Add(-time.Second)
+		r.first, r.last = current, current
 
 	case BackMonth:
-		if d.DateConfig.Parameters.Months == nil {
-			return time.Time{}, time.Time{}, errors.New("months parameter required for BACK_MONTH")
+		if params.Months == nil {
+			return periodRange{}, errors.New("months parameter required for BACK_MONTH")
 		}
-		startDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		startDate = startDate.AddDate(0, -(*d.DateConfig.Parameters.Months), 0)
-		endDate = startDate.AddDate(0, 1, 0).Add(-time.Second)
+		r.first = current - *params.Months
+		r.last = r.first
 
 	case RelativeRange:
-		if d.DateConfig.Parameters.StartBackMonths == nil || d.DateConfig.Parameters.EndBackMonths == nil {
-			return time.Time{}, time.Time{}, errors.New("start_back_months and end_back_months required for RELATIVE_RANGE")
+		if params.StartBackMonths == nil || params.EndBackMonths == nil {
+			return periodRange{}, errors.New("start_back_months and end_back_months required for RELATIVE_RANGE")
 		}
-		startDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		startDate = startDate.AddDate(0, -(*d.DateConfig.Parameters.StartBackMonths), 0)
-		endDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = endDate.AddDate(0, -(*d.DateConfig.Parameters.EndBackMonths), 0).AddDate(0, 1, 0).Add(-time.Second)
+		r.first, r.last = current-*params.StartBackMonths, current-*params.EndBackMonths
 
 	case YTD:
-		startDate = time.Date(currentYear, 1, 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = endDate.AddDate(0, 1, 0).Add(-time.Second)
+		r.first, r.last = cal.yearStart(current), current
 
 	case PreviousYear:
-		startDate = time.Date(currentYear-1, 1, 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = time.Date(currentYear-1, 12, 31, 23, 59, 59, 0, jakartaLocation)
+		r.last = cal.yearStart(current) - 1
+		r.first = cal.yearStart(r.last)
+
+	case FiscalYear:
+		r.first = cal.yearStart(current)
+		r.last = r.first + 11
 
 	case SpecificMonth:
-		if d.DateConfig.Parameters.Month == nil || d.DateConfig.Parameters.Year == nil {
-			return time.Time{}, time.Time{}, errors.New("month and year parameters required for SPECIFIC_MONTH")
+		if params.Month == nil || params.Year == nil {
+			return periodRange{}, errors.New("month and year parameters required for SPECIFIC_MONTH")
 		}
-		startDate = time.Date(*d.DateConfig.Parameters.Year, time.Month(*d.DateConfig.Parameters.Month), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = startDate.AddDate(0, 1, 0).Add(-time.Second)
+		r.first = periodOf(MonthYear{Year: *params.Year, Month: *params.Month})
+		r.last = r.first
 
 	case SpecificRange:
-		if d.DateConfig.Parameters.Start == nil || d.DateConfig.Parameters.End == nil {
-			return time.Time{}, time.Time{}, errors.New("start and end parameters required for SPECIFIC_RANGE")
+		if params.Start == nil || params.End == nil {
+			return periodRange{}, errors.New("start and end parameters required for SPECIFIC_RANGE")
 		}
-		startDate = time.Date(d.DateConfig.Parameters.Start.Year, time.Month(d.DateConfig.Parameters.Start.Month), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = time.Date(d.DateConfig.Parameters.End.Year, time.Month(d.DateConfig.Parameters.End.Month), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = endDate.AddDate(0, 1, 0).Add(-time.Second)
+		r.first, r.last = periodOf(*params.Start), periodOf(*params.End)
+
 	case QTD:
-		currentQuarter := (time.Month(currentMonth)-1)/3 + 1
-		quarterStartMonth := time.Month((currentQuarter-1)*3 + 1)
-		startDate = time.Date(currentYear, quarterStartMonth, 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = endDate.AddDate(0, 1, 0).Add(-time.Second)
+		r.first, r.last = quarterStart(cal, current), current
+
 	case TTM:
-		startDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		startDate = startDate.AddDate(0, -11, 0)
-		endDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = endDate.AddDate(0, 1, 0).Add(-time.Second)
+		r.first, r.last = current-11, current
+
 	case MoM:
-		// Current month
-		endDate = time.Date(currentYear, time.Month(currentMonth), 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = endDate.AddDate(0, 1, 0).Add(-time.Second)
-		// Previous month
-		startDate = time.Date(currentYear, time.Month(currentMonth-1), 1, 0, 0, 0, 0, jakartaLocation)
+		// Previous month through current month
+		r.first, r.last = current-1, current
+
 	case PreviousQuarter:
-		currentQuarter := (time.Month(currentMonth)-1)/3 + 1
-		previousQuarter := currentQuarter - 1
-		previousQuarterYear := currentYear
+		r.last = quarterStart(cal, current) - 1
+		r.first = quarterStart(cal, r.last)
 
-		if previousQuarter == 0 {
-			previousQuarter = 4
-			previousQuarterYear--
+	case Today, Yesterday, LastNDays, WeekToDate, PreviousWeek, SpecificDateRange:
+		if r, err = resolveDays(d.DateConfig.Type, params, now); err != nil {
+			return periodRange{}, err
 		}
 
-		quarterStartMonth := time.Month((previousQuarter-1)*3 + 1)
-		startDate = time.Date(previousQuarterYear, quarterStartMonth, 1, 0, 0, 0, 0, jakartaLocation)
-		endDate = startDate.AddDate(0, 3, 0).Add(-time.Second)
 	default:
-		return time.Time{}, time.Time{}, errors.New("invalid date config type")
+		return periodRange{}, errors.New("invalid date config type")
 	}
 
-	return startDate, endDate, nil
+	if d.DateConfig.Comparison != nil {
+		return r.shift(*d.DateConfig.Comparison)
+	}
+	return r, nil
 }
-func (d *DateRangeCondition) Build(paramOffset int) (string, []interface{}, error) {
-	fmt.Printf("DateRangeCondition Build called with paramOffset: %d\n", paramOffset)
-	startDate, endDate, err := d.calculateDateRange()
+
+// resolveDays resolves the day precision types
+func resolveDays(configType DateConfigType, params DateParameters, now time.Time) (periodRange, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	r := periodRange{days: true}
+
+	switch configType {
+	case Today:
+		r.from, r.until = today, today.AddDate(0, 0, 1)
+
+	case Yesterday:
+		r.from, r.until = today.AddDate(0, 0, -1), today
+
+	case LastNDays:
+		if params.Days == nil || *params.Days < 1 {
+			return periodRange{}, errors.New("positive days parameter required for LAST_N_DAYS")
+		}
+		r.from, r.until = today.AddDate(0, 0, 1-*params.Days), today.AddDate(0, 0, 1)
+
+	case WeekToDate, PreviousWeek:
+		weekStart := time.Monday
+		if params.WeekStart != nil {
+			var ok bool
+			if weekStart, ok = parseWeekday(*params.WeekStart); !ok {
+				return periodRange{}, fmt.Errorf("invalid week_start: %s", *params.WeekStart)
+			}
+		}
+		weekBegin := today.AddDate(0, 0, -((int(today.Weekday()) - int(weekStart) + 7) % 7))
+		if configType == WeekToDate {
+			r.from, r.until = weekBegin, today.AddDate(0, 0, 1)
+		} else {
+			r.from, r.until = weekBegin.AddDate(0, 0, -7), weekBegin
+		}
+
+	case SpecificDateRange:
+		if params.StartDate == nil || params.EndDate == nil {
+			return periodRange{}, errors.New("start_date and end_date parameters required for SPECIFIC_DATE_RANGE")
+		}
+		startDate, err := time.ParseInLocation(AsOfLayout, *params.StartDate, now.Location())
+		if err != nil {
+			return periodRange{}, fmt.Errorf("invalid start_date %q, expected YYYY-MM-DD", *params.StartDate)
+		}
+		endDate, err := time.ParseInLocation(AsOfLayout, *params.EndDate, now.Location())
+		if err != nil {
+			return periodRange{}, fmt.Errorf("invalid end_date %q, expected YYYY-MM-DD", *params.EndDate)
+		}
+		if endDate.Before(startDate) {
+			return periodRange{}, errors.New("end_date must not be before start_date")
+		}
+		r.from, r.until = startDate, endDate.AddDate(0, 0, 1)
+	}
+
+	return r, nil
+}
+
+// calculateDateRange returns the resolved range with an inclusive end, the last second of the range
+func (d *DateRangeCondition) calculateDateRange() (startDate, endDate time.Time, err error) {
+	resolved, err := d.Resolve()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return resolved.Start, resolved.End.Add(-time.Second), nil
+}
+
+// ColumnMode returns the configured column mode, YearMonthColumns when unset
+func (d *DateRangeCondition) ColumnMode() ColumnMode {
+	return d.DateConfig.columns().Mode
+}
+
+// Columns returns the columns the condition filters on for the configured column mode
+func (d *DateRangeCondition) Columns() []string {
+	columns := d.DateConfig.columns()
+	switch columns.Mode {
+	case DateColumn, PeriodKeyColumn:
+		return []string{columns.Column}
+	default:
+		return []string{columns.Year, columns.Month}
+	}
+}
+
+func (d *DateRangeCondition) Build(dl dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	if err := d.DateConfig.Validate(); err != nil {
+		return "", nil, fmt.Errorf("invalid date range: %w", err)
+	}
+	resolved, err := d.resolve()
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to calculate date range: %w", err)
 	}
 
-	startYear, endYear := startDate.Year(), endDate.Year()
-	startMonth, endMonth := int(startDate.Month()), int(endDate.Month())
-	params := make([]interface{}, 0)
+	start, end := resolved.StartPeriod, resolved.EndPeriod
+	columns := d.DateConfig.columns()
+	if resolved.Granularity == DayGranularity && columns.Mode != DateColumn {
+		return "", nil, fmt.Errorf("%s has day precision and requires the %s column mode", d.DateConfig.Type, DateColumn)
+	}
+
+	switch columns.Mode {
+	case YearMonthColumns:
+		yearColumn, err := identifier.Quote(dl, columns.Year)
+		if err != nil {
+			return "", nil, err
+		}
+		monthColumn, err := identifier.Quote(dl, columns.Month)
+		if err != nil {
+			return "", nil, err
+		}
+		switch d.DateConfig.Predicate {
+		case "", PredicateSplit:
+			sql, params := splitPredicate(dl, paramOffset, yearColumn, monthColumn, start, end)
+			return sql, params, nil
+		case PredicateComposite:
+			sql, params := compositePredicate(dl, paramOffset, yearColumn, monthColumn, start, end)
+			return sql, params, nil
+		default:
+			return "", nil, fmt.Errorf("invalid date range predicate style: %s", d.DateConfig.Predicate)
+		}
+
+	case DateColumn:
+		column, err := quoteColumn(dl, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		// Half-open so every instant of the last day matches, whatever the column precision
+		return fmt.Sprintf(
+			`%s >= %s AND %s < %s`,
+			column, dl.Placeholder(paramOffset),
+			column, dl.Placeholder(paramOffset+1),
+		), []interface{}{resolved.Start, resolved.End}, nil
 
-	if startYear == endYear {
-		params = append(params, startYear, startMonth, endMonth)
+	case PeriodKeyColumn:
+		column, err := quoteColumn(dl, columns)
+		if err != nil {
+			return "", nil, err
+		}
 		return fmt.Sprintf(
-			"period_year = $%d AND period_month BETWEEN $%d AND $%d",
-			paramOffset,
-			paramOffset+1,
-			paramOffset+2,
-		), params, nil
+			`%s BETWEEN %s AND %s`,
+			column, dl.Placeholder(paramOffset), dl.Placeholder(paramOffset+1),
+		), []interface{}{start.Year*100 + start.Month, end.Year*100 + end.Month}, nil
+
+	default:
+		return "", nil, fmt.Errorf("invalid date range column mode: %s", columns.Mode)
 	}
-	params = append(params, startYear, startMonth, endYear, endMonth)
+}
+
+// quoteColumn quotes the single column used by the DATE and YYYYMM modes
+func quoteColumn(dl dialect.Dialect, columns PeriodColumns) (string, error) {
+	if columns.Column == "" {
+		return "", fmt.Errorf("column is required for date range column mode %s", columns.Mode)
+	}
+	return identifier.Quote(dl, columns.Column)
+}
+
+// splitPredicate compares the year and month columns separately. Ranges
+// within one year use a single BETWEEN on the month, longer ranges are an OR of
+// the partial first year, the full years in between and the partial last year.
+func splitPredicate(dl dialect.Dialect, paramOffset int, yearColumn, monthColumn string, start, end MonthYear) (string, []interface{}) {
+	if start.Year == end.Year {
+		return fmt.Sprintf(
+			`%s = %s AND %s BETWEEN %s AND %s`,
+			yearColumn, dl.Placeholder(paramOffset),
+			monthColumn, dl.Placeholder(paramOffset+1), dl.Placeholder(paramOffset+2),
+		), []interface{}{start.Year, start.Month, end.Month}
+	}
+
+	parts := []string{fmt.Sprintf(`(%s = %s AND %s >= %s)`,
+		yearColumn, dl.Placeholder(paramOffset), monthColumn, dl.Placeholder(paramOffset+1))}
+	params := []interface{}{start.Year, start.Month}
+
+	if end.Year-start.Year > 1 {
+		parts = append(parts, fmt.Sprintf(`(%s BETWEEN %s AND %s)`,
+			yearColumn, dl.Placeholder(paramOffset+len(params)), dl.Placeholder(paramOffset+len(params)+1)))
+		params = append(params, start.Year+1, end.Year-1)
+	}
+
+	parts = append(parts, fmt.Sprintf(`(%s = %s AND %s <= %s)`,
+		yearColumn, dl.Placeholder(paramOffset+len(params)), monthColumn, dl.Placeholder(paramOffset+len(params)+1)))
+	params = append(params, end.Year, end.Month)
+
+	// Parenthesized so the OR does not leak into the surrounding AND group
+	return "(" + strings.Join(parts, " OR ") + ")", params
+}
+
+// compositePredicate compares year * 100 + month against yyyymm keys
+func compositePredicate(dl dialect.Dialect, paramOffset int, yearColumn, monthColumn string, start, end MonthYear) (string, []interface{}) {
 	return fmt.Sprintf(
-		"(period_year = $%d AND period_month >= $%d) OR (period_year = $%d AND period_month <= $%d)",
-		paramOffset,
-		paramOffset+1,
-		paramOffset+2,
-		paramOffset+3,
-	), params, nil
+		`(%s * 100 + %s) BETWEEN %s AND %s`,
+		yearColumn, monthColumn,
+		dl.Placeholder(paramOffset), dl.Placeholder(paramOffset+1),
+	), []interface{}{start.Year*100 + start.Month, end.Year*100 + end.Month}
 }
@@ -0,0 +1,156 @@
+package daterange
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangeCondition_Days(t *testing.T) {
+	jakarta := mustLoad(t, DefaultTimezone)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, jakarta)
+	}
+	stringPointer := func(s string) *string { return &s }
+	dateColumn := &PeriodColumns{Mode: DateColumn, Column: "created_at"}
+	// Wednesday 17 April 2024, late in the day
+	wednesday := time.Date(2024, 4, 17, 23, 30, 0, 0, jakarta)
+
+	tests := []struct {
+		name          string
+		params        DateParameters
+		configType    DateConfigType
+		asOf          time.Time
+		expectedFrom  time.Time
+		expectedUntil time.Time
+	}{
+		{
+			name:          "Today",
+			configType:    Today,
+			asOf:          wednesday,
+			expectedFrom:  date(2024, 4, 17),
+			expectedUntil: date(2024, 4, 18),
+		},
+		{
+			name:          "Yesterday across a month",
+			configType:    Yesterday,
+			asOf:          date(2024, 3, 1),
+			expectedFrom:  date(2024, 2, 29),
+			expectedUntil: date(2024, 3, 1),
+		},
+		{
+			name:          "Last 7 days",
+			configType:    LastNDays,
+			params:        DateParameters{Days: intPointer(7)},
+			asOf:          wednesday,
+			expectedFrom:  date(2024, 4, 11),
+			expectedUntil: date(2024, 4, 18),
+		},
+		{
+			name:          "Week to date",
+			configType:    WeekToDate,
+			asOf:          wednesday,
+			expectedFrom:  date(2024, 4, 15),
+			expectedUntil: date(2024, 4, 18),
+		},
+		{
+			name:          "Week to date on the first day of the week",
+			configType:    WeekToDate,
+			asOf:          date(2024, 4, 15),
+			expectedFrom:  date(2024, 4, 15),
+			expectedUntil: date(2024, 4, 16),
+		},
+		{
+			name:          "Previous week",
+			configType:    PreviousWeek,
+			asOf:          wednesday,
+			expectedFrom:  date(2024, 4, 8),
+			expectedUntil: date(2024, 4, 15),
+		},
+		{
+			name:          "Previous week starting on Sunday",
+			configType:    PreviousWeek,
+			params:        DateParameters{WeekStart: stringPointer("Sunday")},
+			asOf:          wednesday,
+			expectedFrom:  date(2024, 4, 7),
+			expectedUntil: date(2024, 4, 14),
+		},
+		{
+			name:          "Specific date range",
+			configType:    SpecificDateRange,
+			params:        DateParameters{StartDate: stringPointer("2023-12-28"), EndDate: stringPointer("2024-01-03")},
+			asOf:          wednesday,
+			expectedFrom:  date(2023, 12, 28),
+			expectedUntil: date(2024, 1, 4),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DateConfig{Type: tt.configType, Parameters: tt.params, Columns: dateColumn}
+			sql, params, err := NewDateRangeCondition(config, WithAsOf(tt.asOf)).Build(dialect.Postgres, 2)
+			require.NoError(t, err)
+			assert.Equal(t, `"created_at" >= $2 AND "created_at" < $3`, sql)
+			assert.Equal(t, []interface{}{tt.expectedFrom, tt.expectedUntil}, params)
+		})
+	}
+}
+
+func TestDateRangeCondition_DaysErrors(t *testing.T) {
+	stringPointer := func(s string) *string { return &s }
+	dateColumn := &PeriodColumns{Mode: DateColumn, Column: "created_at"}
+
+	tests := []struct {
+		name        string
+		config      DateConfig
+		expectedErr string
+	}{
+		{
+			name:        "Year and month columns",
+			config:      DateConfig{Type: Today},
+			expectedErr: "TODAY has day precision and requires the DATE column mode",
+		},
+		{
+			name:        "YYYYMM column",
+			config:      DateConfig{Type: Yesterday, Columns: &PeriodColumns{Mode: PeriodKeyColumn, Column: "period"}},
+			expectedErr: "YESTERDAY has day precision and requires the DATE column mode",
+		},
+		{
+			name:        "Missing days",
+			config:      DateConfig{Type: LastNDays, Columns: dateColumn},
+			expectedErr: "positive days parameter required for LAST_N_DAYS",
+		},
+		{
+			name:        "Invalid week start",
+			config:      DateConfig{Type: WeekToDate, Parameters: DateParameters{WeekStart: stringPointer("Mon")}, Columns: dateColumn},
+			expectedErr: "invalid week_start: Mon",
+		},
+		{
+			name:        "Missing end date",
+			config:      DateConfig{Type: SpecificDateRange, Parameters: DateParameters{StartDate: stringPointer("2024-01-01")}, Columns: dateColumn},
+			expectedErr: "start_date and end_date parameters required for SPECIFIC_DATE_RANGE",
+		},
+		{
+			name: "Invalid start date",
+			config: DateConfig{Type: SpecificDateRange, Columns: dateColumn,
+				Parameters: DateParameters{StartDate: stringPointer("01/01/2024"), EndDate: stringPointer("2024-01-31")}},
+			expectedErr: `invalid start_date "01/01/2024", expected YYYY-MM-DD`,
+		},
+		{
+			name: "End before start",
+			config: DateConfig{Type: SpecificDateRange, Columns: dateColumn,
+				Parameters: DateParameters{StartDate: stringPointer("2024-02-01"), EndDate: stringPointer("2024-01-31")}},
+			expectedErr: "end_date must not be before start_date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewDateRangeCondition(tt.config).Build(dialect.Postgres, 1)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
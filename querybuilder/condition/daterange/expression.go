@@ -0,0 +1,343 @@
+package daterange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Period expressions are the compact form of a date config typed by analysts,
+// case insensitive:
+//
+//	this month, last month, 3 months ago       CURRENT_MONTH, BACK_MONTH
+//	last 3 months                              the 3 complete months before the current one
+//	6 months ago..3 months ago                 RELATIVE_RANGE
+//	ytd, qtd, ttm, mom                         YTD, QTD, TTM, MOM
+//	last year, last quarter                    PREVIOUS_YEAR, PREVIOUS_QUARTER
+//	this fiscal year, last fiscal year         FISCAL_YEAR, PREVIOUS_YEAR
+//	2024-03, Mar 2024                          SPECIFIC_MONTH
+//	2024, Q1 2024, FY2024, Q3 FY2024           SPECIFIC_RANGE over a year or quarter
+//	2024-01..2024-06                           SPECIFIC_RANGE
+//	today, yesterday, last 7 days              TODAY, YESTERDAY, LAST_N_DAYS
+//	wtd, last week [starting sunday]           WEEK_TO_DATE, PREVIOUS_WEEK
+//	2024-05-15, 2024-05-01..2024-05-15         SPECIFIC_DATE_RANGE
+//
+// FY years and quarters follow the fiscal calendar. With a week based calendar
+// the months of an expression are fiscal periods, as in DateParameters.
+
+var (
+	monthsAgoPattern     = regexp.MustCompile(`^(\d+) months? ago$`)
+	lastMonthsPattern    = regexp.MustCompile(`^last (\d+) months?$`)
+	lastDaysPattern      = regexp.MustCompile(`^last (\d+) days?$`)
+	yearPattern          = regexp.MustCompile(`^(\d{4})$`)
+	fiscalYearPattern    = regexp.MustCompile(`^fy ?(\d{4})$`)
+	quarterPattern       = regexp.MustCompile(`^q([1-4]) (\d{4})$`)
+	fiscalQuarterPattern = regexp.MustCompile(`^q([1-4]) fy ?(\d{4})$`)
+	monthPattern         = regexp.MustCompile(`^\d{4}-\d{2}$`)
+	datePattern          = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	weekStartingSuffix   = regexp.MustCompile(`^(.+) starting ([a-z]+)$`)
+)
+
+var expressionTypes = map[string]DateConfigType{
+	"this month":           CurrentMonth,
+	"current month":        CurrentMonth,
+	"ytd":                  YTD,
+	"year to date":         YTD,
+	"qtd":                  QTD,
+	"quarter to date":      QTD,
+	"ttm":                  TTM,
+	"ltm":                  TTM,
+	"mom":                  MoM,
+	"last year":            PreviousYear,
+	"previous year":        PreviousYear,
+	"last quarter":         PreviousQuarter,
+	"previous quarter":     PreviousQuarter,
+	"this fiscal year":     FiscalYear,
+	"current fiscal year":  FiscalYear,
+	"last fiscal year":     PreviousYear,
+	"previous fiscal year": PreviousYear,
+	"today":                Today,
+	"yesterday":            Yesterday,
+	"wtd":                  WeekToDate,
+	"week to date":         WeekToDate,
+	"last week":            PreviousWeek,
+	"previous week":        PreviousWeek,
+}
+
+// ParseExpression parses a period expression such as "last 3 months", "Q1 2024"
+// or "2024-01..2024-06" into a date config using the fiscal calendar, which
+// may be nil for calendar years
+func ParseExpression(expr string, fiscal *FiscalCalendar) (DateConfig, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(expr), " "))
+	cal, err := newCalendar(fiscal, time.UTC)
+	if err != nil {
+		return DateConfig{}, err
+	}
+
+	var config DateConfig
+	if from, to, ok := strings.Cut(normalized, ".."); ok {
+		config, err = parseRange(strings.TrimSpace(from), strings.TrimSpace(to))
+	} else {
+		config, err = parseSingle(normalized, cal)
+	}
+	if err != nil {
+		return DateConfig{}, fmt.Errorf("invalid period expression %q: %w", expr, err)
+	}
+
+	config.Fiscal = fiscal
+	if err := config.validate(false); err != nil {
+		return DateConfig{}, fmt.Errorf("invalid period expression %q: %w", expr, err)
+	}
+	return config, nil
+}
+
+func parseSingle(expr string, cal calendar) (DateConfig, error) {
+	if configType, ok := expressionTypes[expr]; ok {
+		return DateConfig{Type: configType}, nil
+	}
+
+	if match := weekStartingSuffix.FindStringSubmatch(expr); match != nil {
+		configType := expressionTypes[match[1]]
+		if configType != WeekToDate && configType != PreviousWeek {
+			return DateConfig{}, errors.New("only weeks take a starting weekday")
+		}
+		weekday, ok := parseWeekday(match[2])
+		if !ok {
+			return DateConfig{}, fmt.Errorf("unknown weekday %s", match[2])
+		}
+		weekStart := weekday.String()
+		return DateConfig{Type: configType, Parameters: DateParameters{WeekStart: &weekStart}}, nil
+	}
+
+	if months, ok := monthOffset(expr); ok {
+		return DateConfig{Type: BackMonth, Parameters: DateParameters{Months: &months}}, nil
+	}
+	if match := lastMonthsPattern.FindStringSubmatch(expr); match != nil {
+		months, _ := strconv.Atoi(match[1])
+		end := 1
+		return DateConfig{Type: RelativeRange, Parameters: DateParameters{StartBackMonths: &months, EndBackMonths: &end}}, nil
+	}
+	if match := lastDaysPattern.FindStringSubmatch(expr); match != nil {
+		days, _ := strconv.Atoi(match[1])
+		return DateConfig{Type: LastNDays, Parameters: DateParameters{Days: &days}}, nil
+	}
+
+	var first, last int
+	switch {
+	case yearPattern.MatchString(expr):
+		year, _ := strconv.Atoi(expr)
+		first, last = periodOf(MonthYear{Year: year, Month: 1}), periodOf(MonthYear{Year: year, Month: 12})
+	case fiscalYearPattern.MatchString(expr):
+		year, _ := strconv.Atoi(fiscalYearPattern.FindStringSubmatch(expr)[1])
+		first = fiscalYearStart(cal, year)
+		last = first + 11
+	case quarterPattern.MatchString(expr):
+		match := quarterPattern.FindStringSubmatch(expr)
+		quarter, _ := strconv.Atoi(match[1])
+		year, _ := strconv.Atoi(match[2])
+		first = periodOf(MonthYear{Year: year, Month: quarter*3 - 2})
+		last = first + 2
+	case fiscalQuarterPattern.MatchString(expr):
+		match := fiscalQuarterPattern.FindStringSubmatch(expr)
+		quarter, _ := strconv.Atoi(match[1])
+		year, _ := strconv.Atoi(match[2])
+		first = fiscalYearStart(cal, year) + (quarter-1)*3
+		last = first + 2
+	default:
+		if month, ok := parseMonth(expr); ok {
+			return DateConfig{Type: SpecificMonth, Parameters: DateParameters{Month: &month.Month, Year: &month.Year}}, nil
+		}
+		if datePattern.MatchString(expr) {
+			return DateConfig{Type: SpecificDateRange, Parameters: DateParameters{StartDate: &expr, EndDate: &expr}}, nil
+		}
+		return DateConfig{}, errors.New("unrecognized period")
+	}
+
+	start, end := periodLabel(first), periodLabel(last)
+	return DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &start, End: &end}}, nil
+}
+
+// parseRange parses both ends of a from..to expression, which must be of the same kind
+func parseRange(from, to string) (DateConfig, error) {
+	if datePattern.MatchString(from) && datePattern.MatchString(to) {
+		return DateConfig{Type: SpecificDateRange, Parameters: DateParameters{StartDate: &from, EndDate: &to}}, nil
+	}
+	if start, ok := parseMonth(from); ok {
+		if end, ok := parseMonth(to); ok {
+			return DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &start, End: &end}}, nil
+		}
+	}
+	if startBack, ok := monthOffset(from); ok {
+		if endBack, ok := monthOffset(to); ok {
+			return DateConfig{Type: RelativeRange, Parameters: DateParameters{StartBackMonths: &startBack, EndBackMonths: &endBack}}, nil
+		}
+	}
+	return DateConfig{}, errors.New("a range needs two dates, two months or two relative months")
+}
+
+// monthOffset parses a month relative to the current one, as the number of months back
+func monthOffset(expr string) (int, bool) {
+	switch expr {
+	case "this month", "current month":
+		return 0, true
+	case "last month", "previous month":
+		return 1, true
+	}
+	if match := monthsAgoPattern.FindStringSubmatch(expr); match != nil {
+		months, _ := strconv.Atoi(match[1])
+		return months, true
+	}
+	return 0, false
+}
+
+// parseMonth parses 2024-03, Mar 2024 or March 2024
+func parseMonth(expr string) (MonthYear, bool) {
+	if monthPattern.MatchString(expr) {
+		year, _ := strconv.Atoi(expr[:4])
+		month, _ := strconv.Atoi(expr[5:])
+		return MonthYear{Year: year, Month: month}, true
+	}
+	for _, layout := range []string{"Jan 2006", "January 2006"} {
+		if t, err := time.Parse(layout, expr); err == nil {
+			return MonthYear{Year: t.Year(), Month: int(t.Month())}, true
+		}
+	}
+	return MonthYear{}, false
+}
+
+// fiscalYearStart returns the first period of fiscal year, which contains
+// January of the year for month calendars and is period 1 for week calendars
+func fiscalYearStart(cal calendar, year int) int {
+	return cal.yearStart(periodOf(MonthYear{Year: year, Month: 1}))
+}
+
+// Expression formats the period of the config, its type and parameters, as the
+// canonical period expression. ParseExpression with the config's fiscal
+// calendar turns it back into the same type and parameters.
+func (c DateConfig) Expression() (string, error) {
+	if err := c.validate(false); err != nil {
+		return "", err
+	}
+	if c.Comparison != nil {
+		return "", errors.New("a compared period has no expression")
+	}
+
+	params := c.Parameters
+	switch c.Type {
+	case BackMonth:
+		if *params.Months == 1 {
+			return "last month", nil
+		}
+		return plural(*params.Months, "month") + " ago", nil
+	case RelativeRange:
+		if *params.EndBackMonths == 1 && *params.StartBackMonths > 0 {
+			return "last " + plural(*params.StartBackMonths, "month"), nil
+		}
+		return formatOffset(*params.StartBackMonths) + ".." + formatOffset(*params.EndBackMonths), nil
+	case SpecificMonth:
+		return formatMonth(MonthYear{Year: *params.Year, Month: *params.Month}), nil
+	case SpecificRange:
+		cal, err := newCalendar(c.Fiscal, time.UTC)
+		if err != nil {
+			return "", err
+		}
+		return formatRange(cal, periodOf(*params.Start), periodOf(*params.End)), nil
+	case LastNDays:
+		return "last " + plural(*params.Days, "day"), nil
+	case WeekToDate, PreviousWeek:
+		expr := "wtd"
+		if c.Type == PreviousWeek {
+			expr = "last week"
+		}
+		if params.WeekStart != nil {
+			expr += " starting " + strings.ToLower(*params.WeekStart)
+		}
+		return expr, nil
+	case SpecificDateRange:
+		if *params.StartDate == *params.EndDate {
+			return *params.StartDate, nil
+		}
+		return *params.StartDate + ".." + *params.EndDate, nil
+	}
+
+	// The first expression listed for each remaining type is its canonical form
+	for _, expr := range []string{"this month", "ytd", "qtd", "ttm", "mom", "last year", "last quarter",
+		"this fiscal year", "today", "yesterday"} {
+		if expressionTypes[expr] == c.Type {
+			return expr, nil
+		}
+	}
+	return "", fmt.Errorf("invalid date config type: %s", c.Type)
+}
+
+func formatOffset(months int) string {
+	switch months {
+	case 0:
+		return "this month"
+	case 1:
+		return "last month"
+	}
+	return plural(months, "month") + " ago"
+}
+
+func formatMonth(month MonthYear) string {
+	return fmt.Sprintf("%04d-%02d", month.Year, month.Month)
+}
+
+// formatRange names whole fiscal or calendar years and quarters, and spells out other ranges
+func formatRange(cal calendar, first, last int) string {
+	yearStart := cal.yearStart(first)
+	switch {
+	case first == yearStart && last == first+11:
+		return cal.yearName(first)
+	case first == quarterStart(cal, first) && last == first+2:
+		return fmt.Sprintf("Q%d %s", (first-yearStart)/3+1, cal.yearName(first))
+	}
+
+	start, end := periodLabel(first), periodLabel(last)
+	switch {
+	case start.Month == 1 && last == first+11:
+		return strconv.Itoa(start.Year)
+	case (start.Month-1)%3 == 0 && last == first+2:
+		return fmt.Sprintf("Q%d %d", (start.Month-1)/3+1, start.Year)
+	}
+	return formatMonth(start) + ".." + formatMonth(end)
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// UnmarshalJSON accepts a period expression string as well as the object form
+func (c *DateConfig) UnmarshalJSON(data []byte) error {
+	var expr string
+	if string(data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(data, &expr); err == nil {
+		*c, err = ParseExpression(expr, nil)
+		return err
+	}
+	type plain DateConfig
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// UnmarshalYAML accepts a period expression scalar as well as the mapping form
+func (c *DateConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var err error
+		*c, err = ParseExpression(node.Value, nil)
+		return err
+	}
+	type plain DateConfig
+	return node.Decode((*plain)(c))
+}
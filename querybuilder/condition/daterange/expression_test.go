@@ -0,0 +1,232 @@
+package daterange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseExpression(t *testing.T) {
+	stringPointer := func(s string) *string { return &s }
+	july := &FiscalCalendar{StartMonth: 7}
+	retail := &FiscalCalendar{WeekPattern: Pattern445}
+
+	tests := []struct {
+		expr      string
+		fiscal    *FiscalCalendar
+		expected  DateConfig
+		canonical string
+	}{
+		{
+			expr:      "This Month",
+			expected:  DateConfig{Type: CurrentMonth},
+			canonical: "this month",
+		},
+		{
+			expr:      "previous month",
+			expected:  DateConfig{Type: BackMonth, Parameters: DateParameters{Months: intPointer(1)}},
+			canonical: "last month",
+		},
+		{
+			expr:      "3 months ago",
+			expected:  DateConfig{Type: BackMonth, Parameters: DateParameters{Months: intPointer(3)}},
+			canonical: "3 months ago",
+		},
+		{
+			expr:      "last 3 months",
+			expected:  DateConfig{Type: RelativeRange, Parameters: DateParameters{StartBackMonths: intPointer(3), EndBackMonths: intPointer(1)}},
+			canonical: "last 3 months",
+		},
+		{
+			expr:      "6 months ago .. this month",
+			expected:  DateConfig{Type: RelativeRange, Parameters: DateParameters{StartBackMonths: intPointer(6), EndBackMonths: intPointer(0)}},
+			canonical: "6 months ago..this month",
+		},
+		{
+			expr:      "YTD",
+			expected:  DateConfig{Type: YTD},
+			canonical: "ytd",
+		},
+		{
+			expr:      "LTM",
+			expected:  DateConfig{Type: TTM},
+			canonical: "ttm",
+		},
+		{
+			expr:      "previous quarter",
+			expected:  DateConfig{Type: PreviousQuarter},
+			canonical: "last quarter",
+		},
+		{
+			expr:      "last fiscal year",
+			fiscal:    july,
+			expected:  DateConfig{Type: PreviousYear, Fiscal: july},
+			canonical: "last year",
+		},
+		{
+			expr:      "Mar 2024",
+			expected:  DateConfig{Type: SpecificMonth, Parameters: DateParameters{Month: intPointer(3), Year: intPointer(2024)}},
+			canonical: "2024-03",
+		},
+		{
+			expr:      "september 2023",
+			expected:  DateConfig{Type: SpecificMonth, Parameters: DateParameters{Month: intPointer(9), Year: intPointer(2023)}},
+			canonical: "2023-09",
+		},
+		{
+			expr:      "2024",
+			expected:  DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 1}, End: &MonthYear{Year: 2024, Month: 12}}},
+			canonical: "2024",
+		},
+		{
+			expr:      "FY2024",
+			expected:  DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 1}, End: &MonthYear{Year: 2024, Month: 12}}},
+			canonical: "2024",
+		},
+		{
+			expr:      "Q2 2024",
+			expected:  DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 4}, End: &MonthYear{Year: 2024, Month: 6}}},
+			canonical: "Q2 2024",
+		},
+		{
+			expr:   "FY2024",
+			fiscal: july,
+			expected: DateConfig{Type: SpecificRange, Fiscal: july,
+				Parameters: DateParameters{Start: &MonthYear{Year: 2023, Month: 7}, End: &MonthYear{Year: 2024, Month: 6}}},
+			canonical: "FY2024",
+		},
+		{
+			expr:   "q3 fy 2024",
+			fiscal: july,
+			expected: DateConfig{Type: SpecificRange, Fiscal: july,
+				Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 1}, End: &MonthYear{Year: 2024, Month: 3}}},
+			canonical: "Q3 FY2024",
+		},
+		{
+			// A calendar year under a July fiscal calendar stays a calendar year
+			expr:   "2024",
+			fiscal: july,
+			expected: DateConfig{Type: SpecificRange, Fiscal: july,
+				Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 1}, End: &MonthYear{Year: 2024, Month: 12}}},
+			canonical: "2024",
+		},
+		{
+			expr:   "FY2023",
+			fiscal: retail,
+			expected: DateConfig{Type: SpecificRange, Fiscal: retail,
+				Parameters: DateParameters{Start: &MonthYear{Year: 2023, Month: 1}, End: &MonthYear{Year: 2023, Month: 12}}},
+			canonical: "FY2023",
+		},
+		{
+			expr:      "2024-01..2024-06",
+			expected:  DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 1}, End: &MonthYear{Year: 2024, Month: 6}}},
+			canonical: "2024-01..2024-06",
+		},
+		{
+			expr:      "Nov 2023..Feb 2024",
+			expected:  DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &MonthYear{Year: 2023, Month: 11}, End: &MonthYear{Year: 2024, Month: 2}}},
+			canonical: "2023-11..2024-02",
+		},
+		{
+			expr:      "2024-03..2024-03",
+			expected:  DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 3}, End: &MonthYear{Year: 2024, Month: 3}}},
+			canonical: "2024-03..2024-03",
+		},
+		{
+			expr:      "last 7 days",
+			expected:  DateConfig{Type: LastNDays, Parameters: DateParameters{Days: intPointer(7)}},
+			canonical: "last 7 days",
+		},
+		{
+			expr:      "last week starting Sunday",
+			expected:  DateConfig{Type: PreviousWeek, Parameters: DateParameters{WeekStart: stringPointer("Sunday")}},
+			canonical: "last week starting sunday",
+		},
+		{
+			expr:      "week to date",
+			expected:  DateConfig{Type: WeekToDate},
+			canonical: "wtd",
+		},
+		{
+			expr:      "2024-05-15",
+			expected:  DateConfig{Type: SpecificDateRange, Parameters: DateParameters{StartDate: stringPointer("2024-05-15"), EndDate: stringPointer("2024-05-15")}},
+			canonical: "2024-05-15",
+		},
+		{
+			expr:      "2024-05-01..2024-05-15",
+			expected:  DateConfig{Type: SpecificDateRange, Parameters: DateParameters{StartDate: stringPointer("2024-05-01"), EndDate: stringPointer("2024-05-15")}},
+			canonical: "2024-05-01..2024-05-15",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			config, err := ParseExpression(tt.expr, tt.fiscal)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config)
+
+			canonical, err := config.Expression()
+			require.NoError(t, err)
+			assert.Equal(t, tt.canonical, canonical)
+
+			reparsed, err := ParseExpression(canonical, tt.fiscal)
+			require.NoError(t, err)
+			assert.Equal(t, config, reparsed)
+		})
+	}
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	tests := []struct {
+		expr        string
+		expectedErr string
+	}{
+		{"next month", `invalid period expression "next month": unrecognized period`},
+		{"2024-13", `invalid period expression "2024-13": parameters.month: month must be between 1 and 12, got 13`},
+		{"2024-06..2024-01", `invalid period expression "2024-06..2024-01": parameters.end: end must not be before start`},
+		{"2024-01..last month", `invalid period expression "2024-01..last month": a range needs two dates, two months or two relative months`},
+		{"ytd starting monday", `invalid period expression "ytd starting monday": only weeks take a starting weekday`},
+		{"last 0 days", `invalid period expression "last 0 days": parameters.days: positive days parameter required for LAST_N_DAYS`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseExpression(tt.expr, nil)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestDateConfig_ExpressionErrors(t *testing.T) {
+	_, err := DateConfig{Type: BackMonth}.Expression()
+	assert.EqualError(t, err, "parameters.months: months parameter required for BACK_MONTH")
+
+	_, err = DateConfig{Type: YTD}.CompareTo(Comparison{Type: PreviousPeriod}).Expression()
+	assert.EqualError(t, err, "a compared period has no expression")
+}
+
+func TestDateConfig_UnmarshalExpression(t *testing.T) {
+	expected := DateConfig{Type: SpecificRange, Parameters: DateParameters{Start: &MonthYear{Year: 2024, Month: 1}, End: &MonthYear{Year: 2024, Month: 3}}}
+
+	var fromJSON struct {
+		DateRange *DateConfig `json:"date_range"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"date_range": "Q1 2024"}`), &fromJSON))
+	assert.Equal(t, &expected, fromJSON.DateRange)
+
+	var fromYAML struct {
+		DateRange *DateConfig `yaml:"date_range"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(`date_range: Q1 2024`), &fromYAML))
+	assert.Equal(t, &expected, fromYAML.DateRange)
+
+	var object DateConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "BACK_MONTH", "parameters": {"months": 2}}`), &object))
+	assert.Equal(t, DateConfig{Type: BackMonth, Parameters: DateParameters{Months: intPointer(2)}}, object)
+
+	err := json.Unmarshal([]byte(`"next month"`), &object)
+	assert.EqualError(t, err, `invalid period expression "next month": unrecognized period`)
+}
@@ -0,0 +1,222 @@
+package daterange
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Periods are numbered year*12 + period-1, so consecutive periods have
+// consecutive numbers across year boundaries and a MonthYear maps directly to
+// a period. For calendar months the year and period are the calendar year and
+// month, for week based calendars the fiscal year and fiscal period.
+
+func periodOf(my MonthYear) int {
+	return my.Year*12 + my.Month - 1
+}
+
+func periodLabel(period int) MonthYear {
+	return MonthYear{Year: period / 12, Month: period%12 + 1}
+}
+
+// calendar maps period numbers to dates
+type calendar interface {
+	// current returns the period containing t
+	current(t time.Time) int
+	// bounds returns the first instant of period and of the period after it
+	bounds(period int) (start, until time.Time)
+	// yearStart returns the first period of the fiscal year containing period
+	yearStart(period int) int
+	// yearName names the fiscal year containing period, e.g. 2024 or FY2024
+	yearName(period int) string
+	// periodName names a single period, e.g. May 2024 or P5 FY2024
+	periodName(period int) string
+}
+
+// quarterStart returns the first period of the fiscal quarter containing period
+func quarterStart(cal calendar, period int) int {
+	first := cal.yearStart(period)
+	return first + (period-first)/3*3
+}
+
+// newCalendar builds the calendar described by fiscal. A nil fiscal calendar is
+// the plain calendar year.
+func newCalendar(fiscal *FiscalCalendar, location *time.Location) (calendar, error) {
+	if fiscal == nil {
+		return monthCalendar{startMonth: 1, location: location}, nil
+	}
+
+	startMonth := fiscal.StartMonth
+	if startMonth == 0 {
+		startMonth = 1
+	}
+	if startMonth < 1 || startMonth > 12 {
+		return nil, fmt.Errorf("invalid fiscal start month: %d", fiscal.StartMonth)
+	}
+	if fiscal.WeekPattern == "" {
+		return monthCalendar{startMonth: startMonth, location: location}, nil
+	}
+
+	weeks, ok := weekPatterns[fiscal.WeekPattern]
+	if !ok {
+		return nil, fmt.Errorf("invalid fiscal week pattern: %s", fiscal.WeekPattern)
+	}
+	weekday := time.Saturday
+	if fiscal.YearEndWeekday != "" {
+		if weekday, ok = parseWeekday(fiscal.YearEndWeekday); !ok {
+			return nil, fmt.Errorf("invalid fiscal year end weekday: %s", fiscal.YearEndWeekday)
+		}
+	}
+	switch fiscal.YearEnd {
+	case "", LastWeekday, NearestWeekday:
+	default:
+		return nil, fmt.Errorf("invalid fiscal year end rule: %s", fiscal.YearEnd)
+	}
+
+	endMonth := startMonth - 1
+	if endMonth == 0 {
+		endMonth = 12
+	}
+	return weekCalendar{
+		endMonth: time.Month(endMonth),
+		weekday:  weekday,
+		nearest:  fiscal.YearEnd == NearestWeekday,
+		weeks:    weeks,
+		location: location,
+	}, nil
+}
+
+var weekPatterns = map[WeekPattern][3]int{
+	Pattern445: {4, 4, 5},
+	Pattern454: {4, 5, 4},
+	Pattern544: {5, 4, 4},
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// monthCalendar uses calendar months, with the fiscal year starting in startMonth
+type monthCalendar struct {
+	startMonth int
+	location   *time.Location
+}
+
+func (c monthCalendar) current(t time.Time) int {
+	return periodOf(MonthYear{Year: t.Year(), Month: int(t.Month())})
+}
+
+func (c monthCalendar) bounds(period int) (start, until time.Time) {
+	label := periodLabel(period)
+	start = time.Date(label.Year, time.Month(label.Month), 1, 0, 0, 0, 0, c.location)
+	return start, start.AddDate(0, 1, 0)
+}
+
+func (c monthCalendar) yearStart(period int) int {
+	return period - (period%12-(c.startMonth-1)+12)%12
+}
+
+// yearName numbers fiscal years by the calendar year they end in
+func (c monthCalendar) yearName(period int) string {
+	lastMonth := periodLabel(c.yearStart(period) + 11)
+	if c.startMonth == 1 {
+		return fmt.Sprint(lastMonth.Year)
+	}
+	return fmt.Sprintf("FY%d", lastMonth.Year)
+}
+
+func (c monthCalendar) periodName(period int) string {
+	start, _ := c.bounds(period)
+	return start.Format("Jan 2006")
+}
+
+// weekCalendar is a 52/53-week calendar. The fiscal year ends on the last
+// weekday of endMonth, or the weekday nearest to the end of endMonth, and is
+// numbered by the calendar year it ends in. Each quarter is split into three
+// periods of weeks, and the extra week of a 53-week year goes to period 12.
+type weekCalendar struct {
+	endMonth time.Month
+	weekday  time.Weekday
+	nearest  bool
+	weeks    [3]int
+	location *time.Location
+}
+
+// yearEnd returns the last day of fiscal year
+func (c weekCalendar) yearEnd(year int) time.Time {
+	lastDay := time.Date(year, c.endMonth+1, 0, 0, 0, 0, 0, c.location)
+	if c.nearest {
+		diff := (int(c.weekday) - int(lastDay.Weekday()) + 7) % 7
+		if diff > 3 {
+			diff -= 7
+		}
+		return lastDay.AddDate(0, 0, diff)
+	}
+	return lastDay.AddDate(0, 0, -((int(lastDay.Weekday()) - int(c.weekday) + 7) % 7))
+}
+
+// periodWeeks returns the number of weeks in each period of fiscal year and its first day
+func (c weekCalendar) periodWeeks(year int) (start time.Time, weeks [12]int) {
+	start = c.yearEnd(year-1).AddDate(0, 0, 1)
+	until := c.yearEnd(year).AddDate(0, 0, 1)
+	for i := range weeks {
+		weeks[i] = c.weeks[i%3]
+	}
+	if days(start, until) == 53*7 {
+		weeks[11]++
+	}
+	return start, weeks
+}
+
+func (c weekCalendar) current(t time.Time) int {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.location)
+	year := t.Year()
+	for day.After(c.yearEnd(year)) {
+		year++
+	}
+	for !day.After(c.yearEnd(year - 1)) {
+		year--
+	}
+
+	start, weeks := c.periodWeeks(year)
+	period := 0
+	for ; period < 11; period++ {
+		start = start.AddDate(0, 0, weeks[period]*7)
+		if day.Before(start) {
+			break
+		}
+	}
+	return year*12 + period
+}
+
+func (c weekCalendar) bounds(period int) (start, until time.Time) {
+	label := periodLabel(period)
+	start, weeks := c.periodWeeks(label.Year)
+	for i := 0; i < label.Month-1; i++ {
+		start = start.AddDate(0, 0, weeks[i]*7)
+	}
+	return start, start.AddDate(0, 0, weeks[label.Month-1]*7)
+}
+
+func (c weekCalendar) yearStart(period int) int {
+	return period - period%12
+}
+
+func (c weekCalendar) yearName(period int) string {
+	return fmt.Sprintf("FY%d", periodLabel(period).Year)
+}
+
+func (c weekCalendar) periodName(period int) string {
+	label := periodLabel(period)
+	return fmt.Sprintf("P%d FY%d", label.Month, label.Year)
+}
+
+// days counts calendar days between two dates, ignoring DST changes
+func days(from, to time.Time) int {
+	return int(to.Sub(from).Round(24*time.Hour) / (24 * time.Hour))
+}
@@ -0,0 +1,230 @@
+package daterange
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangeCondition_FiscalMonths(t *testing.T) {
+	jakarta := mustLoad(t, DefaultTimezone)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, jakarta)
+	}
+	july := &FiscalCalendar{StartMonth: 7}
+	april := &FiscalCalendar{StartMonth: 4}
+	may2024 := date(2024, 5, 15)
+
+	tests := []struct {
+		name          string
+		config        DateConfig
+		asOf          time.Time
+		expectedStart time.Time
+		expectedUntil time.Time
+	}{
+		{
+			name:          "July YTD",
+			config:        DateConfig{Type: YTD, Fiscal: july},
+			asOf:          may2024,
+			expectedStart: date(2023, 7, 1),
+			expectedUntil: date(2024, 6, 1),
+		},
+		{
+			name:          "July QTD",
+			config:        DateConfig{Type: QTD, Fiscal: july},
+			asOf:          may2024,
+			expectedStart: date(2024, 4, 1),
+			expectedUntil: date(2024, 6, 1),
+		},
+		{
+			name:          "July previous quarter",
+			config:        DateConfig{Type: PreviousQuarter, Fiscal: july},
+			asOf:          date(2024, 8, 20),
+			expectedStart: date(2024, 4, 1),
+			expectedUntil: date(2024, 7, 1),
+		},
+		{
+			name:          "July previous year",
+			config:        DateConfig{Type: PreviousYear, Fiscal: july},
+			asOf:          may2024,
+			expectedStart: date(2022, 7, 1),
+			expectedUntil: date(2023, 7, 1),
+		},
+		{
+			name:          "July fiscal year",
+			config:        DateConfig{Type: FiscalYear, Fiscal: july},
+			asOf:          may2024,
+			expectedStart: date(2023, 7, 1),
+			expectedUntil: date(2024, 7, 1),
+		},
+		{
+			name:          "July previous fiscal year in the first month",
+			config:        DateConfig{Type: PreviousYear, Fiscal: july},
+			asOf:          date(2024, 7, 3),
+			expectedStart: date(2023, 7, 1),
+			expectedUntil: date(2024, 7, 1),
+		},
+		{
+			name:          "April YTD across the calendar year",
+			config:        DateConfig{Type: YTD, Fiscal: april},
+			asOf:          date(2024, 2, 10),
+			expectedStart: date(2023, 4, 1),
+			expectedUntil: date(2024, 3, 1),
+		},
+		{
+			name:          "April fiscal year",
+			config:        DateConfig{Type: FiscalYear, Fiscal: april},
+			asOf:          may2024,
+			expectedStart: date(2024, 4, 1),
+			expectedUntil: date(2025, 4, 1),
+		},
+		{
+			name:          "Calendar fiscal year",
+			config:        DateConfig{Type: FiscalYear},
+			asOf:          may2024,
+			expectedStart: date(2024, 1, 1),
+			expectedUntil: date(2025, 1, 1),
+		},
+		{
+			name:          "Months are unchanged",
+			config:        DateConfig{Type: MoM, Fiscal: april},
+			asOf:          may2024,
+			expectedStart: date(2024, 4, 1),
+			expectedUntil: date(2024, 6, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startDate, endDate, err := NewDateRangeCondition(tt.config, WithAsOf(tt.asOf)).calculateDateRange()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStart, startDate)
+			assert.Equal(t, tt.expectedUntil.Add(-time.Second), endDate)
+		})
+	}
+}
+
+func TestDateRangeCondition_FiscalWeeks(t *testing.T) {
+	jakarta := mustLoad(t, DefaultTimezone)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, jakarta)
+	}
+	retail := &FiscalCalendar{WeekPattern: Pattern445}
+	february := &FiscalCalendar{StartMonth: 2, WeekPattern: Pattern544, YearEndWeekday: "sunday", YearEnd: NearestWeekday}
+	dateColumn := &PeriodColumns{Mode: DateColumn, Column: "posted_at"}
+	fiscalColumns := &PeriodColumns{Year: "fiscal_year", Month: "fiscal_period", FiscalPeriods: true}
+
+	tests := []struct {
+		name           string
+		config         DateConfig
+		asOf           time.Time
+		expectedSQL    string
+		expectedParams []interface{}
+	}{
+		{
+			// FY2024 runs from Sunday 31 December 2023 to Saturday 28 December 2024
+			name:           "Current period",
+			config:         DateConfig{Type: CurrentMonth, Fiscal: retail, Columns: dateColumn},
+			asOf:           date(2024, 5, 15),
+			expectedSQL:    `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{date(2024, 4, 28), date(2024, 5, 26)},
+		},
+		{
+			name:           "Quarter to date",
+			config:         DateConfig{Type: QTD, Fiscal: retail, Columns: dateColumn},
+			asOf:           date(2024, 5, 15),
+			expectedSQL:    `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{date(2024, 3, 31), date(2024, 5, 26)},
+		},
+		{
+			name:           "Year to date",
+			config:         DateConfig{Type: YTD, Fiscal: retail, Columns: dateColumn},
+			asOf:           date(2024, 5, 15),
+			expectedSQL:    `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{date(2023, 12, 31), date(2024, 5, 26)},
+		},
+		{
+			name:           "Year to date matches fiscal period numbers",
+			config:         DateConfig{Type: YTD, Fiscal: retail, Columns: fiscalColumns},
+			asOf:           date(2024, 5, 15),
+			expectedSQL:    `"fiscal_year" = $1 AND "fiscal_period" BETWEEN $2 AND $3`,
+			expectedParams: []interface{}{2024, 1, 5},
+		},
+		{
+			name:           "Last days of December belong to the next fiscal year",
+			config:         DateConfig{Type: CurrentMonth, Fiscal: retail, Columns: fiscalColumns},
+			asOf:           date(2024, 12, 30),
+			expectedSQL:    `"fiscal_year" = $1 AND "fiscal_period" BETWEEN $2 AND $3`,
+			expectedParams: []interface{}{2025, 1, 1},
+		},
+		{
+			// FY2022 runs from 26 December 2021 to 31 December 2022, the 53rd week goes to period 12
+			name: "Extra week in a 53-week year",
+			config: DateConfig{Type: SpecificMonth, Fiscal: retail, Columns: dateColumn,
+				Parameters: DateParameters{Month: intPointer(12), Year: intPointer(2022)}},
+			asOf:           date(2024, 5, 15),
+			expectedSQL:    `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{date(2022, 11, 20), date(2023, 1, 1)},
+		},
+		{
+			name:           "Previous fiscal year of 53 weeks",
+			config:         DateConfig{Type: PreviousYear, Fiscal: retail, Columns: dateColumn},
+			asOf:           date(2023, 3, 1),
+			expectedSQL:    `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{date(2021, 12, 26), date(2023, 1, 1)},
+		},
+		{
+			// FY2024 ends on 28 January 2024, the Sunday nearest to Wednesday 31 January
+			name:           "February start, nearest Sunday, 5-4-4",
+			config:         DateConfig{Type: CurrentMonth, Columns: dateColumn, Fiscal: february},
+			asOf:           date(2024, 2, 20),
+			expectedSQL:    `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{date(2024, 1, 29), date(2024, 3, 4)},
+		},
+		{
+			// FY2025 ends on 2 February 2025, the Sunday nearest to Friday 31 January,
+			// which makes it a 53-week year
+			name:           "Nearest Sunday in the start month",
+			config:         DateConfig{Type: CurrentMonth, Columns: dateColumn, Fiscal: february},
+			asOf:           date(2025, 2, 1),
+			expectedSQL:    `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{date(2024, 12, 30), date(2025, 2, 3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params, err := NewDateRangeCondition(tt.config, WithAsOf(tt.asOf)).Build(dialect.Postgres, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+func TestDateRangeCondition_FiscalErrors(t *testing.T) {
+	tests := []struct {
+		fiscal      FiscalCalendar
+		expectedErr string
+	}{
+		{FiscalCalendar{StartMonth: 13}, "fiscal.start_month: invalid fiscal start month: 13"},
+		{FiscalCalendar{WeekPattern: "4-4-4"}, "fiscal.week_pattern: invalid fiscal week pattern: 4-4-4"},
+		{FiscalCalendar{WeekPattern: Pattern445, YearEndWeekday: "Funday"}, "fiscal.year_end_weekday: invalid fiscal year end weekday: Funday"},
+		{FiscalCalendar{WeekPattern: Pattern445, YearEnd: "FIRST"}, "fiscal.year_end: invalid fiscal year end rule: FIRST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedErr, func(t *testing.T) {
+			fiscal := tt.fiscal
+			_, _, err := NewDateRangeCondition(DateConfig{Type: YTD, Fiscal: &fiscal}).calculateDateRange()
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func intPointer(i int) *int {
+	return &i
+}
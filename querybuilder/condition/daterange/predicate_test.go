@@ -0,0 +1,215 @@
+package daterange
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangeCondition_Predicate(t *testing.T) {
+	specificRange := func(startYear, startMonth, endYear, endMonth int, style PredicateStyle) DateConfig {
+		return DateConfig{
+			Type: SpecificRange,
+			Parameters: DateParameters{
+				Start: &MonthYear{Year: startYear, Month: startMonth},
+				End:   &MonthYear{Year: endYear, Month: endMonth},
+			},
+			Predicate: style,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		config         DateConfig
+		paramOffset    int
+		dialect        dialect.Dialect
+		expectedSQL    string
+		expectedParams []interface{}
+	}{
+		{
+			name:           "Split one year",
+			config:         specificRange(2024, 1, 2024, 3, ""),
+			paramOffset:    1,
+			dialect:        dialect.Postgres,
+			expectedSQL:    `"period_year" = $1 AND "period_month" BETWEEN $2 AND $3`,
+			expectedParams: []interface{}{2024, 1, 3},
+		},
+		{
+			name:        "Split two years",
+			config:      specificRange(2023, 11, 2024, 2, PredicateSplit),
+			paramOffset: 1,
+			dialect:     dialect.Postgres,
+			expectedSQL: `(("period_year" = $1 AND "period_month" >= $2) OR ` +
+				`("period_year" = $3 AND "period_month" <= $4))`,
+			expectedParams: []interface{}{2023, 11, 2024, 2},
+		},
+		{
+			name:        "Split three years keeps the middle year",
+			config:      specificRange(2022, 1, 2024, 12, ""),
+			paramOffset: 1,
+			dialect:     dialect.Postgres,
+			expectedSQL: `(("period_year" = $1 AND "period_month" >= $2) OR ("period_year" BETWEEN $3 AND $4) OR ` +
+				`("period_year" = $5 AND "period_month" <= $6))`,
+			expectedParams: []interface{}{2022, 1, 2023, 2023, 2024, 12},
+		},
+		{
+			name:        "Split N years with offset",
+			config:      specificRange(2018, 7, 2024, 6, PredicateSplit),
+			paramOffset: 4,
+			dialect:     dialect.Postgres,
+			expectedSQL: `(("period_year" = $4 AND "period_month" >= $5) OR ("period_year" BETWEEN $6 AND $7) OR ` +
+				`("period_year" = $8 AND "period_month" <= $9))`,
+			expectedParams: []interface{}{2018, 7, 2019, 2023, 2024, 6},
+		},
+		{
+			name:        "Split N years in MySQL",
+			config:      specificRange(2020, 3, 2023, 9, PredicateSplit),
+			paramOffset: 1,
+			dialect:     dialect.MySQL,
+			expectedSQL: "((`period_year` = ? AND `period_month` >= ?) OR (`period_year` BETWEEN ? AND ?) OR " +
+				"(`period_year` = ? AND `period_month` <= ?))",
+			expectedParams: []interface{}{2020, 3, 2021, 2022, 2023, 9},
+		},
+		{
+			name:           "Composite one year",
+			config:         specificRange(2024, 1, 2024, 3, PredicateComposite),
+			paramOffset:    1,
+			dialect:        dialect.Postgres,
+			expectedSQL:    `("period_year" * 100 + "period_month") BETWEEN $1 AND $2`,
+			expectedParams: []interface{}{202401, 202403},
+		},
+		{
+			name:           "Composite two years",
+			config:         specificRange(2023, 11, 2024, 2, PredicateComposite),
+			paramOffset:    3,
+			dialect:        dialect.Postgres,
+			expectedSQL:    `("period_year" * 100 + "period_month") BETWEEN $3 AND $4`,
+			expectedParams: []interface{}{202311, 202402},
+		},
+		{
+			name:           "Composite N years",
+			config:         specificRange(2022, 1, 2024, 12, PredicateComposite),
+			paramOffset:    1,
+			dialect:        dialect.SQLServer,
+			expectedSQL:    `([period_year] * 100 + [period_month]) BETWEEN @p1 AND @p2`,
+			expectedParams: []interface{}{202201, 202412},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params, err := NewDateRangeCondition(tt.config).Build(tt.dialect, tt.paramOffset)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
+
+func TestDateRangeCondition_InvalidPredicate(t *testing.T) {
+	config := DateConfig{
+		Type: SpecificRange,
+		Parameters: DateParameters{
+			Start: &MonthYear{Year: 2023, Month: 1},
+			End:   &MonthYear{Year: 2024, Month: 1},
+		},
+		Predicate: "YEARLY",
+	}
+	_, _, err := NewDateRangeCondition(config).Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "invalid date range: predicate: invalid date range predicate style: YEARLY")
+}
+
+func TestDateRangeCondition_Columns(t *testing.T) {
+	jakarta, err := time.LoadLocation(DefaultTimezone)
+	require.NoError(t, err)
+	firstQuarter := DateParameters{
+		Start: &MonthYear{Year: 2024, Month: 1},
+		End:   &MonthYear{Year: 2024, Month: 3},
+	}
+
+	tests := []struct {
+		name           string
+		config         DateConfig
+		dialect        dialect.Dialect
+		expectedSQL    string
+		expectedParams []interface{}
+		expectedErr    string
+	}{
+		{
+			name: "Custom year and month columns",
+			config: DateConfig{Type: SpecificRange, Parameters: firstQuarter,
+				Columns: &PeriodColumns{Year: "t.fiscal_year", Month: "t.fiscal_month"}},
+			dialect:        dialect.Postgres,
+			expectedSQL:    `"t"."fiscal_year" = $1 AND "t"."fiscal_month" BETWEEN $2 AND $3`,
+			expectedParams: []interface{}{2024, 1, 3},
+		},
+		{
+			name: "Date column",
+			config: DateConfig{Type: SpecificRange, Parameters: firstQuarter,
+				Columns: &PeriodColumns{Mode: DateColumn, Column: "posted_at"}},
+			dialect:     dialect.Postgres,
+			expectedSQL: `"posted_at" >= $1 AND "posted_at" < $2`,
+			expectedParams: []interface{}{
+				time.Date(2024, 1, 1, 0, 0, 0, 0, jakarta),
+				time.Date(2024, 4, 1, 0, 0, 0, 0, jakarta),
+			},
+		},
+		{
+			name: "Date column across years",
+			config: DateConfig{Type: SpecificRange,
+				Parameters: DateParameters{Start: &MonthYear{Year: 2023, Month: 11}, End: &MonthYear{Year: 2024, Month: 12}},
+				Columns:    &PeriodColumns{Mode: DateColumn, Column: "period"}},
+			dialect:     dialect.MySQL,
+			expectedSQL: "`period` >= ? AND `period` < ?",
+			expectedParams: []interface{}{
+				time.Date(2023, 11, 1, 0, 0, 0, 0, jakarta),
+				time.Date(2025, 1, 1, 0, 0, 0, 0, jakarta),
+			},
+		},
+		{
+			name: "YYYYMM column",
+			config: DateConfig{Type: SpecificRange, Parameters: firstQuarter,
+				Columns: &PeriodColumns{Mode: PeriodKeyColumn, Column: "period"}},
+			dialect:        dialect.SQLServer,
+			expectedSQL:    `[period] BETWEEN @p1 AND @p2`,
+			expectedParams: []interface{}{202401, 202403},
+		},
+		{
+			name: "Missing column",
+			config: DateConfig{Type: SpecificRange, Parameters: firstQuarter,
+				Columns: &PeriodColumns{Mode: DateColumn}},
+			dialect:     dialect.Postgres,
+			expectedErr: "column is required for date range column mode DATE",
+		},
+		{
+			name: "Invalid column name",
+			config: DateConfig{Type: SpecificRange, Parameters: firstQuarter,
+				Columns: &PeriodColumns{Mode: PeriodKeyColumn, Column: "period; DROP TABLE t"}},
+			dialect:     dialect.Postgres,
+			expectedErr: "invalid identifier",
+		},
+		{
+			name: "Invalid mode",
+			config: DateConfig{Type: SpecificRange, Parameters: firstQuarter,
+				Columns: &PeriodColumns{Mode: "WEEK"}},
+			dialect:     dialect.Postgres,
+			expectedErr: "invalid date range column mode: WEEK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params, err := NewDateRangeCondition(tt.config).Build(tt.dialect, 1)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedParams, params)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package daterange
+
+import (
+	"fmt"
+	"time"
+)
+
+// Granularity is the unit a date range is resolved to
+type Granularity string
+
+const (
+	// MonthGranularity covers whole months, or whole fiscal periods under a week based fiscal calendar
+	MonthGranularity Granularity = "MONTH"
+	// DayGranularity covers whole days
+	DayGranularity Granularity = "DAY"
+)
+
+// ResolvedRange is the concrete range a DateRangeCondition filters on, e.g. to
+// show in a report header or to use in a cache key
+type ResolvedRange struct {
+	// Start is the first instant of the range
+	Start time.Time `json:"start"`
+	// End is the first instant after the range, so the range is Start <= t < End
+	End time.Time `json:"end"`
+
+	// StartPeriod and EndPeriod are the first and last period, calendar months
+	// or fiscal periods. Zero for day granularity.
+	StartPeriod MonthYear `json:"start_period"`
+	EndPeriod   MonthYear `json:"end_period"`
+
+	Granularity Granularity `json:"granularity"`
+
+	// Label describes the range, e.g. May 2024, Q2 FY2024 or 2024-05-09 - 2024-05-15
+	Label string `json:"label"`
+}
+
+// Resolve computes the range the condition filters on as of now, or as of the
+// configured clock or as-of date
+func (d *DateRangeCondition) Resolve() (ResolvedRange, error) {
+	if err := d.DateConfig.validate(false); err != nil {
+		return ResolvedRange{}, err
+	}
+	return d.resolve()
+}
+
+// resolve computes the range of a config that has already been validated
+func (d *DateRangeCondition) resolve() (ResolvedRange, error) {
+	r, err := d.resolvePeriods()
+	if err != nil {
+		return ResolvedRange{}, err
+	}
+
+	resolved := ResolvedRange{Start: r.start(), End: r.end()}
+	if r.days {
+		resolved.Granularity = DayGranularity
+		resolved.Label = dayLabel(resolved.Start, resolved.End)
+		return resolved, nil
+	}
+
+	resolved.Granularity = MonthGranularity
+	resolved.StartPeriod, resolved.EndPeriod = periodLabel(r.first), periodLabel(r.last)
+	resolved.Label = r.label()
+	return resolved, nil
+}
+
+// label names whole years and quarters, single periods, or the first and last period
+func (r periodRange) label() string {
+	yearStart := r.calendar.yearStart(r.first)
+	switch {
+	case r.first == yearStart && r.last == yearStart+11:
+		return r.calendar.yearName(r.first)
+	case r.first == quarterStart(r.calendar, r.first) && r.last == r.first+2:
+		return fmt.Sprintf("Q%d %s", (r.first-yearStart)/3+1, r.calendar.yearName(r.first))
+	case r.first == r.last:
+		return r.calendar.periodName(r.first)
+	default:
+		return r.calendar.periodName(r.first) + " - " + r.calendar.periodName(r.last)
+	}
+}
+
+func dayLabel(start, end time.Time) string {
+	last := end.AddDate(0, 0, -1)
+	if last.Equal(start) {
+		return start.Format(AsOfLayout)
+	}
+	return start.Format(AsOfLayout) + " - " + last.Format(AsOfLayout)
+}
@@ -0,0 +1,152 @@
+package daterange
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangeCondition_Resolve(t *testing.T) {
+	jakarta := mustLoad(t, DefaultTimezone)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, jakarta)
+	}
+	july := &FiscalCalendar{StartMonth: 7}
+	retail := &FiscalCalendar{WeekPattern: Pattern445}
+
+	tests := []struct {
+		name     string
+		config   DateConfig
+		expected ResolvedRange
+	}{
+		{
+			name:   "Single month",
+			config: DateConfig{Type: CurrentMonth},
+			expected: ResolvedRange{
+				Start: date(2024, 5, 1), End: date(2024, 6, 1),
+				StartPeriod: MonthYear{Year: 2024, Month: 5}, EndPeriod: MonthYear{Year: 2024, Month: 5},
+				Granularity: MonthGranularity, Label: "May 2024",
+			},
+		},
+		{
+			name:   "Partial quarter",
+			config: DateConfig{Type: QTD},
+			expected: ResolvedRange{
+				Start: date(2024, 4, 1), End: date(2024, 6, 1),
+				StartPeriod: MonthYear{Year: 2024, Month: 4}, EndPeriod: MonthYear{Year: 2024, Month: 5},
+				Granularity: MonthGranularity, Label: "Apr 2024 - May 2024",
+			},
+		},
+		{
+			name:   "Trailing twelve months",
+			config: DateConfig{Type: TTM},
+			expected: ResolvedRange{
+				Start: date(2023, 6, 1), End: date(2024, 6, 1),
+				StartPeriod: MonthYear{Year: 2023, Month: 6}, EndPeriod: MonthYear{Year: 2024, Month: 5},
+				Granularity: MonthGranularity, Label: "Jun 2023 - May 2024",
+			},
+		},
+		{
+			name:   "Whole quarter",
+			config: DateConfig{Type: PreviousQuarter},
+			expected: ResolvedRange{
+				Start: date(2024, 1, 1), End: date(2024, 4, 1),
+				StartPeriod: MonthYear{Year: 2024, Month: 1}, EndPeriod: MonthYear{Year: 2024, Month: 3},
+				Granularity: MonthGranularity, Label: "Q1 2024",
+			},
+		},
+		{
+			name:   "Whole year",
+			config: DateConfig{Type: PreviousYear},
+			expected: ResolvedRange{
+				Start: date(2023, 1, 1), End: date(2024, 1, 1),
+				StartPeriod: MonthYear{Year: 2023, Month: 1}, EndPeriod: MonthYear{Year: 2023, Month: 12},
+				Granularity: MonthGranularity, Label: "2023",
+			},
+		},
+		{
+			name:   "Fiscal quarter",
+			config: DateConfig{Type: PreviousQuarter, Fiscal: july},
+			expected: ResolvedRange{
+				Start: date(2024, 1, 1), End: date(2024, 4, 1),
+				StartPeriod: MonthYear{Year: 2024, Month: 1}, EndPeriod: MonthYear{Year: 2024, Month: 3},
+				Granularity: MonthGranularity, Label: "Q3 FY2024",
+			},
+		},
+		{
+			name:   "Fiscal year",
+			config: DateConfig{Type: FiscalYear, Fiscal: july},
+			expected: ResolvedRange{
+				Start: date(2023, 7, 1), End: date(2024, 7, 1),
+				StartPeriod: MonthYear{Year: 2023, Month: 7}, EndPeriod: MonthYear{Year: 2024, Month: 6},
+				Granularity: MonthGranularity, Label: "FY2024",
+			},
+		},
+		{
+			name:   "Week based period",
+			config: DateConfig{Type: CurrentMonth, Fiscal: retail},
+			expected: ResolvedRange{
+				Start: date(2024, 4, 28), End: date(2024, 5, 26),
+				StartPeriod: MonthYear{Year: 2024, Month: 5}, EndPeriod: MonthYear{Year: 2024, Month: 5},
+				Granularity: MonthGranularity, Label: "P5 FY2024",
+			},
+		},
+		{
+			name:   "Week based quarter",
+			config: DateConfig{Type: PreviousQuarter, Fiscal: retail},
+			expected: ResolvedRange{
+				Start: date(2023, 12, 31), End: date(2024, 3, 31),
+				StartPeriod: MonthYear{Year: 2024, Month: 1}, EndPeriod: MonthYear{Year: 2024, Month: 3},
+				Granularity: MonthGranularity, Label: "Q1 FY2024",
+			},
+		},
+		{
+			name:   "Single day",
+			config: DateConfig{Type: Today},
+			expected: ResolvedRange{
+				Start: date(2024, 5, 15), End: date(2024, 5, 16),
+				Granularity: DayGranularity, Label: "2024-05-15",
+			},
+		},
+		{
+			name:   "Days",
+			config: DateConfig{Type: LastNDays, Parameters: DateParameters{Days: intPointer(7)}},
+			expected: ResolvedRange{
+				Start: date(2024, 5, 9), End: date(2024, 5, 16),
+				Granularity: DayGranularity, Label: "2024-05-09 - 2024-05-15",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := NewDateRangeCondition(tt.config, WithAsOf(date(2024, 5, 15))).Resolve()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resolved)
+		})
+	}
+}
+
+func TestResolvedRange_JSON(t *testing.T) {
+	resolved, err := NewDateRangeCondition(DateConfig{Type: YTD, Timezone: "UTC", AsOf: "2024-05-15"}).Resolve()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(resolved)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"start": "2024-01-01T00:00:00Z",
+		"end": "2024-06-01T00:00:00Z",
+		"start_period": {"month": 1, "year": 2024},
+		"end_period": {"month": 5, "year": 2024},
+		"granularity": "MONTH",
+		"label": "Jan 2024 - May 2024"
+	}`, string(data))
+}
+
+func TestDateRangeCondition_ResolveError(t *testing.T) {
+	_, err := NewDateRangeCondition(DateConfig{Type: BackMonth}).Resolve()
+	assert.EqualError(t, err, "parameters.months: months parameter required for BACK_MONTH")
+}
@@ -0,0 +1,86 @@
+package daterange
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangeCondition_Timezone(t *testing.T) {
+	// 31 March 2024 20:00 UTC is already April in Jakarta and Singapore but still March in London
+	instant := time.Date(2024, 3, 31, 20, 0, 0, 0, time.UTC)
+	singapore, err := time.LoadLocation("Asia/Singapore")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		condition     *DateRangeCondition
+		expectedStart time.Time
+	}{
+		{
+			name:          "Default timezone",
+			condition:     NewDateRangeCondition(DateConfig{Type: CurrentMonth}, WithAsOf(instant)),
+			expectedStart: time.Date(2024, 4, 1, 0, 0, 0, 0, mustLoad(t, DefaultTimezone)),
+		},
+		{
+			name:          "Timezone from config",
+			condition:     NewDateRangeCondition(DateConfig{Type: CurrentMonth, Timezone: "Europe/London"}, WithAsOf(instant)),
+			expectedStart: time.Date(2024, 3, 1, 0, 0, 0, 0, mustLoad(t, "Europe/London")),
+		},
+		{
+			name: "Location option overrides config",
+			condition: NewDateRangeCondition(DateConfig{Type: CurrentMonth, Timezone: "Europe/London"},
+				WithAsOf(instant), WithLocation(singapore)),
+			expectedStart: time.Date(2024, 4, 1, 0, 0, 0, 0, singapore),
+		},
+		{
+			name:          "As-of date from config",
+			condition:     NewDateRangeCondition(DateConfig{Type: CurrentMonth, Timezone: "Europe/London", AsOf: "2024-02-10"}),
+			expectedStart: time.Date(2024, 2, 1, 0, 0, 0, 0, mustLoad(t, "Europe/London")),
+		},
+		{
+			name: "Clock option overrides as-of date",
+			condition: NewDateRangeCondition(DateConfig{Type: CurrentMonth, Timezone: "Europe/London", AsOf: "2024-02-10"},
+				WithClock(FixedClock(instant))),
+			expectedStart: time.Date(2024, 3, 1, 0, 0, 0, 0, mustLoad(t, "Europe/London")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startDate, _, err := tt.condition.calculateDateRange()
+			require.NoError(t, err)
+			assert.True(t, tt.expectedStart.Equal(startDate), "expected %s, got %s", tt.expectedStart, startDate)
+		})
+	}
+}
+
+func TestDateRangeCondition_TimezoneErrors(t *testing.T) {
+	_, _, err := NewDateRangeCondition(DateConfig{Type: CurrentMonth, Timezone: "Mars/Olympus"}).calculateDateRange()
+	assert.ErrorContains(t, err, `invalid timezone "Mars/Olympus"`)
+
+	_, _, err = NewDateRangeCondition(DateConfig{Type: CurrentMonth, AsOf: "31/03/2024"}).calculateDateRange()
+	assert.ErrorContains(t, err, `invalid as_of date "31/03/2024"`)
+}
+
+func TestDateConfig_JSONTimezone(t *testing.T) {
+	var config DateConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"YTD","timezone":"Asia/Singapore","as_of":"2024-06-30"}`), &config))
+	assert.Equal(t, "Asia/Singapore", config.Timezone)
+	assert.Equal(t, "2024-06-30", config.AsOf)
+
+	singapore := mustLoad(t, "Asia/Singapore")
+	condition := NewDateRangeCondition(DateConfig{Type: YTD},
+		WithLocation(singapore), WithAsOf(time.Date(2024, 6, 30, 23, 0, 0, 0, singapore)))
+	assert.Equal(t, DateConfig{Type: YTD, Timezone: "Asia/Singapore", AsOf: "2024-06-30"}, condition.Config())
+}
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return location
+}
@@ -0,0 +1,326 @@
+package daterange
+
+import (
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"dynamic-sqlbuilder/querybuilder/validation"
+	"fmt"
+	"time"
+)
+
+// ErrorKind classifies a date config validation failure
+type ErrorKind = validation.ErrorKind
+
+const (
+	MissingParameter    ErrorKind = "MISSING_PARAMETER"
+	InvalidParameter    ErrorKind = "INVALID_PARAMETER"
+	UnexpectedParameter ErrorKind = "UNEXPECTED_PARAMETER" // Set but not used by the type or mode
+	InvalidRange        ErrorKind = "INVALID_RANGE"        // Range ends before it starts
+)
+
+// FieldError identifies the config field that failed validation, its Field
+// is the JSON path, e.g. parameters.month
+type FieldError = validation.FieldError
+
+// FieldErrors collects every failure found in a date config
+type FieldErrors = validation.FieldErrors
+
+// typeRule lists the parameters a type uses and how to report them missing
+type typeRule struct {
+	parameters []string
+	required   string // Detail reported on each missing parameter, empty when none are required
+}
+
+var typeRules = map[DateConfigType]typeRule{
+	CurrentMonth:      {},
+	BackMonth:         {[]string{"months"}, "months parameter required for BACK_MONTH"},
+	RelativeRange:     {[]string{"start_back_months", "end_back_months"}, "start_back_months and end_back_months required for RELATIVE_RANGE"},
+	YTD:               {},
+	PreviousYear:      {},
+	SpecificMonth:     {[]string{"month", "year"}, "month and year parameters required for SPECIFIC_MONTH"},
+	SpecificRange:     {[]string{"start", "end"}, "start and end parameters required for SPECIFIC_RANGE"},
+	QTD:               {},
+	TTM:               {},
+	MoM:               {},
+	PreviousQuarter:   {},
+	FiscalYear:        {},
+	Today:             {},
+	Yesterday:         {},
+	LastNDays:         {[]string{"days"}, "positive days parameter required for LAST_N_DAYS"},
+	WeekToDate:        {parameters: []string{"week_start"}},
+	PreviousWeek:      {parameters: []string{"week_start"}},
+	SpecificDateRange: {[]string{"start_date", "end_date"}, "start_date and end_date parameters required for SPECIFIC_DATE_RANGE"},
+}
+
+// isDayPrecision reports whether the type resolves to whole days
+func isDayPrecision(configType DateConfigType) bool {
+	switch configType {
+	case Today, Yesterday, LastNDays, WeekToDate, PreviousWeek, SpecificDateRange:
+		return true
+	}
+	return false
+}
+
+// Validate checks the config without resolving it: the type, its required
+// parameters and their ranges, parameters the type does not use, and the
+// timezone, columns, fiscal calendar and comparison settings. Every failure
+// is reported, as FieldErrors. Build calls it first.
+func (c DateConfig) Validate() error {
+	return c.validate(true)
+}
+
+// validate checks the config, leaving out the column settings unless
+// columns is set. Resolve does not touch columns, so a day precision
+// config can be resolved whatever its columns.
+func (c DateConfig) validate(columns bool) error {
+	v := &validator{}
+
+	params := c.Parameters
+	set := map[string]bool{
+		"months":            params.Months != nil,
+		"start_back_months": params.StartBackMonths != nil,
+		"end_back_months":   params.EndBackMonths != nil,
+		"month":             params.Month != nil,
+		"year":              params.Year != nil,
+		"start":             params.Start != nil,
+		"end":               params.End != nil,
+		"days":              params.Days != nil,
+		"week_start":        params.WeekStart != nil,
+		"start_date":        params.StartDate != nil,
+		"end_date":          params.EndDate != nil,
+	}
+
+	rule, ok := typeRules[c.Type]
+	switch {
+	case c.Type == "":
+		v.fail(MissingParameter, "type", "date config type is required")
+	case !ok:
+		v.fail(InvalidParameter, "type", fmt.Sprintf("invalid date config type: %s", c.Type))
+	default:
+		used := make(map[string]bool, len(rule.parameters))
+		for _, name := range rule.parameters {
+			used[name] = true
+			if !set[name] && rule.required != "" {
+				v.fail(MissingParameter, "parameters."+name, rule.required)
+			}
+		}
+		// Map order is random, walk the fields in declaration order
+		for _, name := range []string{"months", "start_back_months", "end_back_months", "month", "year",
+			"start", "end", "days", "week_start", "start_date", "end_date"} {
+			if set[name] && !used[name] {
+				v.fail(UnexpectedParameter, "parameters."+name, fmt.Sprintf("%s is not used by %s", name, c.Type))
+			}
+		}
+	}
+
+	v.validateParameters(params, set)
+	v.validateTimezone(c)
+	if columns {
+		v.validateColumns(c)
+	}
+	v.validateFiscal(c.Fiscal)
+	v.validateComparison(c.Comparison)
+
+	if len(v.errors) > 0 {
+		return v.errors
+	}
+	return nil
+}
+
+type validator struct {
+	errors FieldErrors
+}
+
+func (v *validator) fail(kind ErrorKind, field, detail string) {
+	v.errors = append(v.errors, &FieldError{Kind: kind, Field: field, Detail: detail})
+}
+
+func (v *validator) validateParameters(params DateParameters, set map[string]bool) {
+	if set["months"] && *params.Months < 0 {
+		v.fail(InvalidParameter, "parameters.months", "must not be negative")
+	}
+	if set["start_back_months"] && *params.StartBackMonths < 0 {
+		v.fail(InvalidParameter, "parameters.start_back_months", "must not be negative")
+	}
+	if set["end_back_months"] && *params.EndBackMonths < 0 {
+		v.fail(InvalidParameter, "parameters.end_back_months", "must not be negative")
+	}
+	if set["start_back_months"] && set["end_back_months"] && *params.StartBackMonths < *params.EndBackMonths {
+		v.fail(InvalidRange, "parameters.start_back_months", "start_back_months must not be less than end_back_months")
+	}
+
+	if set["month"] {
+		v.validateMonth("parameters.month", *params.Month)
+	}
+	if set["year"] {
+		v.validateYear("parameters.year", *params.Year)
+	}
+	if set["start"] {
+		v.validateMonth("parameters.start.month", params.Start.Month)
+		v.validateYear("parameters.start.year", params.Start.Year)
+	}
+	if set["end"] {
+		v.validateMonth("parameters.end.month", params.End.Month)
+		v.validateYear("parameters.end.year", params.End.Year)
+	}
+	if set["start"] && set["end"] && periodOf(*params.End) < periodOf(*params.Start) {
+		v.fail(InvalidRange, "parameters.end", "end must not be before start")
+	}
+
+	if set["days"] && *params.Days < 1 {
+		v.fail(InvalidParameter, "parameters.days", "positive days parameter required for LAST_N_DAYS")
+	}
+	if set["week_start"] {
+		if _, ok := parseWeekday(*params.WeekStart); !ok {
+			v.fail(InvalidParameter, "parameters.week_start", fmt.Sprintf("invalid week_start: %s", *params.WeekStart))
+		}
+	}
+
+	startDate, startOK := v.parseDate("parameters.start_date", "start_date", params.StartDate)
+	endDate, endOK := v.parseDate("parameters.end_date", "end_date", params.EndDate)
+	if startOK && endOK && endDate.Before(startDate) {
+		v.fail(InvalidRange, "parameters.end_date", "end_date must not be before start_date")
+	}
+}
+
+func (v *validator) validateMonth(field string, month int) {
+	if month < 1 || month > 12 {
+		v.fail(InvalidParameter, field, fmt.Sprintf("month must be between 1 and 12, got %d", month))
+	}
+}
+
+func (v *validator) validateYear(field string, year int) {
+	if year < 1 || year > 9999 {
+		v.fail(InvalidParameter, field, fmt.Sprintf("year must be between 1 and 9999, got %d", year))
+	}
+}
+
+// parseDate parses an optional YYYY-MM-DD parameter, reporting whether it is set and valid
+func (v *validator) parseDate(field, name string, value *string) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(AsOfLayout, *value)
+	if err != nil {
+		v.fail(InvalidParameter, field, fmt.Sprintf("invalid %s %q, expected YYYY-MM-DD", name, *value))
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func (v *validator) validateTimezone(c DateConfig) {
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			v.fail(InvalidParameter, "timezone", fmt.Sprintf("invalid timezone %q", c.Timezone))
+		}
+	}
+	if c.AsOf != "" {
+		v.parseDate("as_of", "as_of date", &c.AsOf)
+	}
+}
+
+func (v *validator) validateColumns(c DateConfig) {
+	switch c.Predicate {
+	case "", PredicateSplit, PredicateComposite:
+	default:
+		v.fail(InvalidParameter, "predicate", fmt.Sprintf("invalid date range predicate style: %s", c.Predicate))
+	}
+
+	columns := c.columns()
+	names := map[string]string{}
+	switch columns.Mode {
+	case YearMonthColumns:
+		names["columns.year"], names["columns.month"] = columns.Year, columns.Month
+		if columns.Column != "" {
+			v.fail(UnexpectedParameter, "columns.column", fmt.Sprintf("column is not used by the %s mode", columns.Mode))
+		}
+	case DateColumn, PeriodKeyColumn:
+		names["columns.column"] = columns.Column
+		if columns.Column == "" {
+			v.fail(MissingParameter, "columns.column", fmt.Sprintf("column is required for date range column mode %s", columns.Mode))
+		}
+		if columns.Year != "" || columns.Month != "" {
+			v.fail(UnexpectedParameter, "columns", fmt.Sprintf("year and month are not used by the %s mode", columns.Mode))
+		}
+		if c.Predicate != "" {
+			v.fail(UnexpectedParameter, "predicate", fmt.Sprintf("predicate is not used by the %s mode", columns.Mode))
+		}
+	default:
+		v.fail(InvalidParameter, "columns.mode", fmt.Sprintf("invalid date range column mode: %s", columns.Mode))
+	}
+
+	for _, field := range []string{"columns.year", "columns.month", "columns.column"} {
+		if name := names[field]; name != "" {
+			if _, err := identifier.Parse(name); err != nil {
+				v.fail(InvalidParameter, field, err.Error())
+			}
+		}
+	}
+
+	weekCalendar := c.Fiscal != nil && c.Fiscal.WeekPattern != ""
+	switch {
+	case columns.FiscalPeriods && columns.Mode == DateColumn:
+		v.fail(UnexpectedParameter, "columns.fiscal_periods", fmt.Sprintf("fiscal_periods is not used by the %s mode", columns.Mode))
+	case columns.FiscalPeriods && !weekCalendar:
+		v.fail(UnexpectedParameter, "columns.fiscal_periods", "only used with a fiscal week_pattern")
+	case weekCalendar && columns.Mode != DateColumn && !columns.FiscalPeriods:
+		v.fail(InvalidParameter, "columns.fiscal_periods",
+			fmt.Sprintf("a week based fiscal calendar resolves to fiscal periods, set fiscal_periods if the %s columns hold them or use the %s mode", columns.Mode, DateColumn))
+	}
+
+	if isDayPrecision(c.Type) && columns.Mode != DateColumn {
+		v.fail(InvalidParameter, "columns.mode",
+			fmt.Sprintf("%s has day precision and requires the %s column mode", c.Type, DateColumn))
+	}
+}
+
+func (v *validator) validateFiscal(fiscal *FiscalCalendar) {
+	if fiscal == nil {
+		return
+	}
+	if fiscal.StartMonth < 0 || fiscal.StartMonth > 12 {
+		v.fail(InvalidParameter, "fiscal.start_month", fmt.Sprintf("invalid fiscal start month: %d", fiscal.StartMonth))
+	}
+	if fiscal.WeekPattern == "" {
+		if fiscal.YearEndWeekday != "" {
+			v.fail(UnexpectedParameter, "fiscal.year_end_weekday", "only used with a week_pattern")
+		}
+		if fiscal.YearEnd != "" {
+			v.fail(UnexpectedParameter, "fiscal.year_end", "only used with a week_pattern")
+		}
+		return
+	}
+
+	if _, ok := weekPatterns[fiscal.WeekPattern]; !ok {
+		v.fail(InvalidParameter, "fiscal.week_pattern", fmt.Sprintf("invalid fiscal week pattern: %s", fiscal.WeekPattern))
+	}
+	if fiscal.YearEndWeekday != "" {
+		if _, ok := parseWeekday(fiscal.YearEndWeekday); !ok {
+			v.fail(InvalidParameter, "fiscal.year_end_weekday", fmt.Sprintf("invalid fiscal year end weekday: %s", fiscal.YearEndWeekday))
+		}
+	}
+	switch fiscal.YearEnd {
+	case "", LastWeekday, NearestWeekday:
+	default:
+		v.fail(InvalidParameter, "fiscal.year_end", fmt.Sprintf("invalid fiscal year end rule: %s", fiscal.YearEnd))
+	}
+}
+
+func (v *validator) validateComparison(comparison *Comparison) {
+	if comparison == nil {
+		return
+	}
+	switch comparison.Type {
+	case PreviousPeriod, SamePeriodLastYear:
+		if comparison.Periods != 0 {
+			v.fail(UnexpectedParameter, "comparison.periods", fmt.Sprintf("periods is not used by %s", comparison.Type))
+		}
+	case PeriodsBack:
+		if comparison.Periods < 1 {
+			v.fail(InvalidParameter, "comparison.periods", "positive periods required for PERIODS_BACK comparison")
+		}
+	case "":
+		v.fail(MissingParameter, "comparison.type", "comparison type is required")
+	default:
+		v.fail(InvalidParameter, "comparison.type", fmt.Sprintf("invalid comparison type: %s", comparison.Type))
+	}
+}
@@ -0,0 +1,220 @@
+package daterange
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected FieldErrors
+	}{
+		{
+			name:   "Valid specific month",
+			config: `{"type": "SPECIFIC_MONTH", "parameters": {"month": 3, "year": 2024}}`,
+		},
+		{
+			name: "Valid day range with a date column",
+			config: `{"type": "SPECIFIC_DATE_RANGE", "parameters": {"start_date": "2024-05-01", "end_date": "2024-05-15"},
+				"columns": {"mode": "DATE", "column": "posted_at"}}`,
+		},
+		{
+			name:   "Valid week to date without week start",
+			config: `{"type": "WEEK_TO_DATE", "columns": {"mode": "DATE", "column": "posted_at"}}`,
+		},
+		{
+			name:   "Missing type",
+			config: `{}`,
+			expected: FieldErrors{
+				{Kind: MissingParameter, Field: "type", Detail: "date config type is required"},
+			},
+		},
+		{
+			name:   "Unknown type",
+			config: `{"type": "NEXT_MONTH"}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "type", Detail: "invalid date config type: NEXT_MONTH"},
+			},
+		},
+		{
+			name:   "Missing parameters",
+			config: `{"type": "SPECIFIC_MONTH", "parameters": {"year": 2024}}`,
+			expected: FieldErrors{
+				{Kind: MissingParameter, Field: "parameters.month", Detail: "month and year parameters required for SPECIFIC_MONTH"},
+			},
+		},
+		{
+			name:   "Parameters the type does not use",
+			config: `{"type": "CURRENT_MONTH", "parameters": {"months": 3, "start_date": "2024-01-01"}}`,
+			expected: FieldErrors{
+				{Kind: UnexpectedParameter, Field: "parameters.months", Detail: "months is not used by CURRENT_MONTH"},
+				{Kind: UnexpectedParameter, Field: "parameters.start_date", Detail: "start_date is not used by CURRENT_MONTH"},
+			},
+		},
+		{
+			name:   "Out of range month and year",
+			config: `{"type": "SPECIFIC_MONTH", "parameters": {"month": 13, "year": 0}}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "parameters.month", Detail: "month must be between 1 and 12, got 13"},
+				{Kind: InvalidParameter, Field: "parameters.year", Detail: "year must be between 1 and 9999, got 0"},
+			},
+		},
+		{
+			name:   "Reversed relative range",
+			config: `{"type": "RELATIVE_RANGE", "parameters": {"start_back_months": 1, "end_back_months": 3}}`,
+			expected: FieldErrors{
+				{Kind: InvalidRange, Field: "parameters.start_back_months", Detail: "start_back_months must not be less than end_back_months"},
+			},
+		},
+		{
+			name: "Reversed specific range",
+			config: `{"type": "SPECIFIC_RANGE", "parameters": {"start": {"month": 6, "year": 2024},
+				"end": {"month": 1, "year": 2024}}}`,
+			expected: FieldErrors{
+				{Kind: InvalidRange, Field: "parameters.end", Detail: "end must not be before start"},
+			},
+		},
+		{
+			name: "Malformed dates",
+			config: `{"type": "SPECIFIC_DATE_RANGE", "parameters": {"start_date": "01/05/2024", "end_date": "2024-05-15"},
+				"columns": {"mode": "DATE", "column": "posted_at"}}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "parameters.start_date", Detail: `invalid start_date "01/05/2024", expected YYYY-MM-DD`},
+			},
+		},
+		{
+			name:   "Non positive days and unknown week start",
+			config: `{"type": "LAST_N_DAYS", "parameters": {"days": 0, "week_start": "Funday"}, "columns": {"mode": "DATE", "column": "posted_at"}}`,
+			expected: FieldErrors{
+				{Kind: UnexpectedParameter, Field: "parameters.week_start", Detail: "week_start is not used by LAST_N_DAYS"},
+				{Kind: InvalidParameter, Field: "parameters.days", Detail: "positive days parameter required for LAST_N_DAYS"},
+				{Kind: InvalidParameter, Field: "parameters.week_start", Detail: "invalid week_start: Funday"},
+			},
+		},
+		{
+			name:   "Day precision needs a date column",
+			config: `{"type": "TODAY"}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "columns.mode", Detail: "TODAY has day precision and requires the DATE column mode"},
+			},
+		},
+		{
+			name:   "Timezone and as of",
+			config: `{"type": "YTD", "timezone": "Mars/Olympus", "as_of": "yesterday"}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "timezone", Detail: `invalid timezone "Mars/Olympus"`},
+				{Kind: InvalidParameter, Field: "as_of", Detail: `invalid as_of date "yesterday", expected YYYY-MM-DD`},
+			},
+		},
+		{
+			name:   "Column settings",
+			config: `{"type": "YTD", "predicate": "COMPOSITE", "columns": {"mode": "YYYYMM", "month": "m"}}`,
+			expected: FieldErrors{
+				{Kind: MissingParameter, Field: "columns.column", Detail: "column is required for date range column mode YYYYMM"},
+				{Kind: UnexpectedParameter, Field: "columns", Detail: "year and month are not used by the YYYYMM mode"},
+				{Kind: UnexpectedParameter, Field: "predicate", Detail: "predicate is not used by the YYYYMM mode"},
+			},
+		},
+		{
+			name:   "Unsafe column name",
+			config: `{"type": "YTD", "columns": {"mode": "DATE", "column": "posted_at; DROP TABLE sales"}}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "columns.column", Detail: `invalid identifier "posted_at; DROP TABLE sales"`},
+			},
+		},
+		{
+			name:   "Fiscal calendar",
+			config: `{"type": "FISCAL_YEAR", "fiscal": {"start_month": 13, "year_end_weekday": "sunday"}}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "fiscal.start_month", Detail: "invalid fiscal start month: 13"},
+				{Kind: UnexpectedParameter, Field: "fiscal.year_end_weekday", Detail: "only used with a week_pattern"},
+			},
+		},
+		{
+			name:   "Week calendar on calendar period columns",
+			config: `{"type": "CURRENT_MONTH", "fiscal": {"week_pattern": "4-4-5"}}`,
+			expected: FieldErrors{
+				{Kind: InvalidParameter, Field: "columns.fiscal_periods",
+					Detail: "a week based fiscal calendar resolves to fiscal periods, set fiscal_periods if the YEAR_MONTH columns hold them or use the DATE mode"},
+			},
+		},
+		{
+			name: "Week calendar on fiscal period columns",
+			config: `{"type": "CURRENT_MONTH", "fiscal": {"week_pattern": "4-4-5"},
+				"columns": {"mode": "YYYYMM", "column": "fiscal_period", "fiscal_periods": true}}`,
+		},
+		{
+			name:   "Fiscal periods without a week calendar",
+			config: `{"type": "YTD", "fiscal": {"start_month": 7}, "columns": {"fiscal_periods": true}}`,
+			expected: FieldErrors{
+				{Kind: UnexpectedParameter, Field: "columns.fiscal_periods", Detail: "only used with a fiscal week_pattern"},
+			},
+		},
+		{
+			name:   "Comparison",
+			config: `{"type": "YTD", "comparison": {"type": "PREVIOUS_PERIOD", "periods": 2}}`,
+			expected: FieldErrors{
+				{Kind: UnexpectedParameter, Field: "comparison.periods", Detail: "periods is not used by PREVIOUS_PERIOD"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config DateConfig
+			require.NoError(t, json.Unmarshal([]byte(tt.config), &config))
+
+			err := config.Validate()
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var fieldErrors FieldErrors
+			require.True(t, errors.As(err, &fieldErrors), "expected FieldErrors, got %v", err)
+			assert.Equal(t, tt.expected, fieldErrors)
+		})
+	}
+}
+
+func TestDateRangeCondition_BuildValidates(t *testing.T) {
+	config := DateConfig{Type: BackMonth, Parameters: DateParameters{Months: intPointer(1), Year: intPointer(2024)}}
+
+	_, _, err := NewDateRangeCondition(config).Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "invalid date range: parameters.year: year is not used by BACK_MONTH")
+
+	var fieldErrors FieldErrors
+	require.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, UnexpectedParameter, fieldErrors[0].Kind)
+}
+
+func TestNewValidatedDateRangeCondition(t *testing.T) {
+	config := DateConfig{Type: BackMonth, Parameters: DateParameters{Months: intPointer(1), Year: intPointer(2024)}}
+
+	condition, err := NewValidatedDateRangeCondition(config)
+	assert.Nil(t, condition)
+	assert.EqualError(t, err, "parameters.year: year is not used by BACK_MONTH")
+
+	var fieldErrors FieldErrors
+	require.True(t, errors.As(err, &fieldErrors))
+	assert.Equal(t, UnexpectedParameter, fieldErrors[0].Kind)
+
+	condition, err = NewValidatedDateRangeCondition(DateConfig{Type: BackMonth, Parameters: DateParameters{Months: intPointer(1)}})
+	require.NoError(t, err)
+	assert.Equal(t, BackMonth, condition.DateConfig.Type)
+}
+
+func TestFieldErrors_JSON(t *testing.T) {
+	err := DateConfig{Type: BackMonth}.Validate()
+
+	data, marshalErr := json.Marshal(err)
+	require.NoError(t, marshalErr)
+	assert.JSONEq(t, `[{"kind": "MISSING_PARAMETER", "field": "parameters.months",
+		"detail": "months parameter required for BACK_MONTH"}]`, string(data))
+}
@@ -0,0 +1,45 @@
+package fields
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"errors"
+	"fmt"
+)
+
+// ColumnCondition compares two columns, e.g. for join ON conditions
+type ColumnCondition struct {
+	Left     string             // Left column name
+	Operator ComparisonOperator // Comparison operator
+	Right    string             // Right column name
+}
+
+// NewColumnCondition creates a new column-to-column condition
+func NewColumnCondition(left string, operator ComparisonOperator, right string) *ColumnCondition {
+	return &ColumnCondition{
+		Left:     left,
+		Operator: operator,
+		Right:    right,
+	}
+}
+
+// Build implements the QueryCondition interface
+func (cc *ColumnCondition) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	switch cc.Operator {
+	case Equals, NotEquals, GreaterThan, LessThan, GreaterOrEqual, LessOrEqual:
+	default:
+		return "", nil, fmt.Errorf("operator %s is not supported for column comparison", cc.Operator)
+	}
+	if cc.Left == "" || cc.Right == "" {
+		return "", nil, errors.New("column comparison requires both columns")
+	}
+	left, err := identifier.Quote(d, cc.Left)
+	if err != nil {
+		return "", nil, err
+	}
+	right, err := identifier.Quote(d, cc.Right)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s %s", left, cc.Operator, right), nil, nil
+}
@@ -0,0 +1,63 @@
+package fields
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnCondition_Build(t *testing.T) {
+	tests := []struct {
+		name          string
+		left          string
+		operator      ComparisonOperator
+		right         string
+		expectedSQL   string
+		expectedError string
+	}{
+		{
+			name:        "Equals columns",
+			left:        "t.account_code",
+			operator:    Equals,
+			right:       "coa.account_code",
+			expectedSQL: `"t"."account_code" = "coa"."account_code"`,
+		},
+		{
+			name:        "Range comparison",
+			left:        "t.posted_at",
+			operator:    GreaterOrEqual,
+			right:       "p.start_date",
+			expectedSQL: `"t"."posted_at" >= "p"."start_date"`,
+		},
+		{
+			name:          "Unsupported operator",
+			left:          "a",
+			operator:      In,
+			right:         "b",
+			expectedError: "operator IN is not supported for column comparison",
+		},
+		{
+			name:          "Missing column",
+			left:          "a",
+			operator:      Equals,
+			expectedError: "column comparison requires both columns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := NewColumnCondition(tt.left, tt.operator, tt.right).Build(dialect.Postgres, 1)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Nil(t, args)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package fields
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -9,25 +12,63 @@ import (
 type ComparisonOperator string
 
 const (
-	Equals         ComparisonOperator = "="
-	NotEquals      ComparisonOperator = "!="
-	GreaterThan    ComparisonOperator = ">"
-	LessThan       ComparisonOperator = "<"
-	GreaterOrEqual ComparisonOperator = ">="
-	LessOrEqual    ComparisonOperator = "<="
-	Like           ComparisonOperator = "LIKE"
-	ILike          ComparisonOperator = "ILIKE"
-	In             ComparisonOperator = "IN"
-	NotIn          ComparisonOperator = "NOT IN"
-	IsNull         ComparisonOperator = "IS NULL"
-	IsNotNull      ComparisonOperator = "IS NOT NULL"
+	Equals            ComparisonOperator = "="
+	NotEquals         ComparisonOperator = "!="
+	GreaterThan       ComparisonOperator = ">"
+	LessThan          ComparisonOperator = "<"
+	GreaterOrEqual    ComparisonOperator = ">="
+	LessOrEqual       ComparisonOperator = "<="
+	Like              ComparisonOperator = "LIKE"
+	NotLike           ComparisonOperator = "NOT LIKE"
+	ILike             ComparisonOperator = "ILIKE"
+	NotILike          ComparisonOperator = "NOT ILIKE"
+	In                ComparisonOperator = "IN"
+	NotIn             ComparisonOperator = "NOT IN"
+	Between           ComparisonOperator = "BETWEEN"     // Value is a slice of the lower and upper bound
+	NotBetween        ComparisonOperator = "NOT BETWEEN" // Value is a slice of the lower and upper bound
+	IsNull            ComparisonOperator = "IS NULL"
+	IsNotNull         ComparisonOperator = "IS NOT NULL"
+	IsTrue            ComparisonOperator = "IS TRUE"
+	IsFalse           ComparisonOperator = "IS FALSE"
+	IsDistinctFrom    ComparisonOperator = "IS DISTINCT FROM"     // Like != but NULL is a value
+	IsNotDistinctFrom ComparisonOperator = "IS NOT DISTINCT FROM" // Like = but NULL is a value
+
+	// Postgres pattern matching, see dialect.Features.PatternMatch
+	SimilarTo ComparisonOperator = "SIMILAR TO"
+	Regex     ComparisonOperator = "~"  // POSIX regular expression
+	IRegex    ComparisonOperator = "~*" // Case insensitive POSIX regular expression
+
+	// Postgres array and JSONB operators, see dialect.Features.JSONOperators
+	Contains       ComparisonOperator = "@>"
+	ContainedBy    ComparisonOperator = "<@"
+	Overlaps       ComparisonOperator = "&&"
+	HasKey         ComparisonOperator = "?"
+	JSONTextEquals ComparisonOperator = "->>" // field ->> key = value, Value is a slice of the key and the value
+)
+
+// EmptyListPolicy decides what an IN or NOT IN condition with an empty list
+// builds, e.g. when a filter comes from a selection the user left empty
+type EmptyListPolicy string
+
+const (
+	// EmptyListError fails the build, this is the default
+	EmptyListError EmptyListPolicy = "ERROR"
+	// EmptyListMatchNone compares with the empty set: IN matches no row
+	// (1 = 0) and NOT IN matches every row (1 = 1)
+	EmptyListMatchNone EmptyListPolicy = "MATCH_NONE"
+	// EmptyListIgnore drops the condition as if it was never added, which
+	// matches every row when it is combined with AND
+	EmptyListIgnore EmptyListPolicy = "IGNORE"
 )
 
 // FieldCondition represents a single field condition
 type FieldCondition struct {
-	Field    string             // Column name
-	Operator ComparisonOperator // Comparison operator
-	Value    interface{}        // Value to compare against
+	Field          string             // Column name
+	Operator       ComparisonOperator // Comparison operator
+	Value          interface{}        // Value to compare against, a slice of any type for IN and BETWEEN
+	RawField       bool               // Field is an unchecked SQL expression, see NewUnsafeRawCondition
+	ArrayParameter bool               // IN list bound as one array, see WithArrayParameter
+	EmptyList      EmptyListPolicy    // What an empty IN list builds, EmptyListError when unset
 }
 
 // NewFieldCondition creates a new field condition
@@ -39,36 +80,188 @@ func NewFieldCondition(field string, operator ComparisonOperator, value interfac
 	}
 }
 
-// Build implements the QueryCondition interface
-func (fc *FieldCondition) Build(paramOffset int) (string, []interface{}, error) {
-	// Handle special cases for NULL comparisons
-	switch fc.Operator {
-	case IsNull:
-		return fmt.Sprintf("%s IS NULL", fc.Field), nil, nil
-	case IsNotNull:
-		return fmt.Sprintf("%s IS NOT NULL", fc.Field), nil, nil
+// NewUnsafeRawCondition creates a condition on a raw SQL expression such as
+// SUM(amount). The expression is emitted verbatim and must never come from user input.
+func NewUnsafeRawCondition(expression string, operator ComparisonOperator, value interface{}) *FieldCondition {
+	return &FieldCondition{
+		Field:    expression,
+		Operator: operator,
+		Value:    value,
+		RawField: true,
+	}
+}
+
+// WithArrayParameter binds an IN or NOT IN list as a single array parameter,
+// field = ANY($1) or field <> ALL($1), instead of one placeholder per value.
+// This keeps the SQL and the parameter count fixed for long lists. The value
+// is bound as is, so it must be a typed slice such as []string or []int64
+// that the driver encodes as an array; []interface{} is rejected as drivers
+// cannot tell its element type. Dialects without array parameters expand the
+// list as usual.
+func (fc *FieldCondition) WithArrayParameter() *FieldCondition {
+	fc.ArrayParameter = true
+	return fc
+}
+
+// WithEmptyList sets what the condition builds when its IN or NOT IN list is empty
+func (fc *FieldCondition) WithEmptyList(policy EmptyListPolicy) *FieldCondition {
+	fc.EmptyList = policy
+	return fc
+}
+
+func (fc *FieldCondition) field(d dialect.Dialect) (string, error) {
+	if fc.RawField {
+		return identifier.UnsafeRaw(fc.Field).Build(d)
+	}
+	return identifier.Quote(d, fc.Field)
+}
+
+// Build implements the QueryCondition interface. Every value is bound as a
+// parameter, operators the dialect lacks are emulated or rejected.
+func (fc *FieldCondition) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	field, err := fc.field(d)
+	if err != nil {
+		return "", nil, err
 	}
+	features := d.Features()
+	placeholder := d.Placeholder(paramOffset)
 
-	// Handle IN and NOT IN operators
-	if fc.Operator == In || fc.Operator == NotIn {
-		values, ok := fc.Value.([]interface{})
+	switch fc.Operator {
+	case IsNull, IsNotNull:
+		return fmt.Sprintf("%s %s", field, fc.Operator), nil, nil
+
+	case IsTrue, IsFalse:
+		if features.BooleanTest {
+			return fmt.Sprintf("%s %s", field, fc.Operator), nil, nil
+		}
+		// Booleans are bits, NULL must not match either test
+		if fc.Operator == IsTrue {
+			return fmt.Sprintf("COALESCE(%s, 0) = 1", field), nil, nil
+		}
+		return fmt.Sprintf("COALESCE(%s, 1) = 0", field), nil, nil
+
+	case In, NotIn:
+		values, ok := sliceValues(fc.Value)
 		if !ok {
 			return "", nil, fmt.Errorf("value for IN/NOT IN operator must be a slice")
 		}
 		if len(values) == 0 {
-			return "", nil, fmt.Errorf("empty slice provided for IN/NOT IN operator")
+			return fc.buildEmptyList()
+		}
+		if fc.ArrayParameter {
+			// Checked on every dialect so a condition does not only fail on Postgres
+			if _, untyped := fc.Value.([]interface{}); untyped {
+				return "", nil, fmt.Errorf("array parameter for IN/NOT IN operator must be a typed slice such as []string, not []interface{}")
+			}
+			if features.ArrayParameters {
+				return fc.buildArray(field, placeholder)
+			}
 		}
 
 		// Build the parameter placeholders
 		placeholders := make([]string, len(values))
 		for i := range values {
-			placeholders[i] = fmt.Sprintf("$%d", paramOffset+i)
+			placeholders[i] = d.Placeholder(paramOffset + i)
 		}
-		return fmt.Sprintf("%s %s (%s)", fc.Field, fc.Operator,
+		return fmt.Sprintf("%s %s (%s)", field, fc.Operator,
 			strings.Join(placeholders, ",")), values, nil
+
+	case Between, NotBetween:
+		values, ok := sliceValues(fc.Value)
+		if !ok || len(values) != 2 {
+			return "", nil, fmt.Errorf("value for %s operator must be a slice of the lower and upper bound", fc.Operator)
+		}
+		return fmt.Sprintf("%s %s %s AND %s", field, fc.Operator, placeholder, d.Placeholder(paramOffset+1)),
+			values, nil
+
+	case Equals, NotEquals, GreaterThan, LessThan, GreaterOrEqual, LessOrEqual, Like, NotLike:
+
+	case ILike, NotILike:
+		// Dialects without ILIKE compare lowercased values instead
+		if !features.ILike {
+			like := Like
+			if fc.Operator == NotILike {
+				like = NotLike
+			}
+			return fmt.Sprintf("LOWER(%s) %s LOWER(%s)", field, like, placeholder),
+				[]interface{}{fc.Value}, nil
+		}
+
+	case IsDistinctFrom, IsNotDistinctFrom:
+		if !features.DistinctFrom {
+			if fc.Operator == IsNotDistinctFrom {
+				return fmt.Sprintf("%s <=> %s", field, placeholder), []interface{}{fc.Value}, nil
+			}
+			return fmt.Sprintf("NOT (%s <=> %s)", field, placeholder), []interface{}{fc.Value}, nil
+		}
+
+	case SimilarTo, Regex, IRegex:
+		if !features.PatternMatch {
+			return "", nil, fmt.Errorf("operator %s is not supported by %s", fc.Operator, d.Name())
+		}
+
+	case Contains, ContainedBy, Overlaps, HasKey, JSONTextEquals:
+		if !features.JSONOperators {
+			return "", nil, fmt.Errorf("operator %s is not supported by %s", fc.Operator, d.Name())
+		}
+		if fc.Operator == JSONTextEquals {
+			values, ok := sliceValues(fc.Value)
+			if !ok || len(values) != 2 {
+				return "", nil, fmt.Errorf("value for ->> operator must be a slice of the key and the value")
+			}
+			return fmt.Sprintf("%s ->> %s = %s", field, placeholder, d.Placeholder(paramOffset+1)), values, nil
+		}
+
+	default:
+		return "", nil, fmt.Errorf("invalid comparison operator: %s", fc.Operator)
 	}
 
-	// Regular comparison
-	return fmt.Sprintf("%s %s $%d", fc.Field, fc.Operator, paramOffset),
-		[]interface{}{fc.Value}, nil
+	return fmt.Sprintf("%s %s %s", field, fc.Operator, placeholder), []interface{}{fc.Value}, nil
+}
+
+// buildArray builds an IN list as a comparison with a single array parameter
+func (fc *FieldCondition) buildArray(field, placeholder string) (string, []interface{}, error) {
+	comparison := "= ANY"
+	if fc.Operator == NotIn {
+		comparison = "<> ALL"
+	}
+	return fmt.Sprintf("%s %s(%s)", field, comparison, placeholder), []interface{}{fc.Value}, nil
+}
+
+// buildEmptyList builds an IN or NOT IN condition with an empty list. The
+// constant comparisons are used as every dialect accepts them in a WHERE.
+func (fc *FieldCondition) buildEmptyList() (string, []interface{}, error) {
+	switch fc.EmptyList {
+	case "", EmptyListError:
+		return "", nil, fmt.Errorf("empty slice provided for IN/NOT IN operator")
+	case EmptyListMatchNone:
+		if fc.Operator == NotIn {
+			return "1 = 1", nil, nil
+		}
+		return "1 = 0", nil, nil
+	case EmptyListIgnore:
+		return "", nil, nil
+	default:
+		return "", nil, fmt.Errorf("invalid empty list policy: %s", fc.EmptyList)
+	}
+}
+
+// sliceValues returns the elements of a slice or array of any element type.
+// A []byte is a single value, not a list.
+func sliceValues(value interface{}) ([]interface{}, bool) {
+	if values, ok := value.([]interface{}); ok {
+		return values, true
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	if v.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, true
 }
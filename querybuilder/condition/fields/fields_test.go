@@ -1,6 +1,7 @@
 package fields
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,7 +24,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     Equals,
 			value:        42,
 			paramOffset:  1,
-			expectedSQL:  "column_name = $1",
+			expectedSQL:  `"column_name" = $1`,
 			expectedArgs: []interface{}{42},
 		},
 		{
@@ -32,7 +33,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     NotEquals,
 			value:        "test",
 			paramOffset:  2,
-			expectedSQL:  "column_name != $2",
+			expectedSQL:  `"column_name" != $2`,
 			expectedArgs: []interface{}{"test"},
 		},
 		{
@@ -41,7 +42,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     GreaterThan,
 			value:        18,
 			paramOffset:  1,
-			expectedSQL:  "age > $1",
+			expectedSQL:  `"age" > $1`,
 			expectedArgs: []interface{}{18},
 		},
 		{
@@ -50,7 +51,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     LessThan,
 			value:        99.99,
 			paramOffset:  3,
-			expectedSQL:  "price < $3",
+			expectedSQL:  `"price" < $3`,
 			expectedArgs: []interface{}{99.99},
 		},
 		{
@@ -59,7 +60,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     GreaterOrEqual,
 			value:        10,
 			paramOffset:  1,
-			expectedSQL:  "quantity >= $1",
+			expectedSQL:  `"quantity" >= $1`,
 			expectedArgs: []interface{}{10},
 		},
 		{
@@ -68,7 +69,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     LessOrEqual,
 			value:        50.5,
 			paramOffset:  4,
-			expectedSQL:  "weight <= $4",
+			expectedSQL:  `"weight" <= $4`,
 			expectedArgs: []interface{}{50.5},
 		},
 		{
@@ -77,7 +78,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     Like,
 			value:        "%John%",
 			paramOffset:  1,
-			expectedSQL:  "name LIKE $1",
+			expectedSQL:  `"name" LIKE $1`,
 			expectedArgs: []interface{}{"%John%"},
 		},
 		{
@@ -86,7 +87,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     ILike,
 			value:        "%.com",
 			paramOffset:  2,
-			expectedSQL:  "email ILIKE $2",
+			expectedSQL:  `"email" ILIKE $2`,
 			expectedArgs: []interface{}{"%.com"},
 		},
 		{
@@ -95,7 +96,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     In,
 			value:        []interface{}{"active", "pending"},
 			paramOffset:  1,
-			expectedSQL:  "status IN ($1,$2)",
+			expectedSQL:  `"status" IN ($1,$2)`,
 			expectedArgs: []interface{}{"active", "pending"},
 		},
 		{
@@ -104,7 +105,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     NotIn,
 			value:        []interface{}{1, 2, 3},
 			paramOffset:  1,
-			expectedSQL:  "category NOT IN ($1,$2,$3)",
+			expectedSQL:  `"category" NOT IN ($1,$2,$3)`,
 			expectedArgs: []interface{}{1, 2, 3},
 		},
 		{
@@ -113,7 +114,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     IsNull,
 			value:        nil,
 			paramOffset:  1,
-			expectedSQL:  "deleted_at IS NULL",
+			expectedSQL:  `"deleted_at" IS NULL`,
 			expectedArgs: nil,
 		},
 		{
@@ -122,7 +123,7 @@ func TestFieldCondition_Build(t *testing.T) {
 			operator:     IsNotNull,
 			value:        nil,
 			paramOffset:  1,
-			expectedSQL:  "updated_at IS NOT NULL",
+			expectedSQL:  `"updated_at" IS NOT NULL`,
 			expectedArgs: nil,
 		},
 		{
@@ -141,12 +142,203 @@ func TestFieldCondition_Build(t *testing.T) {
 			paramOffset:   1,
 			expectedError: "empty slice provided for IN/NOT IN operator",
 		},
+		{
+			name:         "Between operator",
+			field:        "amount",
+			operator:     Between,
+			value:        []interface{}{100, 200},
+			paramOffset:  2,
+			expectedSQL:  `"amount" BETWEEN $2 AND $3`,
+			expectedArgs: []interface{}{100, 200},
+		},
+		{
+			name:         "NotBetween operator",
+			field:        "amount",
+			operator:     NotBetween,
+			value:        []interface{}{100, 200},
+			paramOffset:  1,
+			expectedSQL:  `"amount" NOT BETWEEN $1 AND $2`,
+			expectedArgs: []interface{}{100, 200},
+		},
+		{
+			name:          "Between operator with one value",
+			field:         "amount",
+			operator:      Between,
+			value:         []interface{}{100},
+			paramOffset:   1,
+			expectedError: "value for BETWEEN operator must be a slice of the lower and upper bound",
+		},
+		{
+			name:          "Between operator with a single value",
+			field:         "amount",
+			operator:      NotBetween,
+			value:         100,
+			paramOffset:   1,
+			expectedError: "value for NOT BETWEEN operator must be a slice of the lower and upper bound",
+		},
+		{
+			name:         "NotLike operator",
+			field:        "name",
+			operator:     NotLike,
+			value:        "%test%",
+			paramOffset:  1,
+			expectedSQL:  `"name" NOT LIKE $1`,
+			expectedArgs: []interface{}{"%test%"},
+		},
+		{
+			name:         "NotILike operator",
+			field:        "email",
+			operator:     NotILike,
+			value:        "%.test",
+			paramOffset:  1,
+			expectedSQL:  `"email" NOT ILIKE $1`,
+			expectedArgs: []interface{}{"%.test"},
+		},
+		{
+			name:         "SimilarTo operator",
+			field:        "code",
+			operator:     SimilarTo,
+			value:        "(A|B)%",
+			paramOffset:  1,
+			expectedSQL:  `"code" SIMILAR TO $1`,
+			expectedArgs: []interface{}{"(A|B)%"},
+		},
+		{
+			name:         "Regex operator",
+			field:        "code",
+			operator:     Regex,
+			value:        "^[0-9]+$",
+			paramOffset:  1,
+			expectedSQL:  `"code" ~ $1`,
+			expectedArgs: []interface{}{"^[0-9]+$"},
+		},
+		{
+			name:         "IRegex operator",
+			field:        "name",
+			operator:     IRegex,
+			value:        "^acme",
+			paramOffset:  1,
+			expectedSQL:  `"name" ~* $1`,
+			expectedArgs: []interface{}{"^acme"},
+		},
+		{
+			name:         "IsDistinctFrom operator",
+			field:        "department",
+			operator:     IsDistinctFrom,
+			value:        nil,
+			paramOffset:  1,
+			expectedSQL:  `"department" IS DISTINCT FROM $1`,
+			expectedArgs: []interface{}{nil},
+		},
+		{
+			name:         "IsNotDistinctFrom operator",
+			field:        "department",
+			operator:     IsNotDistinctFrom,
+			value:        "FIN",
+			paramOffset:  1,
+			expectedSQL:  `"department" IS NOT DISTINCT FROM $1`,
+			expectedArgs: []interface{}{"FIN"},
+		},
+		{
+			name:         "IsTrue operator",
+			field:        "is_active",
+			operator:     IsTrue,
+			paramOffset:  1,
+			expectedSQL:  `"is_active" IS TRUE`,
+			expectedArgs: nil,
+		},
+		{
+			name:         "IsFalse operator",
+			field:        "is_active",
+			operator:     IsFalse,
+			paramOffset:  1,
+			expectedSQL:  `"is_active" IS FALSE`,
+			expectedArgs: nil,
+		},
+		{
+			name:         "Contains operator",
+			field:        "tags",
+			operator:     Contains,
+			value:        []string{"urgent"},
+			paramOffset:  1,
+			expectedSQL:  `"tags" @> $1`,
+			expectedArgs: []interface{}{[]string{"urgent"}},
+		},
+		{
+			name:         "ContainedBy operator",
+			field:        "tags",
+			operator:     ContainedBy,
+			value:        []string{"urgent", "review"},
+			paramOffset:  1,
+			expectedSQL:  `"tags" <@ $1`,
+			expectedArgs: []interface{}{[]string{"urgent", "review"}},
+		},
+		{
+			name:         "Overlaps operator",
+			field:        "tags",
+			operator:     Overlaps,
+			value:        []string{"urgent"},
+			paramOffset:  1,
+			expectedSQL:  `"tags" && $1`,
+			expectedArgs: []interface{}{[]string{"urgent"}},
+		},
+		{
+			name:         "HasKey operator",
+			field:        "attributes",
+			operator:     HasKey,
+			value:        "color",
+			paramOffset:  1,
+			expectedSQL:  `"attributes" ? $1`,
+			expectedArgs: []interface{}{"color"},
+		},
+		{
+			name:         "JSONTextEquals operator",
+			field:        "attributes",
+			operator:     JSONTextEquals,
+			value:        []interface{}{"color", "red"},
+			paramOffset:  3,
+			expectedSQL:  `"attributes" ->> $3 = $4`,
+			expectedArgs: []interface{}{"color", "red"},
+		},
+		{
+			name:          "JSONTextEquals operator without a key",
+			field:         "attributes",
+			operator:      JSONTextEquals,
+			value:         "red",
+			paramOffset:   1,
+			expectedError: "value for ->> operator must be a slice of the key and the value",
+		},
+		{
+			name:         "Qualified field",
+			field:        "coa.account_code",
+			operator:     Equals,
+			value:        "1001",
+			paramOffset:  1,
+			expectedSQL:  `"coa"."account_code" = $1`,
+			expectedArgs: []interface{}{"1001"},
+		},
+		{
+			name:          "Field with injection attempt",
+			field:         "id = 1 OR 1",
+			operator:      Equals,
+			value:         1,
+			paramOffset:   1,
+			expectedError: "invalid identifier",
+		},
+		{
+			name:          "Invalid operator",
+			field:         "id",
+			operator:      "= 1 OR 1 =",
+			value:         1,
+			paramOffset:   1,
+			expectedError: "invalid comparison operator",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fc := NewFieldCondition(tt.field, tt.operator, tt.value)
-			sql, args, err := fc.Build(tt.paramOffset)
+			sql, args, err := fc.Build(dialect.Postgres, tt.paramOffset)
 
 			if tt.expectedError != "" {
 				assert.Error(t, err)
@@ -173,3 +365,335 @@ func TestNewFieldCondition(t *testing.T) {
 	assert.Equal(t, operator, fc.Operator)
 	assert.Equal(t, value, fc.Value)
 }
+
+func TestNewUnsafeRawCondition(t *testing.T) {
+	fc := NewUnsafeRawCondition("SUM(amount)", GreaterThan, 1000)
+
+	sql, args, err := fc.Build(dialect.Postgres, 2)
+	assert.NoError(t, err)
+	assert.True(t, fc.RawField)
+	assert.Equal(t, "SUM(amount) > $2", sql)
+	assert.Equal(t, []interface{}{1000}, args)
+}
+
+func TestFieldCondition_BuildDialects(t *testing.T) {
+	tests := []struct {
+		dialect      dialect.Dialect
+		expectedIn   string
+		expectedLike string
+	}{
+		{
+			dialect:      dialect.Postgres,
+			expectedIn:   `"status" IN ($3,$4)`,
+			expectedLike: `"name" ILIKE $3`,
+		},
+		{
+			dialect:      dialect.MySQL,
+			expectedIn:   "`status` IN (?,?)",
+			expectedLike: "LOWER(`name`) LIKE LOWER(?)",
+		},
+		{
+			dialect:      dialect.SQLite,
+			expectedIn:   `"status" IN (?,?)`,
+			expectedLike: `LOWER("name") LIKE LOWER(?)`,
+		},
+		{
+			dialect:      dialect.SQLServer,
+			expectedIn:   "[status] IN (@p3,@p4)",
+			expectedLike: "LOWER([name]) LIKE LOWER(@p3)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			sql, args, err := NewFieldCondition("status", In, []interface{}{"open", "closed"}).Build(tt.dialect, 3)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIn, sql)
+			assert.Equal(t, []interface{}{"open", "closed"}, args)
+
+			sql, args, err = NewFieldCondition("name", ILike, "%acme%").Build(tt.dialect, 3)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedLike, sql)
+			assert.Equal(t, []interface{}{"%acme%"}, args)
+		})
+	}
+}
+
+func TestFieldCondition_BuildOperatorDialects(t *testing.T) {
+	tests := []struct {
+		name         string
+		operator     ComparisonOperator
+		value        interface{}
+		expectedSQL  map[string]string
+		expectedArgs []interface{}
+	}{
+		{
+			name:     "NOT ILIKE",
+			operator: NotILike,
+			value:    "%acme%",
+			expectedSQL: map[string]string{
+				"postgres":  `"name" NOT ILIKE $1`,
+				"mysql":     "LOWER(`name`) NOT LIKE LOWER(?)",
+				"sqlite":    `LOWER("name") NOT LIKE LOWER(?)`,
+				"sqlserver": "LOWER([name]) NOT LIKE LOWER(@p1)",
+			},
+			expectedArgs: []interface{}{"%acme%"},
+		},
+		{
+			name:     "IS TRUE",
+			operator: IsTrue,
+			expectedSQL: map[string]string{
+				"postgres":  `"name" IS TRUE`,
+				"mysql":     "`name` IS TRUE",
+				"sqlite":    `"name" IS TRUE`,
+				"sqlserver": "COALESCE([name], 0) = 1",
+			},
+		},
+		{
+			name:     "IS FALSE",
+			operator: IsFalse,
+			expectedSQL: map[string]string{
+				"postgres":  `"name" IS FALSE`,
+				"mysql":     "`name` IS FALSE",
+				"sqlite":    `"name" IS FALSE`,
+				"sqlserver": "COALESCE([name], 1) = 0",
+			},
+		},
+		{
+			name:     "IS DISTINCT FROM",
+			operator: IsDistinctFrom,
+			value:    "acme",
+			expectedSQL: map[string]string{
+				"postgres":  `"name" IS DISTINCT FROM $1`,
+				"mysql":     "NOT (`name` <=> ?)",
+				"sqlite":    `"name" IS DISTINCT FROM ?`,
+				"sqlserver": "[name] IS DISTINCT FROM @p1",
+			},
+			expectedArgs: []interface{}{"acme"},
+		},
+		{
+			name:     "IS NOT DISTINCT FROM",
+			operator: IsNotDistinctFrom,
+			value:    "acme",
+			expectedSQL: map[string]string{
+				"postgres":  `"name" IS NOT DISTINCT FROM $1`,
+				"mysql":     "`name` <=> ?",
+				"sqlite":    `"name" IS NOT DISTINCT FROM ?`,
+				"sqlserver": "[name] IS NOT DISTINCT FROM @p1",
+			},
+			expectedArgs: []interface{}{"acme"},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, d := range []dialect.Dialect{dialect.Postgres, dialect.MySQL, dialect.SQLite, dialect.SQLServer} {
+			t.Run(tt.name+"/"+d.Name(), func(t *testing.T) {
+				sql, args, err := NewFieldCondition("name", tt.operator, tt.value).Build(d, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSQL[d.Name()], sql)
+				assert.Equal(t, tt.expectedArgs, args)
+			})
+		}
+	}
+}
+
+func TestFieldCondition_BuildPostgresOperators(t *testing.T) {
+	for _, operator := range []ComparisonOperator{SimilarTo, Regex, IRegex, Contains, ContainedBy, Overlaps, HasKey, JSONTextEquals} {
+		for _, d := range []dialect.Dialect{dialect.MySQL, dialect.SQLite, dialect.SQLServer} {
+			t.Run(string(operator)+"/"+d.Name(), func(t *testing.T) {
+				_, _, err := NewFieldCondition("name", operator, []interface{}{"a", "b"}).Build(d, 1)
+				assert.EqualError(t, err, "operator "+string(operator)+" is not supported by "+d.Name())
+			})
+		}
+	}
+}
+
+func TestFieldCondition_BuildTypedSlices(t *testing.T) {
+	tests := []struct {
+		name         string
+		operator     ComparisonOperator
+		value        interface{}
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "Strings",
+			operator:     In,
+			value:        []string{"1001", "1002"},
+			expectedSQL:  `"code" IN ($1,$2)`,
+			expectedArgs: []interface{}{"1001", "1002"},
+		},
+		{
+			name:         "Integers",
+			operator:     NotIn,
+			value:        []int64{7, 8, 9},
+			expectedSQL:  `"code" NOT IN ($1,$2,$3)`,
+			expectedArgs: []interface{}{int64(7), int64(8), int64(9)},
+		},
+		{
+			name:         "Array",
+			operator:     In,
+			value:        [2]int{1, 2},
+			expectedSQL:  `"code" IN ($1,$2)`,
+			expectedArgs: []interface{}{1, 2},
+		},
+		{
+			name:         "Between bounds",
+			operator:     Between,
+			value:        []float64{1.5, 2.5},
+			expectedSQL:  `"code" BETWEEN $1 AND $2`,
+			expectedArgs: []interface{}{1.5, 2.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := NewFieldCondition("code", tt.operator, tt.value).Build(dialect.Postgres, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	_, _, err := NewFieldCondition("code", In, []byte("abc")).Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "value for IN/NOT IN operator must be a slice")
+}
+
+func TestFieldCondition_WithArrayParameter(t *testing.T) {
+	accounts := []string{"1001", "1002", "1003"}
+
+	tests := []struct {
+		name         string
+		dialect      dialect.Dialect
+		operator     ComparisonOperator
+		value        interface{}
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "IN",
+			dialect:      dialect.Postgres,
+			operator:     In,
+			value:        accounts,
+			expectedSQL:  `"code" = ANY($2)`,
+			expectedArgs: []interface{}{accounts},
+		},
+		{
+			name:         "NOT IN",
+			dialect:      dialect.Postgres,
+			operator:     NotIn,
+			value:        accounts,
+			expectedSQL:  `"code" <> ALL($2)`,
+			expectedArgs: []interface{}{accounts},
+		},
+		{
+			name:         "Integers",
+			dialect:      dialect.Postgres,
+			operator:     In,
+			value:        []int64{7, 8},
+			expectedSQL:  `"code" = ANY($2)`,
+			expectedArgs: []interface{}{[]int64{7, 8}},
+		},
+		{
+			name:         "Expanded without array parameters",
+			dialect:      dialect.MySQL,
+			operator:     In,
+			value:        accounts,
+			expectedSQL:  "`code` IN (?,?,?)",
+			expectedArgs: []interface{}{"1001", "1002", "1003"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := NewFieldCondition("code", tt.operator, tt.value).WithArrayParameter().Build(tt.dialect, 2)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	_, _, err := NewFieldCondition("code", In, []string{}).WithArrayParameter().Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "empty slice provided for IN/NOT IN operator")
+
+	// Invalid values fail the same way on every dialect
+	for _, d := range []dialect.Dialect{dialect.Postgres, dialect.MySQL} {
+		_, _, err = NewFieldCondition("code", In, "1001").WithArrayParameter().Build(d, 1)
+		assert.EqualError(t, err, "value for IN/NOT IN operator must be a slice", d.Name())
+
+		_, _, err = NewFieldCondition("code", In, []interface{}{"1001"}).WithArrayParameter().Build(d, 1)
+		assert.EqualError(t, err, "array parameter for IN/NOT IN operator must be a typed slice such as []string, not []interface{}", d.Name())
+	}
+}
+
+func TestFieldCondition_EmptyList(t *testing.T) {
+	tests := []struct {
+		name          string
+		operator      ComparisonOperator
+		policy        EmptyListPolicy
+		arrayParam    bool
+		expectedSQL   string
+		expectedError string
+	}{
+		{
+			name:          "Error by default",
+			operator:      In,
+			expectedError: "empty slice provided for IN/NOT IN operator",
+		},
+		{
+			name:          "Error",
+			operator:      NotIn,
+			policy:        EmptyListError,
+			expectedError: "empty slice provided for IN/NOT IN operator",
+		},
+		{
+			name:        "IN matches none",
+			operator:    In,
+			policy:      EmptyListMatchNone,
+			expectedSQL: "1 = 0",
+		},
+		{
+			name:        "NOT IN matches all",
+			operator:    NotIn,
+			policy:      EmptyListMatchNone,
+			expectedSQL: "1 = 1",
+		},
+		{
+			name:        "Ignored",
+			operator:    In,
+			policy:      EmptyListIgnore,
+			expectedSQL: "",
+		},
+		{
+			name:        "Array parameter",
+			operator:    In,
+			policy:      EmptyListMatchNone,
+			arrayParam:  true,
+			expectedSQL: "1 = 0",
+		},
+		{
+			name:          "Invalid policy",
+			operator:      In,
+			policy:        "SKIP",
+			expectedError: "invalid empty list policy: SKIP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := NewFieldCondition("department", tt.operator, []string{}).WithEmptyList(tt.policy)
+			if tt.arrayParam {
+				fc.WithArrayParameter()
+			}
+			sql, args, err := fc.Build(dialect.Postgres, 1)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Empty(t, args)
+		})
+	}
+}
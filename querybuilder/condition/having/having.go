@@ -0,0 +1,93 @@
+package having
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"fmt"
+)
+
+// Having implements HavingClause on top of the regular condition tree
+type Having struct {
+	Group   *querybuilder.WhereGroup
+	aliases map[string]string // alias -> aggregate expression
+}
+
+// Verify interface implementation at compile time
+var _ querybuilder.HavingClause = (*Having)(nil)
+
+func NewHaving() *Having {
+	return &Having{
+		Group:   querybuilder.NewWhereGroup(querybuilder.AND),
+		aliases: make(map[string]string),
+	}
+}
+
+// Add adds a condition; conditions are joined with AND
+func (h *Having) Add(condition querybuilder.QueryCondition) *Having {
+	h.Group.Add(condition)
+	return h
+}
+
+// ResolveAliases returns a copy of the clause where conditions written against
+// an alias (e.g. total_amount > $1) are rendered using the aggregate expression,
+// since Postgres does not allow select aliases inside HAVING. The copy shares
+// the conditions and h is left unchanged, so it can be resolved on every build.
+func (h *Having) ResolveAliases(aliases map[string]string) *Having {
+	resolved := &Having{
+		Group:   h.Group,
+		aliases: make(map[string]string, len(h.aliases)+len(aliases)),
+	}
+	for alias, expression := range h.aliases {
+		resolved.aliases[alias] = expression
+	}
+	for alias, expression := range aliases {
+		resolved.aliases[alias] = expression
+	}
+	return resolved
+}
+
+func (h *Having) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	condition := h.resolve(h.Group)
+
+	clause, args, err := condition.Build(d, paramOffset)
+	if err != nil {
+		return "", nil, err
+	}
+	if clause == "" {
+		return "", nil, nil
+	}
+
+	return fmt.Sprintf("HAVING %s", clause), args, nil
+}
+
+// resolve returns a copy of the condition with alias references replaced by
+// their aggregate expressions. Conditions it does not know are left untouched.
+func (h *Having) resolve(condition querybuilder.QueryCondition) querybuilder.QueryCondition {
+	switch c := condition.(type) {
+	case *fields.FieldCondition:
+		if expression, ok := h.aliases[c.Field]; ok && !c.RawField {
+			resolved := *c
+			resolved.Field = expression
+			resolved.RawField = true
+			return &resolved
+		}
+		return c
+	case *querybuilder.WhereGroup:
+		resolved := querybuilder.NewWhereGroup(c.Operator)
+		for _, cond := range c.Conditions {
+			resolved.Add(h.resolve(cond))
+		}
+		return resolved
+	case *querybuilder.CompoundCondition:
+		resolved := &querybuilder.CompoundCondition{Operator: c.Operator}
+		for _, cond := range c.Conditions {
+			resolved.Conditions = append(resolved.Conditions, h.resolve(cond))
+		}
+		return resolved
+	case *querybuilder.NotCondition:
+		return querybuilder.Not(h.resolve(c.Condition))
+	default:
+		return condition
+	}
+}
@@ -0,0 +1,134 @@
+package having
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHaving(t *testing.T) {
+	tests := []struct {
+		name         string
+		buildHaving  func() *Having
+		paramOffset  int
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name: "Empty Having",
+			buildHaving: func() *Having {
+				return NewHaving()
+			},
+			paramOffset:  1,
+			expectedSQL:  "",
+			expectedArgs: nil,
+		},
+		{
+			name: "Aggregate Expression",
+			buildHaving: func() *Having {
+				return NewHaving().
+					Add(fields.NewUnsafeRawCondition("SUM(amount)", fields.GreaterThan, 1000000))
+			},
+			paramOffset:  1,
+			expectedSQL:  "HAVING SUM(amount) > $1",
+			expectedArgs: []interface{}{1000000},
+		},
+		{
+			name: "Alias Resolved To Expression",
+			buildHaving: func() *Having {
+				return NewHaving().
+					ResolveAliases(map[string]string{"total_amount": "SUM(amount)"}).
+					Add(fields.NewFieldCondition("total_amount", fields.GreaterThan, 1000000))
+			},
+			paramOffset:  1,
+			expectedSQL:  "HAVING SUM(amount) > $1",
+			expectedArgs: []interface{}{1000000},
+		},
+		{
+			name: "Parameters Continue After Offset",
+			buildHaving: func() *Having {
+				return NewHaving().
+					Add(fields.NewUnsafeRawCondition("SUM(amount)", fields.GreaterThan, 100)).
+					Add(fields.NewUnsafeRawCondition("COUNT(*)", fields.GreaterOrEqual, 5))
+			},
+			paramOffset:  4,
+			expectedSQL:  "HAVING (SUM(amount) > $4 AND COUNT(*) >= $5)",
+			expectedArgs: []interface{}{100, 5},
+		},
+		{
+			name: "Nested Group With Aliases",
+			buildHaving: func() *Having {
+				group := querybuilder.NewWhereGroup(querybuilder.OR)
+				group.Add(fields.NewFieldCondition("total_amount", fields.GreaterThan, 1000))
+				group.Add(fields.NewFieldCondition("avg_margin", fields.LessThan, 0.1))
+
+				return NewHaving().
+					ResolveAliases(map[string]string{
+						"total_amount": "SUM(amount)",
+						"avg_margin":   "AVG(profit_margin)",
+					}).
+					Add(group)
+			},
+			paramOffset:  2,
+			expectedSQL:  "HAVING (SUM(amount) > $2 OR AVG(profit_margin) < $3)",
+			expectedArgs: []interface{}{1000, 0.1},
+		},
+		{
+			name: "Combinators With Aliases",
+			buildHaving: func() *Having {
+				return NewHaving().
+					ResolveAliases(map[string]string{
+						"total_amount": "SUM(amount)",
+						"row_count":    "COUNT(*)",
+					}).
+					Add(querybuilder.Or(
+						fields.NewFieldCondition("total_amount", fields.GreaterThan, 1000),
+						querybuilder.Not(fields.NewFieldCondition("row_count", fields.LessThan, 5)),
+					))
+			},
+			paramOffset:  1,
+			expectedSQL:  "HAVING (SUM(amount) > $1 OR NOT (COUNT(*) < $2))",
+			expectedArgs: []interface{}{1000, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.buildHaving()
+			sql, args, err := h.Build(dialect.Postgres, tt.paramOffset)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestHaving_ResolveDoesNotMutateConditions(t *testing.T) {
+	condition := fields.NewFieldCondition("total_amount", fields.GreaterThan, 1000)
+
+	h := NewHaving().
+		ResolveAliases(map[string]string{"total_amount": "SUM(amount)"}).
+		Add(condition)
+
+	_, _, err := h.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "total_amount", condition.Field)
+}
+
+func TestHaving_ResolveAliasesReturnsCopy(t *testing.T) {
+	h := NewHaving().Add(fields.NewFieldCondition("total_amount", fields.GreaterThan, 1000))
+	resolved := h.ResolveAliases(map[string]string{"total_amount": "SUM(amount)"})
+
+	sql, _, err := resolved.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "HAVING SUM(amount) > $1", sql)
+
+	sql, _, err = h.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `HAVING "total_amount" > $1`, sql)
+}
@@ -2,53 +2,65 @@ package wheregroups
 
 import (
 	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/dialect"
 	"fmt"
-	"strings"
 )
 
-// WhereGroups implements WhereClause
+// WhereGroups implements WhereClause. Groups are kept by pointer, so
+// conditions added to a group after it was added are part of the clause.
 type WhereGroups struct {
-	Groups []querybuilder.WhereGroup
+	Groups []*querybuilder.WhereGroup
 }
 
 func NewWhereGroups() *WhereGroups {
 	return &WhereGroups{
-		Groups: make([]querybuilder.WhereGroup, 0),
+		Groups: make([]*querybuilder.WhereGroup, 0),
 	}
 }
-func (w *WhereGroups) Add(group querybuilder.WhereGroup) {
-	// fmt.Printf("Adding group with %d conditions to WhereGroups\n", len(group.Conditions))
+func (w *WhereGroups) Add(group *querybuilder.WhereGroup) {
 	w.Groups = append(w.Groups, group)
 }
 
-func (w *WhereGroups) Build(paramOffset int) (string, []interface{}, error) {
-	if len(w.Groups) == 0 {
-		return "", nil, nil
+func (w *WhereGroups) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	expression, args, err := w.BuildExpression(d, paramOffset)
+	if err != nil || expression == "" {
+		return "", nil, err
 	}
+	return fmt.Sprintf("WHERE %s", expression), args, nil
+}
 
-	var clauses []string
+// BuildExpression builds the joined groups without the WHERE keyword so the
+// result can be combined with other predicates. Groups are joined left to
+// right: when the joining operator changes, everything before it is
+// parenthesized, so a OR b AND c is built as (a OR b) AND c.
+func (w *WhereGroups) BuildExpression(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	var expression string
+	var joinedWith querybuilder.LogicalOperator // Operator joining the groups in expression, empty for one group
 	var args []interface{}
-	currentOffset := paramOffset
 
-	for i, group := range w.Groups {
-		clause, groupArgs, err := group.Build(currentOffset)
+	for _, group := range w.Groups {
+		clause, groupArgs, err := group.Build(d, paramOffset+len(args))
 		if err != nil {
 			return "", nil, err
 		}
-		if clause != "" {
-			// Only add the operator if it's not the first group
-			if i > 0 {
-				clauses = append(clauses, string(group.Operator))
-			}
-			clauses = append(clauses, clause)
-			args = append(args, groupArgs...)
-			currentOffset += len(groupArgs)
+		// Empty groups are skipped, the first group's operator joins nothing
+		if clause == "" {
+			continue
+		}
+		args = append(args, groupArgs...)
+		if expression == "" {
+			expression = clause
+			continue
 		}
+		if joinedWith != "" && joinedWith != group.Operator {
+			expression = fmt.Sprintf("(%s)", expression)
+		}
+		expression = fmt.Sprintf("%s %s %s", expression, group.Operator, clause)
+		joinedWith = group.Operator
 	}
 
-	if len(clauses) == 0 {
+	if expression == "" {
 		return "", nil, nil
 	}
-
-	return fmt.Sprintf("WHERE %s", strings.Join(clauses, " ")), args, nil
+	return expression, args, nil
 }
@@ -3,6 +3,7 @@ package wheregroups
 import (
 	"dynamic-sqlbuilder/querybuilder"
 	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -35,11 +36,11 @@ func TestWhereGroups(t *testing.T) {
 				group := querybuilder.NewWhereGroup(querybuilder.AND)
 				condition := fields.NewFieldCondition("column1", fields.Equals, "value1")
 				group.Add(condition)
-				wg.Add(*group)
+				wg.Add(group)
 				return wg
 			},
 			paramOffset:   1,
-			expectedSQL:   "WHERE column1 = $1",
+			expectedSQL:   `WHERE "column1" = $1`,
 			expectedArgs:  []interface{}{"value1"},
 			expectedError: nil,
 		},
@@ -51,17 +52,17 @@ func TestWhereGroups(t *testing.T) {
 				// First group
 				group1 := querybuilder.NewWhereGroup(querybuilder.AND)
 				group1.Add(fields.NewFieldCondition("column1", fields.Equals, "value1"))
-				wg.Add(*group1)
+				wg.Add(group1)
 
 				// Second group
 				group2 := querybuilder.NewWhereGroup(querybuilder.AND)
 				group2.Add(fields.NewFieldCondition("column2", fields.GreaterThan, 10))
-				wg.Add(*group2)
+				wg.Add(group2)
 
 				return wg
 			},
 			paramOffset:   1,
-			expectedSQL:   "WHERE column1 = $1 AND column2 > $2",
+			expectedSQL:   `WHERE "column1" = $1 AND "column2" > $2`,
 			expectedArgs:  []interface{}{"value1", 10},
 			expectedError: nil,
 		},
@@ -72,16 +73,16 @@ func TestWhereGroups(t *testing.T) {
 
 				group1 := querybuilder.NewWhereGroup(querybuilder.OR)
 				group1.Add(fields.NewFieldCondition("column1", fields.Equals, "value1"))
-				wg.Add(*group1)
+				wg.Add(group1)
 
 				group2 := querybuilder.NewWhereGroup(querybuilder.OR)
 				group2.Add(fields.NewFieldCondition("column2", fields.LessThan, 20))
-				wg.Add(*group2)
+				wg.Add(group2)
 
 				return wg
 			},
 			paramOffset:   1,
-			expectedSQL:   "WHERE column1 = $1 OR column2 < $2",
+			expectedSQL:   `WHERE "column1" = $1 OR "column2" < $2`,
 			expectedArgs:  []interface{}{"value1", 20},
 			expectedError: nil,
 		},
@@ -92,20 +93,20 @@ func TestWhereGroups(t *testing.T) {
 
 				group1 := querybuilder.NewWhereGroup(querybuilder.AND)
 				group1.Add(fields.NewFieldCondition("column1", fields.Equals, "value1"))
-				wg.Add(*group1)
+				wg.Add(group1)
 
 				group2 := querybuilder.NewWhereGroup(querybuilder.OR)
 				group2.Add(fields.NewFieldCondition("column2", fields.GreaterThan, 10))
-				wg.Add(*group2)
+				wg.Add(group2)
 
 				group3 := querybuilder.NewWhereGroup(querybuilder.AND)
 				group3.Add(fields.NewFieldCondition("column3", fields.LessThan, 20))
-				wg.Add(*group3)
+				wg.Add(group3)
 
 				return wg
 			},
 			paramOffset:   1,
-			expectedSQL:   "WHERE column1 = $1 OR column2 > $2 AND column3 < $3",
+			expectedSQL:   `WHERE ("column1" = $1 OR "column2" > $2) AND "column3" < $3`,
 			expectedArgs:  []interface{}{"value1", 10, 20},
 			expectedError: nil,
 		},
@@ -119,11 +120,11 @@ func TestWhereGroups(t *testing.T) {
 				group.Add(fields.NewFieldCondition("column2", fields.GreaterThan, 10))
 				group.Add(fields.NewFieldCondition("column3", fields.Like, "%pattern%"))
 
-				wg.Add(*group)
+				wg.Add(group)
 				return wg
 			},
 			paramOffset:   1,
-			expectedSQL:   "WHERE (column1 = $1 AND column2 > $2 AND column3 LIKE $3)",
+			expectedSQL:   `WHERE ("column1" = $1 AND "column2" > $2 AND "column3" LIKE $3)`,
 			expectedArgs:  []interface{}{"value1", 10, "%pattern%"},
 			expectedError: nil,
 		},
@@ -133,11 +134,11 @@ func TestWhereGroups(t *testing.T) {
 				wg := NewWhereGroups()
 				group := querybuilder.NewWhereGroup(querybuilder.AND)
 				group.Add(fields.NewFieldCondition("column1", fields.Equals, "value1"))
-				wg.Add(*group)
+				wg.Add(group)
 				return wg
 			},
 			paramOffset:   5,
-			expectedSQL:   "WHERE column1 = $5",
+			expectedSQL:   `WHERE "column1" = $5`,
 			expectedArgs:  []interface{}{"value1"},
 			expectedError: nil,
 		},
@@ -150,18 +151,18 @@ func TestWhereGroups(t *testing.T) {
 				group1 := querybuilder.NewWhereGroup(querybuilder.AND)
 				group1.Add(fields.NewFieldCondition("id", fields.Equals, 1))
 				group1.Add(fields.NewFieldCondition("status", fields.Equals, "active"))
-				wg.Add(*group1)
+				wg.Add(group1)
 
 				// Second group (price > 100 OR category = 'premium')
 				group2 := querybuilder.NewWhereGroup(querybuilder.OR)
 				group2.Add(fields.NewFieldCondition("price", fields.GreaterThan, 100))
 				group2.Add(fields.NewFieldCondition("category", fields.Equals, "premium"))
-				wg.Add(*group2)
+				wg.Add(group2)
 
 				return wg
 			},
 			paramOffset:   1,
-			expectedSQL:   "WHERE (id = $1 AND status = $2) OR (price > $3 OR category = $4)",
+			expectedSQL:   `WHERE ("id" = $1 AND "status" = $2) OR ("price" > $3 OR "category" = $4)`,
 			expectedArgs:  []interface{}{1, "active", 100, "premium"},
 			expectedError: nil,
 		},
@@ -170,7 +171,7 @@ func TestWhereGroups(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			wg := tt.buildGroups()
-			sql, args, err := wg.Build(tt.paramOffset)
+			sql, args, err := wg.Build(dialect.Postgres, tt.paramOffset)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
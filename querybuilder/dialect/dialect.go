@@ -0,0 +1,154 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LimitSyntax describes how a dialect restricts the number of returned rows
+type LimitSyntax string
+
+const (
+	// LimitOffset renders LIMIT n OFFSET m
+	LimitOffset LimitSyntax = "LIMIT_OFFSET"
+	// OffsetFetch renders OFFSET m ROWS FETCH NEXT n ROWS ONLY, or TOP (n)
+	// when there is no ORDER BY and no offset
+	OffsetFetch LimitSyntax = "OFFSET_FETCH"
+)
+
+// Features lists the optional SQL features a dialect supports natively.
+// Clauses emulate or reject what is missing.
+type Features struct {
+	FoldLowerCase   bool        // Names are lowercased before quoting to match what unquoted names fold to
+	ILike           bool        // ILIKE operator, otherwise LOWER(x) LIKE LOWER(y)
+	BooleanTest     bool        // x IS TRUE and x IS FALSE, otherwise COALESCE(x, 0) = 1
+	DistinctFrom    bool        // x IS DISTINCT FROM y, otherwise MySQL's null-safe x <=> y
+	PatternMatch    bool        // SIMILAR TO and the ~ and ~* regular expression operators
+	JSONOperators   bool        // Array and JSONB operators @>, <@, &&, ? and ->>
+	ArrayParameters bool        // x = ANY($1) with one array parameter, otherwise IN lists are expanded
+	NullsOrder      bool        // NULLS FIRST/LAST in ORDER BY, otherwise a CASE sort key
+	RowComparison   bool        // (a, b) > (x, y), otherwise expanded with OR
+	JoinUsing       bool        // JOIN ... USING (col)
+	FullJoin        bool        // FULL JOIN
+	AggregateFilter bool        // SUM(x) FILTER (WHERE ...), otherwise SUM(CASE WHEN ... THEN x END)
+	GroupByPosition bool        // GROUP BY 1, a select column by position
+	Limit           LimitSyntax // Row limiting syntax
+	UnboundedLimit  string      // LIMIT value meaning "all rows" when only OFFSET is set, empty if OFFSET may stand alone
+}
+
+// Dialect describes the SQL differences between database engines
+type Dialect interface {
+	// Name returns the dialect name used in error messages, e.g. postgres
+	Name() string
+	// Placeholder returns the bind parameter for a 1-based argument position
+	Placeholder(position int) string
+	// QuoteIdentifier quotes a single identifier part such as a table or column name
+	QuoteIdentifier(part string) string
+	// Features returns the optional features supported by the dialect
+	Features() Features
+}
+
+// Dialects supported out of the box
+var (
+	Postgres  Dialect = postgres{}
+	MySQL     Dialect = mysql{}
+	SQLite    Dialect = sqlite{}
+	SQLServer Dialect = sqlserver{}
+
+	// PostgresFolded lowercases names before quoting them, so CoaDescription
+	// names the column coadescription as it would unquoted. Postgres quotes
+	// names as written, which keeps mixed case columns reachable.
+	PostgresFolded Dialect = postgres{foldLowerCase: true}
+)
+
+type postgres struct {
+	foldLowerCase bool
+}
+
+func (postgres) Name() string { return "postgres" }
+
+func (postgres) Placeholder(position int) string { return fmt.Sprintf("$%d", position) }
+
+func (postgres) QuoteIdentifier(part string) string { return quote(part, `"`, `"`) }
+
+func (p postgres) Features() Features {
+	return Features{
+		FoldLowerCase:   p.foldLowerCase,
+		ILike:           true,
+		BooleanTest:     true,
+		DistinctFrom:    true,
+		PatternMatch:    true,
+		JSONOperators:   true,
+		ArrayParameters: true,
+		NullsOrder:      true,
+		RowComparison:   true,
+		JoinUsing:       true,
+		FullJoin:        true,
+		AggregateFilter: true,
+		GroupByPosition: true,
+		Limit:           LimitOffset,
+	}
+}
+
+type mysql struct{}
+
+func (mysql) Name() string { return "mysql" }
+
+func (mysql) Placeholder(int) string { return "?" }
+
+func (mysql) QuoteIdentifier(part string) string { return quote(part, "`", "`") }
+
+func (mysql) Features() Features {
+	return Features{
+		BooleanTest:     true,
+		RowComparison:   true,
+		JoinUsing:       true,
+		GroupByPosition: true,
+		Limit:           LimitOffset,
+		UnboundedLimit:  "18446744073709551615", // Largest LIMIT MySQL accepts
+	}
+}
+
+type sqlite struct{}
+
+func (sqlite) Name() string { return "sqlite" }
+
+func (sqlite) Placeholder(int) string { return "?" }
+
+func (sqlite) QuoteIdentifier(part string) string { return quote(part, `"`, `"`) }
+
+func (sqlite) Features() Features {
+	return Features{
+		BooleanTest:     true,
+		DistinctFrom:    true,
+		NullsOrder:      true,
+		RowComparison:   true,
+		JoinUsing:       true,
+		FullJoin:        true,
+		AggregateFilter: true,
+		GroupByPosition: true,
+		Limit:           LimitOffset,
+		UnboundedLimit:  "-1",
+	}
+}
+
+type sqlserver struct{}
+
+func (sqlserver) Name() string { return "sqlserver" }
+
+func (sqlserver) Placeholder(position int) string { return fmt.Sprintf("@p%d", position) }
+
+func (sqlserver) QuoteIdentifier(part string) string { return quote(part, "[", "]") }
+
+func (sqlserver) Features() Features {
+	return Features{
+		DistinctFrom: true,
+		FullJoin:     true,
+		Limit:        OffsetFetch,
+	}
+}
+
+// quote wraps part in the quote characters, doubling any closing quote inside it
+func quote(part, open, close string) string {
+	return open + strings.ReplaceAll(part, close, close+close) + close
+}
@@ -0,0 +1,40 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialects(t *testing.T) {
+	tests := []struct {
+		dialect             Dialect
+		expectedPlaceholder string
+		expectedQuoted      string
+	}{
+		{dialect: Postgres, expectedPlaceholder: "$3", expectedQuoted: `"amount"`},
+		{dialect: MySQL, expectedPlaceholder: "?", expectedQuoted: "`amount`"},
+		{dialect: SQLite, expectedPlaceholder: "?", expectedQuoted: `"amount"`},
+		{dialect: SQLServer, expectedPlaceholder: "@p3", expectedQuoted: "[amount]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			assert.Equal(t, tt.expectedPlaceholder, tt.dialect.Placeholder(3))
+			assert.Equal(t, tt.expectedQuoted, tt.dialect.QuoteIdentifier("amount"))
+		})
+	}
+}
+
+func TestQuoteIdentifier_EscapesClosingQuote(t *testing.T) {
+	assert.Equal(t, `"a""b"`, Postgres.QuoteIdentifier(`a"b`))
+	assert.Equal(t, "`a``b`", MySQL.QuoteIdentifier("a`b"))
+	assert.Equal(t, "[a]]b]", SQLServer.QuoteIdentifier("a]b"))
+}
+
+func TestPostgresFolded(t *testing.T) {
+	assert.False(t, Postgres.Features().FoldLowerCase)
+	assert.True(t, PostgresFolded.Features().FoldLowerCase)
+	assert.Equal(t, Postgres.Features().Limit, PostgresFolded.Features().Limit)
+	assert.Equal(t, "postgres", PostgresFolded.Name())
+}
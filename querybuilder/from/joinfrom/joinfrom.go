@@ -0,0 +1,158 @@
+package joinfrom
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Join represents a single joined table
+type Join struct {
+	Type  querybuilder.JoinType
+	Table string
+	Alias string
+	On    querybuilder.QueryCondition // ON condition, mutually exclusive with Using
+	Using []string                    // USING column list
+}
+
+// JoinFrom implements a FROM clause with joined tables
+type JoinFrom struct {
+	Table string
+	Alias string
+	Joins []Join
+}
+
+// Verify interface implementation at compile time
+var _ querybuilder.FromClause = (*JoinFrom)(nil)
+
+func NewJoinFrom(table, alias string) *JoinFrom {
+	return &JoinFrom{
+		Table: table,
+		Alias: alias,
+		Joins: make([]Join, 0),
+	}
+}
+
+// Join adds a join with an ON condition; CROSS joins take a nil condition
+func (f *JoinFrom) Join(joinType querybuilder.JoinType, table, alias string, on querybuilder.QueryCondition) *JoinFrom {
+	f.Joins = append(f.Joins, Join{
+		Type:  joinType,
+		Table: table,
+		Alias: alias,
+		On:    on,
+	})
+	return f
+}
+
+// JoinUsing adds a join matching the given columns with USING
+func (f *JoinFrom) JoinUsing(joinType querybuilder.JoinType, table, alias string, columns ...string) *JoinFrom {
+	f.Joins = append(f.Joins, Join{
+		Type:  joinType,
+		Table: table,
+		Alias: alias,
+		Using: columns,
+	})
+	return f
+}
+
+func (f *JoinFrom) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	if f.Table == "" {
+		return "", nil, errors.New("no table specified for from")
+	}
+
+	table, err := tableRef(d, f.Table, f.Alias)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parts := []string{fmt.Sprintf("FROM %s", table)}
+	var args []interface{}
+
+	for _, join := range f.Joins {
+		clause, joinArgs, err := join.build(d, paramOffset+len(args))
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, clause)
+		args = append(args, joinArgs...)
+	}
+
+	return strings.Join(parts, " "), args, nil
+}
+
+func (j Join) build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	switch j.Type {
+	case querybuilder.InnerJoin, querybuilder.LeftJoin, querybuilder.RightJoin,
+		querybuilder.FullJoin, querybuilder.CrossJoin:
+	default:
+		return "", nil, fmt.Errorf("invalid join type: %s", j.Type)
+	}
+	if j.Type == querybuilder.FullJoin && !d.Features().FullJoin {
+		return "", nil, fmt.Errorf("%s is not supported by %s", j.Type, d.Name())
+	}
+	if j.Table == "" {
+		return "", nil, fmt.Errorf("no table specified for %s", j.Type)
+	}
+
+	table, err := tableRef(d, j.Table, j.Alias)
+	if err != nil {
+		return "", nil, err
+	}
+	clause := fmt.Sprintf("%s %s", j.Type, table)
+
+	if j.Type == querybuilder.CrossJoin {
+		if j.On != nil || len(j.Using) > 0 {
+			return "", nil, fmt.Errorf("CROSS JOIN %s cannot have ON or USING", j.Table)
+		}
+		return clause, nil, nil
+	}
+
+	if j.On != nil && len(j.Using) > 0 {
+		return "", nil, fmt.Errorf("%s %s cannot have both ON and USING", j.Type, j.Table)
+	}
+
+	if len(j.Using) > 0 {
+		if !d.Features().JoinUsing {
+			return "", nil, fmt.Errorf("%s %s: USING is not supported by %s", j.Type, j.Table, d.Name())
+		}
+		columns := make([]string, len(j.Using))
+		for i, column := range j.Using {
+			quoted, err := identifier.QuoteAlias(d, column)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid USING column for %s: %w", j.Table, err)
+			}
+			columns[i] = quoted
+		}
+		return fmt.Sprintf("%s USING (%s)", clause, strings.Join(columns, ", ")), nil, nil
+	}
+
+	if j.On == nil {
+		return "", nil, fmt.Errorf("%s %s requires an ON condition or USING columns", j.Type, j.Table)
+	}
+	onSQL, onArgs, err := j.On.Build(d, paramOffset)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build ON condition for %s: %w", j.Table, err)
+	}
+	if onSQL == "" {
+		return "", nil, fmt.Errorf("%s %s requires an ON condition or USING columns", j.Type, j.Table)
+	}
+	return fmt.Sprintf("%s ON %s", clause, onSQL), onArgs, nil
+}
+
+func tableRef(d dialect.Dialect, table, alias string) (string, error) {
+	quotedTable, err := identifier.Quote(d, table)
+	if err != nil {
+		return "", err
+	}
+	if alias == "" {
+		return quotedTable, nil
+	}
+	quotedAlias, err := identifier.QuoteAlias(d, alias)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s AS %s", quotedTable, quotedAlias), nil
+}
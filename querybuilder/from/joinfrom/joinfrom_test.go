@@ -0,0 +1,148 @@
+package joinfrom
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinFrom(t *testing.T) {
+	tests := []struct {
+		name          string
+		buildFrom     func() *JoinFrom
+		paramOffset   int
+		expectedSQL   string
+		expectedArgs  []interface{}
+		expectedError string
+	}{
+		{
+			name: "Table With Alias",
+			buildFrom: func() *JoinFrom {
+				return NewJoinFrom("transactions", "t")
+			},
+			paramOffset: 1,
+			expectedSQL: `FROM "transactions" AS "t"`,
+		},
+		{
+			name: "Left Join On Columns",
+			buildFrom: func() *JoinFrom {
+				return NewJoinFrom("transactions", "t").
+					Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+						fields.NewColumnCondition("t.account_code", fields.Equals, "coa.account_code"))
+			},
+			paramOffset: 1,
+			expectedSQL: `FROM "transactions" AS "t" LEFT JOIN "chart_of_accounts" AS "coa" ON "t"."account_code" = "coa"."account_code"`,
+		},
+		{
+			name: "All Join Types",
+			buildFrom: func() *JoinFrom {
+				on := fields.NewColumnCondition("a.id", fields.Equals, "b.a_id")
+				return NewJoinFrom("a", "").
+					Join(querybuilder.InnerJoin, "b", "", on).
+					Join(querybuilder.RightJoin, "c", "", on).
+					Join(querybuilder.FullJoin, "d", "", on).
+					Join(querybuilder.CrossJoin, "e", "", nil)
+			},
+			paramOffset: 1,
+			expectedSQL: `FROM "a" INNER JOIN "b" ON "a"."id" = "b"."a_id" RIGHT JOIN "c" ON "a"."id" = "b"."a_id" FULL JOIN "d" ON "a"."id" = "b"."a_id" CROSS JOIN "e"`,
+		},
+		{
+			name: "Join Using",
+			buildFrom: func() *JoinFrom {
+				return NewJoinFrom("transactions", "").
+					JoinUsing(querybuilder.InnerJoin, "departments", "d", "department_id", "company_id")
+			},
+			paramOffset: 1,
+			expectedSQL: `FROM "transactions" INNER JOIN "departments" AS "d" USING ("department_id", "company_id")`,
+		},
+		{
+			name: "Parameterized On Conditions",
+			buildFrom: func() *JoinFrom {
+				rates := querybuilder.NewWhereGroup(querybuilder.AND)
+				rates.Add(fields.NewColumnCondition("t.currency", fields.Equals, "r.currency"))
+				rates.Add(fields.NewFieldCondition("r.rate_type", fields.Equals, "SPOT"))
+
+				coa := querybuilder.NewWhereGroup(querybuilder.AND)
+				coa.Add(fields.NewColumnCondition("t.account_code", fields.Equals, "coa.account_code"))
+				coa.Add(fields.NewFieldCondition("coa.is_active", fields.Equals, true))
+
+				return NewJoinFrom("transactions", "t").
+					Join(querybuilder.LeftJoin, "fx_rates", "r", rates).
+					Join(querybuilder.InnerJoin, "chart_of_accounts", "coa", coa)
+			},
+			paramOffset:  3,
+			expectedSQL:  `FROM "transactions" AS "t" LEFT JOIN "fx_rates" AS "r" ON ("t"."currency" = "r"."currency" AND "r"."rate_type" = $3) INNER JOIN "chart_of_accounts" AS "coa" ON ("t"."account_code" = "coa"."account_code" AND "coa"."is_active" = $4)`,
+			expectedArgs: []interface{}{"SPOT", true},
+		},
+		{
+			name: "Missing Base Table",
+			buildFrom: func() *JoinFrom {
+				return NewJoinFrom("", "")
+			},
+			paramOffset:   1,
+			expectedError: "no table specified for from",
+		},
+		{
+			name: "Missing On Condition",
+			buildFrom: func() *JoinFrom {
+				return NewJoinFrom("a", "").Join(querybuilder.InnerJoin, "b", "", nil)
+			},
+			paramOffset:   1,
+			expectedError: "INNER JOIN b requires an ON condition or USING columns",
+		},
+		{
+			name: "Cross Join With Condition",
+			buildFrom: func() *JoinFrom {
+				return NewJoinFrom("a", "").
+					Join(querybuilder.CrossJoin, "b", "", fields.NewColumnCondition("a.id", fields.Equals, "b.id"))
+			},
+			paramOffset:   1,
+			expectedError: "CROSS JOIN b cannot have ON or USING",
+		},
+		{
+			name: "Invalid Join Type",
+			buildFrom: func() *JoinFrom {
+				return NewJoinFrom("a", "").JoinUsing("SIDEWAYS JOIN", "b", "", "id")
+			},
+			paramOffset:   1,
+			expectedError: "invalid join type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.buildFrom().Build(dialect.Postgres, tt.paramOffset)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestJoinFrom_DialectSupport(t *testing.T) {
+	_, _, err := NewJoinFrom("a", "").JoinUsing(querybuilder.InnerJoin, "b", "", "id").Build(dialect.SQLServer, 1)
+	assert.EqualError(t, err, "INNER JOIN b: USING is not supported by sqlserver")
+
+	_, _, err = NewJoinFrom("a", "").
+		Join(querybuilder.FullJoin, "b", "", fields.NewColumnCondition("a.id", fields.Equals, "b.id")).
+		Build(dialect.MySQL, 1)
+	assert.EqualError(t, err, "FULL JOIN is not supported by mysql")
+
+	sql, _, err := NewJoinFrom("transactions", "t").
+		Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+			fields.NewColumnCondition("t.account_code", fields.Equals, "coa.account_code")).
+		Build(dialect.SQLServer, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "FROM [transactions] AS [t] LEFT JOIN [chart_of_accounts] AS [coa] ON [t].[account_code] = [coa].[account_code]", sql)
+}
@@ -1,6 +1,11 @@
 package simplefrom
 
-import "fmt"
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"errors"
+	"fmt"
+)
 
 // SimpleFrom implements a basic FROM clause
 type SimpleFrom struct {
@@ -11,6 +16,13 @@ func NewSimpleFrom(table string) *SimpleFrom {
 	return &SimpleFrom{Table: table}
 }
 
-func (f *SimpleFrom) Build() string {
-	return fmt.Sprintf("FROM %s", f.Table)
+func (f *SimpleFrom) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	if f.Table == "" {
+		return "", nil, errors.New("no table specified for from")
+	}
+	table, err := identifier.Quote(d, f.Table)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("FROM %s", table), nil, nil
 }
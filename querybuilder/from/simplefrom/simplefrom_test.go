@@ -1,6 +1,7 @@
 package simplefrom
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,31 +17,31 @@ func TestSimpleFrom(t *testing.T) {
 		{
 			name:           "Basic Table Name",
 			tableName:      "users",
-			expectedOutput: "FROM users",
+			expectedOutput: `FROM "users"`,
 			description:    "Should handle basic table name correctly",
 		},
 		{
 			name:           "Table Name with Schema",
 			tableName:      "public.users",
-			expectedOutput: "FROM public.users",
+			expectedOutput: `FROM "public"."users"`,
 			description:    "Should handle schema-qualified table names",
 		},
 		{
 			name:           "Table Name with Special Characters",
 			tableName:      "user_data",
-			expectedOutput: "FROM user_data",
+			expectedOutput: `FROM "user_data"`,
 			description:    "Should handle table names with underscores",
 		},
 		{
 			name:           "Table Name with Mixed Case",
 			tableName:      "UserData",
-			expectedOutput: "FROM UserData",
+			expectedOutput: `FROM "UserData"`,
 			description:    "Should preserve case in table names",
 		},
 		{
 			name:           "Complex Table Name",
 			tableName:      "myschema.user_data_2023",
-			expectedOutput: "FROM myschema.user_data_2023",
+			expectedOutput: `FROM "myschema"."user_data_2023"`,
 			description:    "Should handle complex table names with schema, underscores and numbers",
 		},
 	}
@@ -52,9 +53,11 @@ func TestSimpleFrom(t *testing.T) {
 			simpleFrom := NewSimpleFrom(tt.tableName)
 
 			// Execute test
-			result := simpleFrom.Build()
+			result, args, err := simpleFrom.Build(dialect.Postgres, 1)
 
 			// Assert results
+			assert.NoError(t, err)
+			assert.Nil(t, args, "SimpleFrom should not produce arguments")
 			assert.Equal(t, tt.expectedOutput, result,
 				"Expected '%s' but got '%s'",
 				tt.expectedOutput, result)
@@ -83,11 +86,26 @@ func TestSimpleFromImplementation(t *testing.T) {
 		simpleFrom := NewSimpleFrom("test_table")
 
 		// Execute test multiple times
-		result1 := simpleFrom.Build()
-		result2 := simpleFrom.Build()
+		result1, _, err1 := simpleFrom.Build(dialect.Postgres, 1)
+		result2, _, err2 := simpleFrom.Build(dialect.Postgres, 5)
+
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
 
 		// Assert results
 		assert.Equal(t, result1, result2,
 			"Multiple Build() calls should return consistent results")
 	})
 }
+
+func TestSimpleFromEmptyTable(t *testing.T) {
+	t.Run("Empty Table Name", func(t *testing.T) {
+		_, _, err := NewSimpleFrom("").Build(dialect.Postgres, 1)
+		assert.Error(t, err, "Empty table name should return an error")
+	})
+
+	t.Run("Invalid Table Name", func(t *testing.T) {
+		_, _, err := NewSimpleFrom("users; DROP TABLE users").Build(dialect.Postgres, 1)
+		assert.Error(t, err, "Invalid table name should return an error")
+	})
+}
@@ -0,0 +1,45 @@
+package simplegroupby
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"fmt"
+	"strings"
+)
+
+// SimpleGroupBy implements a basic GROUP BY clause
+type SimpleGroupBy struct {
+	Fields []identifier.Expr
+}
+
+func NewSimpleGroupBy(fields ...string) *SimpleGroupBy {
+	return &SimpleGroupBy{Fields: identifier.Names(fields...)}
+}
+
+// Add appends fields to the GROUP BY list
+func (g *SimpleGroupBy) Add(fields ...string) *SimpleGroupBy {
+	g.Fields = append(g.Fields, identifier.Names(fields...)...)
+	return g
+}
+
+// AddExpr appends field or raw expressions to the GROUP BY list
+func (g *SimpleGroupBy) AddExpr(exprs ...identifier.Expr) *SimpleGroupBy {
+	g.Fields = append(g.Fields, exprs...)
+	return g
+}
+
+func (g *SimpleGroupBy) Build(d dialect.Dialect) (string, error) {
+	if len(g.Fields) == 0 {
+		return "", fmt.Errorf("no fields specified for group by")
+	}
+
+	fields := make([]string, len(g.Fields))
+	for i, field := range g.Fields {
+		sql, err := field.Build(d)
+		if err != nil {
+			return "", err
+		}
+		fields[i] = sql
+	}
+	return fmt.Sprintf("GROUP BY %s", strings.Join(fields, ", ")), nil
+}
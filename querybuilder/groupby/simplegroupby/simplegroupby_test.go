@@ -0,0 +1,88 @@
+package simplegroupby
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimpleGroupBy(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      []string
+		expected    string
+		expectError bool
+		description string
+	}{
+		{
+			name:        "Single field",
+			fields:      []string{"department"},
+			expected:    `GROUP BY "department"`,
+			description: "Should group by a single field",
+		},
+		{
+			name:        "Multiple fields",
+			fields:      []string{"account_type", "department"},
+			expected:    `GROUP BY "account_type", "department"`,
+			description: "Should group by multiple fields in order",
+		},
+		{
+			name:        "Table qualified fields",
+			fields:      []string{"coa.coadescription", "t.cost_center"},
+			expected:    `GROUP BY "coa"."coadescription", "t"."cost_center"`,
+			description: "Should handle table qualified field names",
+		},
+		{
+			name:        "Invalid field",
+			fields:      []string{"department; DROP TABLE users"},
+			expectError: true,
+			description: "Should reject fields that are not valid identifiers",
+		},
+		{
+			name:        "No fields",
+			fields:      []string{},
+			expectError: true,
+			description: "Should return error when no fields are specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log(tt.description)
+			groupBy := NewSimpleGroupBy(tt.fields...)
+			result, err := groupBy.Build(dialect.Postgres)
+
+			if tt.expectError {
+				assert.Error(t, err, "Should return an error")
+				return
+			}
+
+			assert.NoError(t, err, "Should not return an error")
+			assert.Equal(t, tt.expected, result,
+				"Built clause should match expected SQL")
+		})
+	}
+}
+
+func TestSimpleGroupBy_Add(t *testing.T) {
+	t.Run("Add Fields", func(t *testing.T) {
+		groupBy := NewSimpleGroupBy("department").Add("cost_center", "region")
+
+		result, err := groupBy.Build(dialect.Postgres)
+		assert.NoError(t, err)
+		assert.Equal(t, `GROUP BY "department", "cost_center", "region"`, result)
+	})
+}
+
+func TestSimpleGroupBy_AddExpr(t *testing.T) {
+	t.Run("Raw Expression", func(t *testing.T) {
+		groupBy := NewSimpleGroupBy("department").
+			AddExpr(identifier.UnsafeRaw("date_trunc('month', posted_at)"))
+
+		result, err := groupBy.Build(dialect.Postgres)
+		assert.NoError(t, err)
+		assert.Equal(t, `GROUP BY "department", date_trunc('month', posted_at)`, result)
+	})
+}
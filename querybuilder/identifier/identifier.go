@@ -0,0 +1,132 @@
+package identifier
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxPartLength is the Postgres identifier length limit (NAMEDATALEN - 1)
+const maxPartLength = 63
+
+// maxParts allows at most schema.table.column
+const maxParts = 3
+
+var partPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// Identifier is a validated, possibly qualified, SQL identifier such as
+// amount, coa.coadescription or public.transactions.amount
+type Identifier struct {
+	Parts []string
+}
+
+// Parse validates a dotted identifier. The last part may be * to select all
+// columns of a table (e.g. t.*).
+func Parse(name string) (Identifier, error) {
+	if name == "" {
+		return Identifier{}, fmt.Errorf("identifier must not be empty")
+	}
+
+	parts := strings.Split(name, ".")
+	if len(parts) > maxParts {
+		return Identifier{}, fmt.Errorf("identifier %q has more than %d parts", name, maxParts)
+	}
+
+	for i, part := range parts {
+		if part == "*" && i == len(parts)-1 {
+			continue
+		}
+		if len(part) > maxPartLength {
+			return Identifier{}, fmt.Errorf("identifier %q exceeds %d characters", part, maxPartLength)
+		}
+		if !partPattern.MatchString(part) {
+			return Identifier{}, fmt.Errorf("invalid identifier %q", name)
+		}
+	}
+	return Identifier{Parts: parts}, nil
+}
+
+// Quote renders the identifier with every part quoted for the dialect, e.g.
+// "coa"."coadescription". Quoting preserves case unless the dialect opts in to
+// folding, see dialect.PostgresFolded.
+func (id Identifier) Quote(d dialect.Dialect) string {
+	fold := d.Features().FoldLowerCase
+	quoted := make([]string, len(id.Parts))
+	for i, part := range id.Parts {
+		if part == "*" {
+			quoted[i] = part
+			continue
+		}
+		if fold {
+			part = strings.ToLower(part)
+		}
+		quoted[i] = d.QuoteIdentifier(part)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// String returns the unquoted dotted form
+func (id Identifier) String() string {
+	return strings.Join(id.Parts, ".")
+}
+
+// Quote validates and quotes a dotted identifier
+func Quote(d dialect.Dialect, name string) (string, error) {
+	id, err := Parse(name)
+	if err != nil {
+		return "", err
+	}
+	return id.Quote(d), nil
+}
+
+// QuoteAlias validates and quotes an unqualified name such as a column alias
+// or table alias
+func QuoteAlias(d dialect.Dialect, alias string) (string, error) {
+	id, err := Parse(alias)
+	if err != nil {
+		return "", err
+	}
+	if len(id.Parts) != 1 || id.Parts[0] == "*" {
+		return "", fmt.Errorf("invalid alias %q", alias)
+	}
+	return id.Quote(d), nil
+}
+
+// Expr is a SQL fragment that is either a field name, validated and quoted
+// when built, or a raw expression created through UnsafeRaw
+type Expr struct {
+	Text string
+	Raw  bool
+}
+
+// Name creates an expression for a field name that is validated and quoted
+func Name(name string) Expr {
+	return Expr{Text: name}
+}
+
+// Names creates field name expressions for each name
+func Names(names ...string) []Expr {
+	exprs := make([]Expr, len(names))
+	for i, name := range names {
+		exprs[i] = Name(name)
+	}
+	return exprs
+}
+
+// UnsafeRaw creates an expression that is emitted verbatim. It must never be
+// built from user input since it bypasses identifier validation.
+func UnsafeRaw(sql string) Expr {
+	return Expr{Text: sql, Raw: true}
+}
+
+// Build renders the expression, returning an error for invalid field names
+func (e Expr) Build(d dialect.Dialect) (string, error) {
+	if e.Raw {
+		if strings.TrimSpace(e.Text) == "" {
+			return "", fmt.Errorf("raw expression must not be empty")
+		}
+		return e.Text, nil
+	}
+	return Quote(d, e.Text)
+}
@@ -0,0 +1,129 @@
+package identifier
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      string
+		expectedError string
+	}{
+		{name: "Simple column", input: "amount", expected: `"amount"`},
+		{name: "Table qualified", input: "coa.coadescription", expected: `"coa"."coadescription"`},
+		{name: "Schema qualified", input: "public.transactions.amount", expected: `"public"."transactions"."amount"`},
+		{name: "Mixed case preserved", input: "UserData", expected: `"UserData"`},
+		{name: "Digits and dollar", input: "user_data_2023$", expected: `"user_data_2023$"`},
+		{name: "Star", input: "*", expected: "*"},
+		{name: "Qualified star", input: "t.*", expected: `"t".*`},
+		{name: "Empty", input: "", expectedError: "identifier must not be empty"},
+		{name: "Too many parts", input: "a.b.c.d", expectedError: "has more than 3 parts"},
+		{name: "Empty part", input: "a..b", expectedError: "invalid identifier"},
+		{name: "Leading digit", input: "1column", expectedError: "invalid identifier"},
+		{name: "Whitespace", input: " id ", expectedError: "invalid identifier"},
+		{name: "Injection attempt", input: "id; DROP TABLE users", expectedError: "invalid identifier"},
+		{name: "Embedded quote", input: `id"--`, expectedError: "invalid identifier"},
+		{name: "Function call", input: "COUNT(*)", expectedError: "invalid identifier"},
+		{name: "Star not last", input: "*.id", expectedError: "invalid identifier"},
+		{
+			name:          "Too long",
+			input:         "a234567890123456789012345678901234567890123456789012345678901234",
+			expectedError: "exceeds 63 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Quote(dialect.Postgres, tt.input)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestQuote_Dialects(t *testing.T) {
+	tests := []struct {
+		dialect           dialect.Dialect
+		expected          string
+		expectedMixedCase string
+	}{
+		{dialect: dialect.Postgres, expected: `"coa"."coadescription"`, expectedMixedCase: `"Coa"."CoaDescription"`},
+		{dialect: dialect.PostgresFolded, expected: `"coa"."coadescription"`, expectedMixedCase: `"coa"."coadescription"`},
+		{dialect: dialect.MySQL, expected: "`coa`.`coadescription`", expectedMixedCase: "`Coa`.`CoaDescription`"},
+		{dialect: dialect.SQLite, expected: `"coa"."coadescription"`, expectedMixedCase: `"Coa"."CoaDescription"`},
+		{dialect: dialect.SQLServer, expected: "[coa].[coadescription]", expectedMixedCase: "[Coa].[CoaDescription]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			result, err := Quote(tt.dialect, "coa.coadescription")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+
+			// Case is preserved unless the dialect folds it
+			result, err = Quote(tt.dialect, "Coa.CoaDescription")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMixedCase, result)
+		})
+	}
+}
+
+func TestQuoteAlias(t *testing.T) {
+	quoted, err := QuoteAlias(dialect.Postgres, "total_amount")
+	assert.NoError(t, err)
+	assert.Equal(t, `"total_amount"`, quoted)
+
+	_, err = QuoteAlias(dialect.Postgres, "t.total")
+	assert.Error(t, err)
+
+	_, err = QuoteAlias(dialect.Postgres, "*")
+	assert.Error(t, err)
+}
+
+func TestExpr_Build(t *testing.T) {
+	tests := []struct {
+		name          string
+		expr          Expr
+		expected      string
+		expectedError string
+	}{
+		{name: "Name is quoted", expr: Name("t.amount"), expected: `"t"."amount"`},
+		{name: "Invalid name", expr: Name("amount + 1"), expectedError: "invalid identifier"},
+		{name: "Raw is verbatim", expr: UnsafeRaw("date_trunc('month', posted_at)"), expected: "date_trunc('month', posted_at)"},
+		{name: "Empty raw", expr: UnsafeRaw("  "), expectedError: "raw expression must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.expr.Build(dialect.Postgres)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	id, err := Parse("public.transactions")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"public", "transactions"}, id.Parts)
+	assert.Equal(t, "public.transactions", id.String())
+}
@@ -0,0 +1,12 @@
+package querybuilder
+
+// JoinType represents SQL join types
+type JoinType string
+
+const (
+	InnerJoin JoinType = "INNER JOIN"
+	LeftJoin  JoinType = "LEFT JOIN"
+	RightJoin JoinType = "RIGHT JOIN"
+	FullJoin  JoinType = "FULL JOIN"
+	CrossJoin JoinType = "CROSS JOIN"
+)
@@ -0,0 +1,105 @@
+package querybuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"strings"
+)
+
+// CompoundCondition joins conditions with AND or OR and can be nested to any
+// depth, e.g. And(a, Or(b, And(c, Not(d)))) builds
+// a AND (b OR c AND NOT (d)).
+//
+// Parentheses are only written where precedence needs them: nested compounds
+// with the same operator are flattened and AND binds tighter than OR, so an
+// AND never needs them. Like every condition with a top level OR, an OR of
+// several conditions is parenthesized so it can be placed in any AND.
+type CompoundCondition struct {
+	Operator   LogicalOperator
+	Conditions []QueryCondition
+}
+
+// And returns a condition that holds when all conditions hold
+func And(conditions ...QueryCondition) *CompoundCondition {
+	return &CompoundCondition{Operator: AND, Conditions: conditions}
+}
+
+// Or returns a condition that holds when any of the conditions holds
+func Or(conditions ...QueryCondition) *CompoundCondition {
+	return &CompoundCondition{Operator: OR, Conditions: conditions}
+}
+
+// Build implements QueryCondition interface. Conditions that build no SQL are
+// skipped, so a compound of one condition builds that condition unchanged.
+func (cc *CompoundCondition) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	parts, args, err := cc.buildParts(d, paramOffset)
+	if err != nil || len(parts) == 0 {
+		return "", nil, err
+	}
+	if len(parts) == 1 {
+		return parts[0], args, nil
+	}
+	expression := strings.Join(parts, " "+string(cc.Operator)+" ")
+	if cc.Operator == OR {
+		expression = "(" + expression + ")"
+	}
+	return expression, args, nil
+}
+
+// buildParts builds the operands, inlining the operands of nested compounds
+// with the same operator
+func (cc *CompoundCondition) buildParts(d dialect.Dialect, paramOffset int) ([]string, []interface{}, error) {
+	var parts []string
+	var args []interface{}
+
+	for _, cond := range cc.Conditions {
+		if nested, ok := cond.(*CompoundCondition); ok && nested.Operator == cc.Operator {
+			nestedParts, nestedArgs, err := nested.buildParts(d, paramOffset+len(args))
+			if err != nil {
+				return nil, nil, err
+			}
+			parts = append(parts, nestedParts...)
+			args = append(args, nestedArgs...)
+			continue
+		}
+
+		clause, condArgs, err := cond.Build(d, paramOffset+len(args))
+		if err != nil {
+			return nil, nil, err
+		}
+		if clause != "" {
+			parts = append(parts, clause)
+			args = append(args, condArgs...)
+		}
+	}
+	return parts, args, nil
+}
+
+// NotCondition negates a condition, see Not
+type NotCondition struct {
+	Condition QueryCondition
+}
+
+// Not returns a condition that holds when condition does not. The negated
+// condition is always parenthesized: MySQL's HIGH_NOT_PRECEDENCE mode would
+// otherwise read NOT a BETWEEN b AND c as (NOT a) BETWEEN b AND c.
+func Not(condition QueryCondition) *NotCondition {
+	return &NotCondition{Condition: condition}
+}
+
+// Build implements QueryCondition interface
+func (nc *NotCondition) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	// A compound brings its own parentheses only for OR, write them once
+	if compound, ok := nc.Condition.(*CompoundCondition); ok {
+		parts, args, err := compound.buildParts(d, paramOffset)
+		if err != nil || len(parts) == 0 {
+			return "", nil, err
+		}
+		return "NOT (" + strings.Join(parts, " "+string(compound.Operator)+" ") + ")", args, nil
+	}
+
+	clause, args, err := nc.Condition.Build(d, paramOffset)
+	if err != nil || clause == "" {
+		return "", nil, err
+	}
+	return "NOT (" + clause + ")", args, nil
+}
@@ -0,0 +1,126 @@
+package querybuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// predicate builds column = placeholder, bound to the column name
+type predicate string
+
+func (p predicate) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	return string(p) + " = " + d.Placeholder(paramOffset), []interface{}{string(p)}, nil
+}
+
+func TestCompoundCondition_Build(t *testing.T) {
+	a, b, c, d := predicate("a"), predicate("b"), predicate("c"), predicate("d")
+	empty := MockCondition{}
+
+	tests := []struct {
+		name         string
+		condition    QueryCondition
+		expectedSQL  string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "AND",
+			condition:    And(a, b),
+			expectedSQL:  "a = $1 AND b = $2",
+			expectedArgs: []interface{}{"a", "b"},
+		},
+		{
+			name:         "OR is parenthesized",
+			condition:    Or(a, b),
+			expectedSQL:  "(a = $1 OR b = $2)",
+			expectedArgs: []interface{}{"a", "b"},
+		},
+		{
+			name:         "OR inside AND",
+			condition:    And(a, Or(b, c)),
+			expectedSQL:  "a = $1 AND (b = $2 OR c = $3)",
+			expectedArgs: []interface{}{"a", "b", "c"},
+		},
+		{
+			name:         "AND inside OR needs no parentheses",
+			condition:    Or(a, And(b, c)),
+			expectedSQL:  "(a = $1 OR b = $2 AND c = $3)",
+			expectedArgs: []interface{}{"a", "b", "c"},
+		},
+		{
+			name:         "Same operators are flattened",
+			condition:    Or(Or(a, b), Or(c, Or(d))),
+			expectedSQL:  "(a = $1 OR b = $2 OR c = $3 OR d = $4)",
+			expectedArgs: []interface{}{"a", "b", "c", "d"},
+		},
+		{
+			name:         "Deep nesting with NOT",
+			condition:    And(a, Or(b, And(c, Not(d)))),
+			expectedSQL:  "a = $1 AND (b = $2 OR c = $3 AND NOT (d = $4))",
+			expectedArgs: []interface{}{"a", "b", "c", "d"},
+		},
+		{
+			name:         "NOT of OR",
+			condition:    Not(Or(a, b)),
+			expectedSQL:  "NOT (a = $1 OR b = $2)",
+			expectedArgs: []interface{}{"a", "b"},
+		},
+		{
+			name:         "NOT of AND",
+			condition:    And(a, Not(And(b, c))),
+			expectedSQL:  "a = $1 AND NOT (b = $2 AND c = $3)",
+			expectedArgs: []interface{}{"a", "b", "c"},
+		},
+		{
+			name:         "Double NOT",
+			condition:    Not(Not(a)),
+			expectedSQL:  "NOT (NOT (a = $1))",
+			expectedArgs: []interface{}{"a"},
+		},
+		{
+			name:         "Single condition is unchanged",
+			condition:    Or(And(a)),
+			expectedSQL:  "a = $1",
+			expectedArgs: []interface{}{"a"},
+		},
+		{
+			name:         "Empty conditions are skipped",
+			condition:    Or(empty, And(empty, a), Not(empty), And()),
+			expectedSQL:  "a = $1",
+			expectedArgs: []interface{}{"a"},
+		},
+		{
+			name:        "Nothing to build",
+			condition:   Not(Or(empty, And())),
+			expectedSQL: "",
+		},
+		{
+			name:         "Inside a where group",
+			condition:    NewWhereGroup(AND).Add(a).Add(Or(b, c)),
+			expectedSQL:  "(a = $1 AND (b = $2 OR c = $3))",
+			expectedArgs: []interface{}{"a", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.condition.Build(dialect.Postgres, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestCompoundCondition_BuildError(t *testing.T) {
+	failing := MockCondition{err: errors.New("invalid field")}
+
+	_, _, err := And(predicate("a"), Or(predicate("b"), failing)).Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "invalid field")
+
+	_, _, err = Not(And(failing)).Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "invalid field")
+}
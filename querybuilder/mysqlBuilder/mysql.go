@@ -0,0 +1,13 @@
+package mysqlbuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+)
+
+// MySQLQueryBuilder builds MySQL queries with ? placeholders and backtick quoting
+type MySQLQueryBuilder = sqlbuilder.Builder
+
+func NewMySQLQueryBuilder() *MySQLQueryBuilder {
+	return sqlbuilder.New(dialect.MySQL)
+}
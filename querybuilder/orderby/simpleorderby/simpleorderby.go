@@ -0,0 +1,245 @@
+package simpleorderby
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"fmt"
+	"strings"
+)
+
+// Direction represents the sort direction of an ORDER BY key
+type Direction string
+
+const (
+	Asc  Direction = "ASC"
+	Desc Direction = "DESC"
+)
+
+// NullsOrder controls where NULL values are placed in the sort
+type NullsOrder string
+
+const (
+	NullsFirst NullsOrder = "NULLS FIRST"
+	NullsLast  NullsOrder = "NULLS LAST"
+)
+
+// KeyKind describes what an ORDER BY key refers to
+type KeyKind string
+
+const (
+	FieldKey   KeyKind = "FIELD"   // Column name or expression
+	AliasKey   KeyKind = "ALIAS"   // Output alias of the select list, e.g. an aggregate alias
+	OrdinalKey KeyKind = "ORDINAL" // 1-based position in the select list
+	RawKey     KeyKind = "RAW"     // Unchecked SQL expression, see UnsafeRaw
+)
+
+// OrderKey represents a single ORDER BY key
+type OrderKey struct {
+	Kind      KeyKind
+	Field     string // Field, alias or raw expression, unused for ordinal keys
+	Position  int    // Select list position for ordinal keys
+	Direction Direction
+	Nulls     NullsOrder
+}
+
+// Field creates a key ordering by a column or expression
+func Field(field string) OrderKey {
+	return OrderKey{Kind: FieldKey, Field: field}
+}
+
+// Alias creates a key ordering by an output alias of the select list
+func Alias(alias string) OrderKey {
+	return OrderKey{Kind: AliasKey, Field: alias}
+}
+
+// Ordinal creates a key ordering by the 1-based position in the select list
+func Ordinal(position int) OrderKey {
+	return OrderKey{Kind: OrdinalKey, Position: position}
+}
+
+// UnsafeRaw creates a key ordering by a raw SQL expression. It is emitted
+// verbatim and must never come from user input.
+func UnsafeRaw(expression string) OrderKey {
+	return OrderKey{Kind: RawKey, Field: expression}
+}
+
+// Asc returns a copy of the key sorted ascending
+func (k OrderKey) Asc() OrderKey {
+	k.Direction = Asc
+	return k
+}
+
+// Desc returns a copy of the key sorted descending
+func (k OrderKey) Desc() OrderKey {
+	k.Direction = Desc
+	return k
+}
+
+// NullsFirst returns a copy of the key placing NULLs first
+func (k OrderKey) NullsFirst() OrderKey {
+	k.Nulls = NullsFirst
+	return k
+}
+
+// NullsLast returns a copy of the key placing NULLs last
+func (k OrderKey) NullsLast() OrderKey {
+	k.Nulls = NullsLast
+	return k
+}
+
+// Build renders the key, e.g. total_amount DESC NULLS LAST. Dialects without
+// NULLS FIRST/LAST get an extra CASE sort key placing NULLs instead. A select
+// alias cannot be used inside that CASE, so alias keys fail there unless they
+// are built by a SimpleOrderBy that resolved the alias, see ResolveAliases.
+func (k OrderKey) Build(d dialect.Dialect) (string, error) {
+	return k.build(d, "")
+}
+
+// build renders the key, expression is the aggregate expression of an alias key
+func (k OrderKey) build(d dialect.Dialect, expression string) (string, error) {
+	var target string
+	var err error
+	switch k.Kind {
+	case FieldKey, AliasKey, RawKey:
+		if k.Field == "" {
+			return "", fmt.Errorf("order by key requires a field")
+		}
+		switch k.Kind {
+		case FieldKey:
+			target, err = identifier.Quote(d, k.Field)
+		case AliasKey:
+			target, err = identifier.QuoteAlias(d, k.Field)
+		default:
+			target, err = identifier.UnsafeRaw(k.Field).Build(d)
+		}
+		if err != nil {
+			return "", err
+		}
+	case OrdinalKey:
+		if k.Position < 1 {
+			return "", fmt.Errorf("order by position must be at least 1, got %d", k.Position)
+		}
+		target = fmt.Sprintf("%d", k.Position)
+	default:
+		return "", fmt.Errorf("invalid order by key kind: %s", k.Kind)
+	}
+
+	switch k.Direction {
+	case "", Asc, Desc:
+	default:
+		return "", fmt.Errorf("invalid order by direction: %s", k.Direction)
+	}
+	switch k.Nulls {
+	case "", NullsFirst, NullsLast:
+	default:
+		return "", fmt.Errorf("invalid nulls ordering: %s", k.Nulls)
+	}
+
+	parts := []string{target}
+	if k.Direction != "" {
+		parts = append(parts, string(k.Direction))
+	}
+	if k.Nulls == "" {
+		return strings.Join(parts, " "), nil
+	}
+	if d.Features().NullsOrder {
+		parts = append(parts, string(k.Nulls))
+		return strings.Join(parts, " "), nil
+	}
+
+	if k.Kind == OrdinalKey {
+		return "", fmt.Errorf("%s by position is not supported by %s", k.Nulls, d.Name())
+	}
+	nullTarget := target
+	if k.Kind == AliasKey {
+		if expression == "" {
+			return "", fmt.Errorf("%s by alias %s is not supported by %s", k.Nulls, k.Field, d.Name())
+		}
+		nullTarget = expression
+	}
+	nullRank, valueRank := 1, 0
+	if k.Nulls == NullsFirst {
+		nullRank, valueRank = 0, 1
+	}
+	return fmt.Sprintf("CASE WHEN %s IS NULL THEN %d ELSE %d END, %s",
+		nullTarget, nullRank, valueRank, strings.Join(parts, " ")), nil
+}
+
+// SimpleOrderBy implements the ORDER BY clause
+type SimpleOrderBy struct {
+	Keys    []OrderKey
+	aliases map[string]string // alias -> aggregate expression, see ResolveAliases
+}
+
+func NewSimpleOrderBy(keys ...OrderKey) *SimpleOrderBy {
+	return &SimpleOrderBy{Keys: keys}
+}
+
+// Add appends keys to the ORDER BY list
+func (o *SimpleOrderBy) Add(keys ...OrderKey) *SimpleOrderBy {
+	o.Keys = append(o.Keys, keys...)
+	return o
+}
+
+// ResolveAliases returns a copy of the clause that knows the aggregate
+// expression of each alias. Dialects without NULLS FIRST/LAST place NULLs with
+// a CASE on the expression, since the alias cannot be used inside it. The copy
+// shares the keys and o is left unchanged.
+func (o *SimpleOrderBy) ResolveAliases(aliases map[string]string) *SimpleOrderBy {
+	resolved := &SimpleOrderBy{
+		Keys:    o.Keys,
+		aliases: make(map[string]string, len(o.aliases)+len(aliases)),
+	}
+	for alias, expression := range o.aliases {
+		resolved.aliases[alias] = expression
+	}
+	for alias, expression := range aliases {
+		resolved.aliases[alias] = expression
+	}
+	return resolved
+}
+
+// Validate checks alias and ordinal keys against the select list. A columnCount
+// of 0 means the select list size is unknown (e.g. SELECT *) and skips the
+// ordinal range check.
+func (o *SimpleOrderBy) Validate(aliases []string, columnCount int) error {
+	known := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		known[alias] = true
+	}
+
+	for _, key := range o.Keys {
+		switch key.Kind {
+		case AliasKey:
+			if !known[key.Field] {
+				return fmt.Errorf("order by alias %s does not exist in select clause", key.Field)
+			}
+		case OrdinalKey:
+			if columnCount > 0 && key.Position > columnCount {
+				return fmt.Errorf("order by position %d is out of range, select clause has %d columns",
+					key.Position, columnCount)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *SimpleOrderBy) Build(d dialect.Dialect) (string, error) {
+	if len(o.Keys) == 0 {
+		return "", fmt.Errorf("no keys specified for order by")
+	}
+
+	keys := make([]string, len(o.Keys))
+	for i, key := range o.Keys {
+		var expression string
+		if key.Kind == AliasKey {
+			expression = o.aliases[key.Field]
+		}
+		sql, err := key.build(d, expression)
+		if err != nil {
+			return "", err
+		}
+		keys[i] = sql
+	}
+	return fmt.Sprintf("ORDER BY %s", strings.Join(keys, ", ")), nil
+}
@@ -0,0 +1,178 @@
+package simpleorderby
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimpleOrderBy_Build(t *testing.T) {
+	tests := []struct {
+		name          string
+		keys          []OrderKey
+		expected      string
+		expectedError string
+		description   string
+	}{
+		{
+			name:        "Single field",
+			keys:        []OrderKey{Field("department")},
+			expected:    `ORDER BY "department"`,
+			description: "Should order by a field without explicit direction",
+		},
+		{
+			name:        "Multiple keys with direction",
+			keys:        []OrderKey{Field("department").Asc(), Field("created_at").Desc()},
+			expected:    `ORDER BY "department" ASC, "created_at" DESC`,
+			description: "Should keep key order and render directions",
+		},
+		{
+			name:        "Nulls ordering",
+			keys:        []OrderKey{Field("closed_at").Desc().NullsLast(), Field("opened_at").NullsFirst()},
+			expected:    `ORDER BY "closed_at" DESC NULLS LAST, "opened_at" NULLS FIRST`,
+			description: "Should render NULLS FIRST/LAST after the direction",
+		},
+		{
+			name:        "Aggregate alias",
+			keys:        []OrderKey{Alias("total_amount").Desc()},
+			expected:    `ORDER BY "total_amount" DESC`,
+			description: "Should order by an aggregate alias",
+		},
+		{
+			name:        "Positional ordinal",
+			keys:        []OrderKey{Ordinal(2).Desc(), Ordinal(1)},
+			expected:    `ORDER BY 2 DESC, 1`,
+			description: "Should order by select list positions",
+		},
+		{
+			name:        "Raw expression",
+			keys:        []OrderKey{UnsafeRaw("date_trunc('month', posted_at)").Desc()},
+			expected:    "ORDER BY date_trunc('month', posted_at) DESC",
+			description: "Should emit raw expressions verbatim",
+		},
+		{
+			name:          "Invalid field",
+			keys:          []OrderKey{Field("id; DROP TABLE users")},
+			expectedError: "invalid identifier",
+			description:   "Should reject fields that are not valid identifiers",
+		},
+		{
+			name:          "No keys",
+			expectedError: "no keys specified for order by",
+			description:   "Should return error when no keys are specified",
+		},
+		{
+			name:          "Invalid ordinal",
+			keys:          []OrderKey{Ordinal(0)},
+			expectedError: "order by position must be at least 1",
+			description:   "Should reject positions below 1",
+		},
+		{
+			name:          "Empty field",
+			keys:          []OrderKey{Field("")},
+			expectedError: "order by key requires a field",
+			description:   "Should reject keys without a field",
+		},
+		{
+			name:          "Invalid direction",
+			keys:          []OrderKey{{Kind: FieldKey, Field: "id", Direction: "SIDEWAYS"}},
+			expectedError: "invalid order by direction",
+			description:   "Should reject unknown directions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log(tt.description)
+			result, err := NewSimpleOrderBy(tt.keys...).Build(dialect.Postgres)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestSimpleOrderBy_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		keys          []OrderKey
+		aliases       []string
+		columnCount   int
+		expectedError string
+	}{
+		{
+			name:        "Known alias",
+			keys:        []OrderKey{Alias("total_amount")},
+			aliases:     []string{"total_amount"},
+			columnCount: 2,
+		},
+		{
+			name:          "Unknown alias",
+			keys:          []OrderKey{Alias("total")},
+			aliases:       []string{"total_amount"},
+			columnCount:   2,
+			expectedError: "order by alias total does not exist in select clause",
+		},
+		{
+			name:        "Ordinal in range",
+			keys:        []OrderKey{Ordinal(2)},
+			columnCount: 2,
+		},
+		{
+			name:          "Ordinal out of range",
+			keys:          []OrderKey{Ordinal(3)},
+			columnCount:   2,
+			expectedError: "order by position 3 is out of range",
+		},
+		{
+			name:        "Ordinal with unknown column count",
+			keys:        []OrderKey{Ordinal(5)},
+			columnCount: 0,
+		},
+		{
+			name: "Fields are not validated",
+			keys: []OrderKey{Field("created_at")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSimpleOrderBy(tt.keys...).Validate(tt.aliases, tt.columnCount)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSimpleOrderBy_ResolveAliases(t *testing.T) {
+	orderBy := NewSimpleOrderBy(Alias("total_amount").Desc().NullsLast(), Field("department"))
+
+	_, err := orderBy.Build(dialect.SQLServer)
+	assert.EqualError(t, err, "NULLS LAST by alias total_amount is not supported by sqlserver")
+
+	resolved := orderBy.ResolveAliases(map[string]string{"total_amount": "SUM([amount])"})
+	sql, err := resolved.Build(dialect.SQLServer)
+	require.NoError(t, err)
+	assert.Equal(t, "ORDER BY CASE WHEN SUM([amount]) IS NULL THEN 1 ELSE 0 END, [total_amount] DESC, [department]", sql)
+
+	// Dialects with NULLS FIRST/LAST keep ordering by the alias
+	sql, err = resolved.Build(dialect.Postgres)
+	require.NoError(t, err)
+	assert.Equal(t, `ORDER BY "total_amount" DESC NULLS LAST, "department"`, sql)
+
+	_, err = orderBy.Build(dialect.SQLServer)
+	assert.Error(t, err, "the original clause is not changed")
+}
@@ -0,0 +1,289 @@
+package keyset
+
+import (
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Keyset implements seek pagination: it holds the ORDER BY keys and the values
+// of the last row of the previous page, and builds the predicate selecting the
+// rows that come after it.
+type Keyset struct {
+	Keys   []simpleorderby.OrderKey
+	Values []interface{}
+}
+
+func NewKeyset(keys []simpleorderby.OrderKey, values []interface{}) *Keyset {
+	return &Keyset{
+		Keys:   keys,
+		Values: values,
+	}
+}
+
+func (k *Keyset) validate() error {
+	if len(k.Keys) == 0 {
+		return errors.New("keyset requires at least one order by key")
+	}
+	if len(k.Keys) != len(k.Values) {
+		return fmt.Errorf("keyset has %d keys but %d values", len(k.Keys), len(k.Values))
+	}
+	for _, key := range k.Keys {
+		if key.Kind != simpleorderby.FieldKey || key.Field == "" {
+			return errors.New("keyset keys must reference fields, aliases and ordinals cannot be used in WHERE")
+		}
+		if key.Nulls != "" {
+			return fmt.Errorf("keyset key %s cannot use NULLS ordering", key.Field)
+		}
+	}
+	return nil
+}
+
+// MatchesOrder checks that the keys are the leading keys of the ORDER BY with
+// the same directions. A predicate on other keys would skip or repeat rows.
+func (k *Keyset) MatchesOrder(orderBy []simpleorderby.OrderKey) error {
+	if len(k.Keys) > len(orderBy) {
+		return fmt.Errorf("keyset has %d keys but the order by has %d", len(k.Keys), len(orderBy))
+	}
+	for i, key := range k.Keys {
+		order := orderBy[i]
+		if order.Kind != key.Kind || order.Field != key.Field || direction(order) != direction(key) {
+			return fmt.Errorf("keyset key %d (%s %s) does not match order by key %s %s",
+				i+1, key.Field, direction(key), order.Field, direction(order))
+		}
+	}
+	return nil
+}
+
+// Build implements the QueryCondition interface. Keys sharing one direction
+// produce a row comparison, e.g. (a, b) > ($1, $2); mixed directions, or
+// dialects without row comparison, are expanded into (a > $1 OR (a = $2 AND b < $3)).
+func (k *Keyset) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	if err := k.validate(); err != nil {
+		return "", nil, err
+	}
+
+	fields := make([]string, len(k.Keys))
+	for i, key := range k.Keys {
+		field, err := identifier.Quote(d, key.Field)
+		if err != nil {
+			return "", nil, err
+		}
+		fields[i] = field
+	}
+
+	if k.uniformDirection() && (len(k.Keys) == 1 || d.Features().RowComparison) {
+		operator := Comparison(k.Keys[0])
+		if len(k.Keys) == 1 {
+			return fmt.Sprintf("%s %s %s", fields[0], operator, d.Placeholder(paramOffset)),
+				[]interface{}{k.Values[0]}, nil
+		}
+
+		placeholders := make([]string, len(k.Keys))
+		for i := range k.Keys {
+			placeholders[i] = d.Placeholder(paramOffset + i)
+		}
+		args := make([]interface{}, len(k.Values))
+		copy(args, k.Values)
+		return fmt.Sprintf("(%s) %s (%s)",
+			strings.Join(fields, ", "), operator, strings.Join(placeholders, ", ")), args, nil
+	}
+
+	var clauses []string
+	var args []interface{}
+	for i, key := range k.Keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = %s", fields[j], d.Placeholder(paramOffset+len(args))))
+			args = append(args, k.Values[j])
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", fields[i], Comparison(key), d.Placeholder(paramOffset+len(args))))
+		args = append(args, k.Values[i])
+
+		if len(parts) == 1 {
+			clauses = append(clauses, parts[0])
+		} else {
+			clauses = append(clauses, fmt.Sprintf("(%s)", strings.Join(parts, " AND ")))
+		}
+	}
+	return fmt.Sprintf("(%s)", strings.Join(clauses, " OR ")), args, nil
+}
+
+func (k *Keyset) uniformDirection() bool {
+	for _, key := range k.Keys[1:] {
+		if Comparison(key) != Comparison(k.Keys[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// direction returns the sort direction of the key, ascending when unset
+func direction(key simpleorderby.OrderKey) simpleorderby.Direction {
+	if key.Direction == simpleorderby.Desc {
+		return simpleorderby.Desc
+	}
+	return simpleorderby.Asc
+}
+
+// Comparison returns the operator selecting rows after the key value
+func Comparison(key simpleorderby.OrderKey) fields.ComparisonOperator {
+	if key.Direction == simpleorderby.Desc {
+		return fields.LessThan
+	}
+	return fields.GreaterThan
+}
+
+// cursorPayload is the serialized form of a keyset
+type cursorPayload struct {
+	Keys   []cursorKey   `json:"k"`
+	Values []cursorValue `json:"v"`
+}
+
+type cursorKey struct {
+	Field     string                  `json:"f"`
+	Direction simpleorderby.Direction `json:"d,omitempty"`
+}
+
+// cursorValue keeps the Go type of a value so it decodes back unchanged
+type cursorValue struct {
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v"`
+}
+
+// Cursor encodes the keyset as an opaque token for the next page
+func (k *Keyset) Cursor() (string, error) {
+	if err := k.validate(); err != nil {
+		return "", err
+	}
+
+	payload := cursorPayload{
+		Keys:   make([]cursorKey, len(k.Keys)),
+		Values: make([]cursorValue, len(k.Values)),
+	}
+	for i, key := range k.Keys {
+		payload.Keys[i] = cursorKey{Field: key.Field, Direction: key.Direction}
+	}
+	for i, value := range k.Values {
+		encoded, err := encodeValue(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode cursor value for %s: %w", k.Keys[i].Field, err)
+		}
+		payload.Values[i] = encoded
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a token produced by Cursor back into a keyset
+func DecodeCursor(token string) (*Keyset, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	ks := &Keyset{
+		Keys:   make([]simpleorderby.OrderKey, len(payload.Keys)),
+		Values: make([]interface{}, len(payload.Values)),
+	}
+	for i, key := range payload.Keys {
+		orderKey := simpleorderby.Field(key.Field)
+		orderKey.Direction = key.Direction
+		ks.Keys[i] = orderKey
+	}
+	for i, value := range payload.Values {
+		decoded, err := decodeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		ks.Values[i] = decoded
+	}
+
+	if err := ks.validate(); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return ks, nil
+}
+
+func encodeValue(value interface{}) (cursorValue, error) {
+	var typeName string
+	switch v := value.(type) {
+	case string:
+		typeName = "string"
+	case int:
+		typeName = "int"
+	case int32:
+		typeName = "int32"
+	case int64:
+		typeName = "int64"
+	case float64:
+		typeName = "float64"
+	case bool:
+		typeName = "bool"
+	case time.Time:
+		typeName = "time"
+		value = v.Format(time.RFC3339Nano)
+	default:
+		return cursorValue{}, fmt.Errorf("unsupported cursor value type %T", value)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return cursorValue{}, err
+	}
+	return cursorValue{Type: typeName, Value: raw}, nil
+}
+
+func decodeValue(value cursorValue) (interface{}, error) {
+	var err error
+	switch value.Type {
+	case "string":
+		var v string
+		err = json.Unmarshal(value.Value, &v)
+		return v, err
+	case "int":
+		var v int
+		err = json.Unmarshal(value.Value, &v)
+		return v, err
+	case "int32":
+		var v int32
+		err = json.Unmarshal(value.Value, &v)
+		return v, err
+	case "int64":
+		var v int64
+		err = json.Unmarshal(value.Value, &v)
+		return v, err
+	case "float64":
+		var v float64
+		err = json.Unmarshal(value.Value, &v)
+		return v, err
+	case "bool":
+		var v bool
+		err = json.Unmarshal(value.Value, &v)
+		return v, err
+	case "time":
+		var s string
+		if err = json.Unmarshal(value.Value, &s); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	default:
+		return nil, fmt.Errorf("unsupported cursor value type %s", value.Type)
+	}
+}
@@ -0,0 +1,213 @@
+package keyset
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyset_Build(t *testing.T) {
+	tests := []struct {
+		name          string
+		keys          []simpleorderby.OrderKey
+		values        []interface{}
+		paramOffset   int
+		expectedSQL   string
+		expectedArgs  []interface{}
+		expectedError string
+	}{
+		{
+			name:         "Single ascending key",
+			keys:         []simpleorderby.OrderKey{simpleorderby.Field("id")},
+			values:       []interface{}{42},
+			paramOffset:  1,
+			expectedSQL:  `"id" > $1`,
+			expectedArgs: []interface{}{42},
+		},
+		{
+			name:         "Single descending key",
+			keys:         []simpleorderby.OrderKey{simpleorderby.Field("id").Desc()},
+			values:       []interface{}{42},
+			paramOffset:  3,
+			expectedSQL:  `"id" < $3`,
+			expectedArgs: []interface{}{42},
+		},
+		{
+			name: "Row comparison ascending",
+			keys: []simpleorderby.OrderKey{
+				simpleorderby.Field("period_year").Asc(),
+				simpleorderby.Field("id").Asc(),
+			},
+			values:       []interface{}{2024, 100},
+			paramOffset:  2,
+			expectedSQL:  `("period_year", "id") > ($2, $3)`,
+			expectedArgs: []interface{}{2024, 100},
+		},
+		{
+			name: "Row comparison descending",
+			keys: []simpleorderby.OrderKey{
+				simpleorderby.Field("amount").Desc(),
+				simpleorderby.Field("id").Desc(),
+			},
+			values:       []interface{}{99.5, 7},
+			paramOffset:  1,
+			expectedSQL:  `("amount", "id") < ($1, $2)`,
+			expectedArgs: []interface{}{99.5, 7},
+		},
+		{
+			name: "Mixed directions",
+			keys: []simpleorderby.OrderKey{
+				simpleorderby.Field("department").Asc(),
+				simpleorderby.Field("amount").Desc(),
+			},
+			values:       []interface{}{"FIN", 500},
+			paramOffset:  1,
+			expectedSQL:  `("department" > $1 OR ("department" = $2 AND "amount" < $3))`,
+			expectedArgs: []interface{}{"FIN", "FIN", 500},
+		},
+		{
+			name:          "Mismatched values",
+			keys:          []simpleorderby.OrderKey{simpleorderby.Field("id")},
+			values:        []interface{}{1, 2},
+			paramOffset:   1,
+			expectedError: "keyset has 1 keys but 2 values",
+		},
+		{
+			name:          "Alias key",
+			keys:          []simpleorderby.OrderKey{simpleorderby.Alias("total_amount")},
+			values:        []interface{}{1},
+			paramOffset:   1,
+			expectedError: "keyset keys must reference fields",
+		},
+		{
+			name:          "Nulls ordering",
+			keys:          []simpleorderby.OrderKey{simpleorderby.Field("closed_at").NullsLast()},
+			values:        []interface{}{1},
+			paramOffset:   1,
+			expectedError: "cannot use NULLS ordering",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := NewKeyset(tt.keys, tt.values).Build(dialect.Postgres, tt.paramOffset)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestKeyset_Cursor(t *testing.T) {
+	postedAt := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	ks := NewKeyset(
+		[]simpleorderby.OrderKey{
+			simpleorderby.Field("posted_at").Desc(),
+			simpleorderby.Field("account_code"),
+			simpleorderby.Field("id").Desc(),
+		},
+		[]interface{}{postedAt, "1001", int64(987654321)},
+	)
+
+	token, err := ks.Cursor()
+	require.NoError(t, err)
+	assert.NotContains(t, token, "posted_at", "cursor should be opaque")
+
+	decoded, err := DecodeCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, ks.Keys, decoded.Keys)
+	assert.Equal(t, "1001", decoded.Values[1])
+	assert.Equal(t, int64(987654321), decoded.Values[2])
+	assert.True(t, postedAt.Equal(decoded.Values[0].(time.Time)))
+
+	expectedSQL, expectedArgs, err := ks.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	sql, args, err := decoded.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expectedSQL, sql)
+	assert.Len(t, args, len(expectedArgs))
+}
+
+func TestKeyset_CursorErrors(t *testing.T) {
+	t.Run("Unsupported value type", func(t *testing.T) {
+		_, err := NewKeyset(
+			[]simpleorderby.OrderKey{simpleorderby.Field("id")},
+			[]interface{}{struct{}{}},
+		).Cursor()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported cursor value type")
+	})
+
+	t.Run("Malformed token", func(t *testing.T) {
+		_, err := DecodeCursor("not a cursor!")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid cursor")
+	})
+
+	t.Run("Empty payload", func(t *testing.T) {
+		_, err := DecodeCursor("e30")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "keyset requires at least one order by key")
+	})
+}
+
+func TestKeyset_MatchesOrder(t *testing.T) {
+	ks := NewKeyset(
+		[]simpleorderby.OrderKey{simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("id")},
+		[]interface{}{"2024-03-01", 10},
+	)
+
+	tests := []struct {
+		name          string
+		orderBy       []simpleorderby.OrderKey
+		expectedError string
+	}{
+		{
+			name:    "Same keys",
+			orderBy: []simpleorderby.OrderKey{simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("id").Asc()},
+		},
+		{
+			name: "Prefix of the order",
+			orderBy: []simpleorderby.OrderKey{
+				simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("id"), simpleorderby.Field("amount"),
+			},
+		},
+		{
+			name:          "Different direction",
+			orderBy:       []simpleorderby.OrderKey{simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("id").Desc()},
+			expectedError: "keyset key 2 (id ASC) does not match order by key id DESC",
+		},
+		{
+			name:          "Different field",
+			orderBy:       []simpleorderby.OrderKey{simpleorderby.Field("id"), simpleorderby.Field("posted_at").Desc()},
+			expectedError: "keyset key 1 (posted_at DESC) does not match order by key id ASC",
+		},
+		{
+			name:          "Shorter order",
+			orderBy:       []simpleorderby.OrderKey{simpleorderby.Field("posted_at").Desc()},
+			expectedError: "keyset has 2 keys but the order by has 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ks.MatchesOrder(tt.orderBy)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
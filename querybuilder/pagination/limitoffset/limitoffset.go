@@ -0,0 +1,103 @@
+package limitoffset
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LimitOffset implements parameterized LIMIT/OFFSET
+type LimitOffset struct {
+	Limit  *int
+	Offset *int
+}
+
+func NewLimitOffset() *LimitOffset {
+	return &LimitOffset{}
+}
+
+// SetLimit sets the maximum number of rows returned
+func (l *LimitOffset) SetLimit(limit int) *LimitOffset {
+	l.Limit = &limit
+	return l
+}
+
+// SetOffset sets the number of rows skipped
+func (l *LimitOffset) SetOffset(offset int) *LimitOffset {
+	l.Offset = &offset
+	return l
+}
+
+func (l *LimitOffset) validate() error {
+	if l.Limit != nil && *l.Limit < 0 {
+		return fmt.Errorf("limit must not be negative, got %d", *l.Limit)
+	}
+	if l.Offset != nil && *l.Offset < 0 {
+		return fmt.Errorf("offset must not be negative, got %d", *l.Offset)
+	}
+	return nil
+}
+
+// Build renders LIMIT/OFFSET, or OFFSET ... FETCH for dialects that use it.
+// OFFSET ... FETCH requires an ORDER BY, which the builder provides.
+func (l *LimitOffset) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	if err := l.validate(); err != nil {
+		return "", nil, err
+	}
+	if d.Features().Limit == dialect.OffsetFetch {
+		return l.buildOffsetFetch(d, paramOffset)
+	}
+
+	var parts []string
+	var args []interface{}
+
+	if l.Limit != nil {
+		parts = append(parts, fmt.Sprintf("LIMIT %s", d.Placeholder(paramOffset+len(args))))
+		args = append(args, *l.Limit)
+	} else if l.Offset != nil && d.Features().UnboundedLimit != "" {
+		// Some dialects only accept OFFSET after a LIMIT
+		parts = append(parts, fmt.Sprintf("LIMIT %s", d.Features().UnboundedLimit))
+	}
+	if l.Offset != nil {
+		parts = append(parts, fmt.Sprintf("OFFSET %s", d.Placeholder(paramOffset+len(args))))
+		args = append(args, *l.Offset)
+	}
+
+	if len(parts) == 0 {
+		return "", nil, nil
+	}
+	return strings.Join(parts, " "), args, nil
+}
+
+func (l *LimitOffset) buildOffsetFetch(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	if l.Limit == nil && l.Offset == nil {
+		return "", nil, nil
+	}
+
+	var args []interface{}
+	offset := "0"
+	if l.Offset != nil {
+		offset = d.Placeholder(paramOffset)
+		args = append(args, *l.Offset)
+	}
+	sql := fmt.Sprintf("OFFSET %s ROWS", offset)
+
+	if l.Limit != nil {
+		sql += fmt.Sprintf(" FETCH NEXT %s ROWS ONLY", d.Placeholder(paramOffset+len(args)))
+		args = append(args, *l.Limit)
+	}
+	return sql, args, nil
+}
+
+// Top renders TOP (n) for the select list, used by OFFSET ... FETCH dialects
+// when the query has a limit but no ORDER BY or offset
+func (l *LimitOffset) Top(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	if err := l.validate(); err != nil {
+		return "", nil, err
+	}
+	if l.Limit == nil || l.Offset != nil {
+		return "", nil, errors.New("TOP requires a limit without an offset")
+	}
+	return fmt.Sprintf("TOP (%s)", d.Placeholder(paramOffset)), []interface{}{*l.Limit}, nil
+}
@@ -0,0 +1,150 @@
+package limitoffset
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitOffset_Build(t *testing.T) {
+	tests := []struct {
+		name          string
+		build         func() *LimitOffset
+		paramOffset   int
+		expectedSQL   string
+		expectedArgs  []interface{}
+		expectedError string
+	}{
+		{
+			name:         "Nothing set",
+			build:        NewLimitOffset,
+			paramOffset:  1,
+			expectedSQL:  "",
+			expectedArgs: nil,
+		},
+		{
+			name: "Limit only",
+			build: func() *LimitOffset {
+				return NewLimitOffset().SetLimit(50)
+			},
+			paramOffset:  1,
+			expectedSQL:  "LIMIT $1",
+			expectedArgs: []interface{}{50},
+		},
+		{
+			name: "Offset only",
+			build: func() *LimitOffset {
+				return NewLimitOffset().SetOffset(100)
+			},
+			paramOffset:  3,
+			expectedSQL:  "OFFSET $3",
+			expectedArgs: []interface{}{100},
+		},
+		{
+			name: "Limit and offset",
+			build: func() *LimitOffset {
+				return NewLimitOffset().SetOffset(100).SetLimit(50)
+			},
+			paramOffset:  4,
+			expectedSQL:  "LIMIT $4 OFFSET $5",
+			expectedArgs: []interface{}{50, 100},
+		},
+		{
+			name: "Negative limit",
+			build: func() *LimitOffset {
+				return NewLimitOffset().SetLimit(-1)
+			},
+			paramOffset:   1,
+			expectedError: "limit must not be negative",
+		},
+		{
+			name: "Negative offset",
+			build: func() *LimitOffset {
+				return NewLimitOffset().SetOffset(-10)
+			},
+			paramOffset:   1,
+			expectedError: "offset must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.build().Build(dialect.Postgres, tt.paramOffset)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestLimitOffset_BuildDialects(t *testing.T) {
+	tests := []struct {
+		dialect        dialect.Dialect
+		limitOffset    *LimitOffset
+		expectedSQL    string
+		expectedArgs   []interface{}
+		expectedTopSQL string
+	}{
+		{
+			dialect:      dialect.MySQL,
+			limitOffset:  NewLimitOffset().SetLimit(50).SetOffset(100),
+			expectedSQL:  "LIMIT ? OFFSET ?",
+			expectedArgs: []interface{}{50, 100},
+		},
+		{
+			dialect:      dialect.MySQL,
+			limitOffset:  NewLimitOffset().SetOffset(100),
+			expectedSQL:  "LIMIT 18446744073709551615 OFFSET ?",
+			expectedArgs: []interface{}{100},
+		},
+		{
+			dialect:      dialect.SQLite,
+			limitOffset:  NewLimitOffset().SetOffset(100),
+			expectedSQL:  "LIMIT -1 OFFSET ?",
+			expectedArgs: []interface{}{100},
+		},
+		{
+			dialect:      dialect.SQLServer,
+			limitOffset:  NewLimitOffset().SetLimit(50).SetOffset(100),
+			expectedSQL:  "OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY",
+			expectedArgs: []interface{}{100, 50},
+		},
+		{
+			dialect:      dialect.SQLServer,
+			limitOffset:  NewLimitOffset().SetOffset(100),
+			expectedSQL:  "OFFSET @p2 ROWS",
+			expectedArgs: []interface{}{100},
+		},
+		{
+			dialect:        dialect.SQLServer,
+			limitOffset:    NewLimitOffset().SetLimit(50),
+			expectedSQL:    "OFFSET 0 ROWS FETCH NEXT @p2 ROWS ONLY",
+			expectedArgs:   []interface{}{50},
+			expectedTopSQL: "TOP (@p2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name()+" "+tt.expectedSQL, func(t *testing.T) {
+			sql, args, err := tt.limitOffset.Build(tt.dialect, 2)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSQL, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+
+			if tt.expectedTopSQL != "" {
+				sql, args, err := tt.limitOffset.Top(tt.dialect, 2)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedTopSQL, sql)
+				assert.Equal(t, tt.expectedArgs, args)
+			}
+		})
+	}
+}
@@ -1,26 +1,38 @@
 package querybuilder
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/pagination/keyset"
 	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
 )
 
 type Query struct {
-	SelectClause SelectClause
-	FromClause   FromClause
-	WhereClause  WhereClause
-	Args         []interface{}
+	SelectClause  SelectClause
+	FromClause    FromClause
+	WhereClause   WhereClause
+	GroupByClause GroupByClause
+	HavingClause  HavingClause
+	OrderByClause OrderByClause
+	LimitClause   LimitClause
+	Args          []interface{}
 }
 
 // QueryBuilder interface with enhanced aggregate support
 type QueryBuilder interface {
 	// Select operations
 	Select(fields ...string) QueryBuilder
+	SelectUnsafeRaw(expressions ...string) QueryBuilder // Raw SQL, never from user input
 	SelectAggregate() QueryBuilder
 	AddRegularField(field string) QueryBuilder
+	AddUnsafeRawField(expression string) QueryBuilder // Raw SQL, never from user input
 	AddAggregate(fn aggregate.AggregateFunction, field, alias string) QueryBuilder
 
-	// FROM operation
+	// FROM operations
 	From(table string) QueryBuilder
+	FromAs(table, alias string) QueryBuilder
+	Join(joinType JoinType, table, alias string, on QueryCondition) QueryBuilder
+	JoinUsing(joinType JoinType, table, alias string, columns ...string) QueryBuilder
 
 	// Where operations
 	Where(condition QueryCondition) QueryBuilder
@@ -29,25 +41,74 @@ type QueryBuilder interface {
 	Or() QueryBuilder  // Starts a new OR group
 	And() QueryBuilder // Starts a new AND group
 
+	// GROUP BY operations
+	GroupBy(fields ...string) QueryBuilder
+	AutoGroupBy() QueryBuilder // Derive GROUP BY from the aggregate select's regular fields
+
+	// HAVING operations, conditions may reference aggregate expressions or aliases
+	Having(condition QueryCondition) QueryBuilder
+	HavingGroup(operator LogicalOperator,
+		buildGroup func(*WhereGroup)) QueryBuilder
+
+	// ORDER BY operations
+	OrderBy(keys ...simpleorderby.OrderKey) QueryBuilder
+
+	// Pagination operations
+	Limit(limit int) QueryBuilder
+	Offset(offset int) QueryBuilder
+	Seek(keyset *keyset.Keyset) QueryBuilder // Keyset pagination after the given row
+
 	Build() (string, []interface{}, error)
 }
 
-// QueryCondition represents a condition in the query
+// QueryCondition represents a condition in the query. Conditions render
+// placeholders and identifiers for the given dialect, numbering parameters
+// from paramOffset.
 type QueryCondition interface {
-	Build(paramOffset int) (string, []interface{}, error)
+	Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error)
 }
 
-// SelectClause interface defines how select clauses should be built
+// SelectClause interface defines how select clauses should be built.
+// Aggregate filters may carry parameters, they are numbered from paramOffset
+// before any other clause.
 type SelectClause interface {
-	Build() (string, error)
+	Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error)
+}
+
+// SelectColumns is implemented by select clauses that can describe their
+// output columns, used to validate ORDER BY aliases and positions
+type SelectColumns interface {
+	Aliases() []string
+	ColumnCount() int // 0 when unknown, e.g. SELECT *
 }
 
-// FromClause defines the interface for building FROM part of query
+// FromClause defines the interface for building FROM part of query.
+// Join conditions may carry parameters, so it numbers them from paramOffset.
 type FromClause interface {
-	Build() string
+	Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error)
 }
 
 // WhereClause interface
 type WhereClause interface {
-	Build(paramOffset int) (string, []interface{}, error)
+	Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error)
+}
+
+// GroupByClause defines the interface for building GROUP BY part of query
+type GroupByClause interface {
+	Build(d dialect.Dialect) (string, error)
+}
+
+// HavingClause defines the interface for building HAVING part of query
+type HavingClause interface {
+	Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error)
+}
+
+// OrderByClause defines the interface for building ORDER BY part of query
+type OrderByClause interface {
+	Build(d dialect.Dialect) (string, error)
+}
+
+// LimitClause defines the interface for building LIMIT/OFFSET part of query
+type LimitClause interface {
+	Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error)
 }
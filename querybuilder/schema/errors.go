@@ -0,0 +1,23 @@
+package schema
+
+import "dynamic-sqlbuilder/querybuilder/validation"
+
+// ErrorKind classifies a schema validation failure
+type ErrorKind = validation.ErrorKind
+
+const (
+	UnknownTable         ErrorKind = "UNKNOWN_TABLE"
+	UnknownColumn        ErrorKind = "UNKNOWN_COLUMN"
+	AmbiguousColumn      ErrorKind = "AMBIGUOUS_COLUMN"
+	OperatorNotAllowed   ErrorKind = "OPERATOR_NOT_ALLOWED"
+	AggregateNotAllowed  ErrorKind = "AGGREGATE_NOT_ALLOWED"
+	RawExpressionBlocked ErrorKind = "RAW_EXPRESSION_NOT_ALLOWED"
+	UnsupportedCondition ErrorKind = "UNSUPPORTED_CONDITION"
+	ColumnTypeMismatch   ErrorKind = "COLUMN_TYPE_MISMATCH"
+)
+
+// FieldError identifies the clause and field that failed validation
+type FieldError = validation.FieldError
+
+// FieldErrors collects every failure found in a query
+type FieldErrors = validation.FieldErrors
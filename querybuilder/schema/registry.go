@@ -0,0 +1,184 @@
+package schema
+
+import (
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"strings"
+)
+
+// ColumnType represents the data type of a registered column
+type ColumnType string
+
+const (
+	Text      ColumnType = "TEXT"
+	Integer   ColumnType = "INTEGER"
+	Numeric   ColumnType = "NUMERIC"
+	Boolean   ColumnType = "BOOLEAN"
+	Date      ColumnType = "DATE"
+	Timestamp ColumnType = "TIMESTAMP"
+)
+
+var (
+	nullOperators     = []fields.ComparisonOperator{fields.IsNull, fields.IsNotNull}
+	equalityOperators = []fields.ComparisonOperator{fields.Equals, fields.NotEquals, fields.In, fields.NotIn,
+		fields.IsDistinctFrom, fields.IsNotDistinctFrom}
+	comparisonOperators = []fields.ComparisonOperator{fields.GreaterThan, fields.LessThan, fields.GreaterOrEqual, fields.LessOrEqual,
+		fields.Between, fields.NotBetween}
+	patternOperators = []fields.ComparisonOperator{fields.Like, fields.NotLike, fields.ILike, fields.NotILike,
+		fields.SimilarTo, fields.Regex, fields.IRegex}
+	booleanOperators = []fields.ComparisonOperator{fields.IsTrue, fields.IsFalse}
+)
+
+// defaultOperators returns the operators permitted for a type when a column
+// does not list its own
+func defaultOperators(columnType ColumnType) []fields.ComparisonOperator {
+	operators := append([]fields.ComparisonOperator{}, nullOperators...)
+	operators = append(operators, equalityOperators...)
+
+	switch columnType {
+	case Text:
+		operators = append(operators, patternOperators...)
+	case Integer, Numeric, Date, Timestamp:
+		operators = append(operators, comparisonOperators...)
+	case Boolean:
+		operators = append(operators, booleanOperators...)
+	}
+	return operators
+}
+
+// defaultAggregates returns the aggregates permitted for a type when a column
+// does not list its own
+func defaultAggregates(columnType ColumnType) []aggregate.AggregateFunction {
+	switch columnType {
+	case Integer, Numeric:
+		return []aggregate.AggregateFunction{aggregate.Sum, aggregate.Avg, aggregate.Count, aggregate.Max, aggregate.Min}
+	case Text, Date, Timestamp:
+		return []aggregate.AggregateFunction{aggregate.Count, aggregate.Max, aggregate.Min}
+	default:
+		return []aggregate.AggregateFunction{aggregate.Count}
+	}
+}
+
+// Column describes an allowed column
+type Column struct {
+	Name       string
+	Type       ColumnType
+	Operators  []fields.ComparisonOperator   // Permitted operators, defaults by Type when empty
+	Aggregates []aggregate.AggregateFunction // Permitted aggregates, defaults by Type when empty
+}
+
+// NewColumn creates a column with the default operators and aggregates for its type
+func NewColumn(name string, columnType ColumnType) Column {
+	return Column{
+		Name: name,
+		Type: columnType,
+	}
+}
+
+// WithOperators returns a copy of the column restricted to the given operators
+func (c Column) WithOperators(operators ...fields.ComparisonOperator) Column {
+	c.Operators = operators
+	return c
+}
+
+// WithAggregates returns a copy of the column restricted to the given aggregates
+func (c Column) WithAggregates(functions ...aggregate.AggregateFunction) Column {
+	c.Aggregates = functions
+	return c
+}
+
+// AllowsOperator reports whether the operator may be used on the column
+func (c Column) AllowsOperator(operator fields.ComparisonOperator) bool {
+	operators := c.Operators
+	if len(operators) == 0 {
+		operators = defaultOperators(c.Type)
+	}
+	for _, allowed := range operators {
+		if allowed == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsAggregate reports whether the aggregate may be applied to the column
+func (c Column) AllowsAggregate(function aggregate.AggregateFunction) bool {
+	functions := c.Aggregates
+	if len(functions) == 0 {
+		functions = defaultAggregates(c.Type)
+	}
+	for _, allowed := range functions {
+		if allowed == function {
+			return true
+		}
+	}
+	return false
+}
+
+// Table describes an allowed table and its columns
+type Table struct {
+	Name    string
+	Columns map[string]Column
+}
+
+// Column looks up a column by name
+func (t *Table) Column(name string) (Column, bool) {
+	column, ok := t.Columns[name]
+	return column, ok
+}
+
+// column looks up a column, ignoring case when the dialect folds names
+func (t *Table) column(name string, fold bool) (Column, bool) {
+	if column, ok := t.Columns[name]; ok || !fold {
+		return column, ok
+	}
+	for key, column := range t.Columns {
+		if strings.EqualFold(key, name) {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+// Registry holds the tables and columns user-driven queries may reference
+type Registry struct {
+	tables map[string]*Table
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		tables: make(map[string]*Table),
+	}
+}
+
+// Register adds a table with its allowed columns, replacing any previous registration
+func (r *Registry) Register(table string, columns ...Column) *Registry {
+	t := &Table{
+		Name:    table,
+		Columns: make(map[string]Column, len(columns)),
+	}
+	for _, column := range columns {
+		t.Columns[column.Name] = column
+	}
+	r.tables[table] = t
+	return r
+}
+
+// Table looks up a registered table by name, e.g. transactions or public.transactions
+func (r *Registry) Table(name string) (*Table, bool) {
+	table, ok := r.tables[name]
+	return table, ok
+}
+
+// table looks up a table, ignoring case when the dialect folds names
+func (r *Registry) table(name string, fold bool) (*Table, bool) {
+	if table, ok := r.tables[name]; ok || !fold {
+		return table, ok
+	}
+	for key, table := range r.tables {
+		if strings.EqualFold(key, name) {
+			return table, true
+		}
+	}
+	return nil, false
+}
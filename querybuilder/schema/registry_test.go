@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumn_Defaults(t *testing.T) {
+	tests := []struct {
+		name              string
+		column            Column
+		allowedOperators  []fields.ComparisonOperator
+		blockedOperators  []fields.ComparisonOperator
+		allowedAggregates []aggregate.AggregateFunction
+		blockedAggregates []aggregate.AggregateFunction
+	}{
+		{
+			name:              "Text",
+			column:            NewColumn("description", Text),
+			allowedOperators:  []fields.ComparisonOperator{fields.Equals, fields.In, fields.ILike, fields.NotLike, fields.Regex, fields.IsNull},
+			blockedOperators:  []fields.ComparisonOperator{fields.GreaterThan, fields.Between, fields.Contains},
+			allowedAggregates: []aggregate.AggregateFunction{aggregate.Count, aggregate.Max},
+			blockedAggregates: []aggregate.AggregateFunction{aggregate.Sum, aggregate.Avg},
+		},
+		{
+			name:              "Numeric",
+			column:            NewColumn("amount", Numeric),
+			allowedOperators:  []fields.ComparisonOperator{fields.Equals, fields.GreaterOrEqual, fields.NotIn, fields.Between, fields.IsDistinctFrom},
+			blockedOperators:  []fields.ComparisonOperator{fields.Like, fields.IsTrue},
+			allowedAggregates: []aggregate.AggregateFunction{aggregate.Sum, aggregate.Avg, aggregate.Min},
+		},
+		{
+			name:              "Boolean",
+			column:            NewColumn("is_active", Boolean),
+			allowedOperators:  []fields.ComparisonOperator{fields.Equals, fields.IsNotNull, fields.IsTrue, fields.IsFalse},
+			blockedOperators:  []fields.ComparisonOperator{fields.LessThan, fields.ILike, fields.NotBetween},
+			allowedAggregates: []aggregate.AggregateFunction{aggregate.Count},
+			blockedAggregates: []aggregate.AggregateFunction{aggregate.Max},
+		},
+		{
+			name: "Explicit restrictions",
+			column: NewColumn("account_code", Text).
+				WithOperators(fields.Equals, fields.In).
+				WithAggregates(aggregate.Count),
+			allowedOperators:  []fields.ComparisonOperator{fields.Equals, fields.In},
+			blockedOperators:  []fields.ComparisonOperator{fields.Like, fields.IsNull},
+			allowedAggregates: []aggregate.AggregateFunction{aggregate.Count},
+			blockedAggregates: []aggregate.AggregateFunction{aggregate.Max},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, operator := range tt.allowedOperators {
+				assert.True(t, tt.column.AllowsOperator(operator), "operator %s should be allowed", operator)
+			}
+			for _, operator := range tt.blockedOperators {
+				assert.False(t, tt.column.AllowsOperator(operator), "operator %s should be blocked", operator)
+			}
+			for _, function := range tt.allowedAggregates {
+				assert.True(t, tt.column.AllowsAggregate(function), "aggregate %s should be allowed", function)
+			}
+			for _, function := range tt.blockedAggregates {
+				assert.False(t, tt.column.AllowsAggregate(function), "aggregate %s should be blocked", function)
+			}
+		})
+	}
+}
+
+func TestRegistry_Table(t *testing.T) {
+	registry := NewRegistry().
+		Register("public.transactions", NewColumn("amount", Numeric))
+
+	table, ok := registry.Table("public.transactions")
+	assert.True(t, ok)
+	_, ok = table.Column("amount")
+	assert.True(t, ok)
+
+	_, ok = registry.Table("transactions")
+	assert.False(t, ok)
+}
@@ -0,0 +1,290 @@
+package schema
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/condition/having"
+	"dynamic-sqlbuilder/querybuilder/condition/wheregroups"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"dynamic-sqlbuilder/querybuilder/pagination/keyset"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"fmt"
+	"strings"
+)
+
+// Clause names used in FieldError
+const (
+	ClauseSelect  = "SELECT"
+	ClauseFrom    = "FROM"
+	ClauseJoin    = "JOIN"
+	ClauseWhere   = "WHERE"
+	ClauseGroupBy = "GROUP BY"
+	ClauseHaving  = "HAVING"
+	ClauseOrderBy = "ORDER BY"
+)
+
+// TableRef is a table referenced in the FROM clause
+type TableRef struct {
+	Name  string
+	Alias string
+}
+
+// Scope validates the fields of one query against the registry, resolving
+// column references through the tables in its FROM clause
+type Scope struct {
+	registry *Registry
+	fold     bool              // Names fold to lower case, so they are matched ignoring case
+	tables   map[string]*Table // Keyed by alias, or by name when not aliased
+	ordered  []*Table
+	aliases  map[string]bool // Select output aliases usable in HAVING and ORDER BY
+	errs     FieldErrors
+}
+
+// NewScope creates a scope for the given FROM tables, recording unknown tables.
+// Names are matched exactly, see NewDialectScope.
+func (r *Registry) NewScope(tables ...TableRef) *Scope {
+	return r.newScope(false, tables)
+}
+
+// NewDialectScope creates a scope that matches names the way the dialect
+// does, ignoring case when it folds names to lower case
+func (r *Registry) NewDialectScope(d dialect.Dialect, tables ...TableRef) *Scope {
+	return r.newScope(d.Features().FoldLowerCase, tables)
+}
+
+func (r *Registry) newScope(fold bool, tables []TableRef) *Scope {
+	s := &Scope{
+		registry: r,
+		fold:     fold,
+		tables:   make(map[string]*Table),
+		aliases:  make(map[string]bool),
+	}
+	for _, ref := range tables {
+		s.AddTable(ClauseFrom, ref)
+	}
+	return s
+}
+
+// AddTable brings a table into scope, e.g. a joined table
+func (s *Scope) AddTable(clause string, ref TableRef) {
+	table, ok := s.registry.table(ref.Name, s.fold)
+	if !ok {
+		s.fail(UnknownTable, clause, ref.Name, "")
+		return
+	}
+
+	key := ref.Alias
+	if key == "" {
+		key = ref.Name
+	}
+	s.tables[s.key(key)] = table
+	s.ordered = append(s.ordered, table)
+}
+
+// AddAliases registers select output aliases
+func (s *Scope) AddAliases(aliases ...string) {
+	for _, alias := range aliases {
+		s.aliases[s.key(alias)] = true
+	}
+}
+
+// key normalizes a table alias or select alias the way the dialect folds it
+func (s *Scope) key(name string) string {
+	if s.fold {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// Err returns the collected errors as FieldErrors, or nil when valid
+func (s *Scope) Err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return s.errs
+}
+
+func (s *Scope) fail(kind ErrorKind, clause, field, detail string) {
+	s.errs = append(s.errs, &FieldError{
+		Kind:   kind,
+		Clause: clause,
+		Field:  field,
+		Detail: detail,
+	})
+}
+
+// resolve finds the column a field name refers to. Star references resolve
+// to a zero Column with ok set.
+func (s *Scope) resolve(clause, field string) (Column, bool) {
+	if field == "*" {
+		return Column{}, true
+	}
+
+	dot := strings.LastIndex(field, ".")
+	if dot >= 0 {
+		qualifier, name := field[:dot], field[dot+1:]
+		table, ok := s.tables[s.key(qualifier)]
+		if !ok {
+			s.fail(UnknownTable, clause, field, fmt.Sprintf("%s is not in the FROM clause", qualifier))
+			return Column{}, false
+		}
+		if name == "*" {
+			return Column{}, true
+		}
+		column, ok := table.column(name, s.fold)
+		if !ok {
+			s.fail(UnknownColumn, clause, field, "")
+			return Column{}, false
+		}
+		return column, true
+	}
+
+	var found []Column
+	for _, table := range s.ordered {
+		if column, ok := table.column(field, s.fold); ok {
+			found = append(found, column)
+		}
+	}
+	switch len(found) {
+	case 0:
+		s.fail(UnknownColumn, clause, field, "")
+		return Column{}, false
+	case 1:
+		return found[0], true
+	default:
+		s.fail(AmbiguousColumn, clause, field, "qualify the column with a table name or alias")
+		return Column{}, false
+	}
+}
+
+func (s *Scope) isAlias(clause, field string) bool {
+	return (clause == ClauseHaving || clause == ClauseOrderBy) && s.aliases[s.key(field)]
+}
+
+// CheckField validates a plain field reference
+func (s *Scope) CheckField(clause string, field identifier.Expr) {
+	if field.Raw {
+		s.fail(RawExpressionBlocked, clause, field.Text, "")
+		return
+	}
+	if s.isAlias(clause, field.Text) {
+		return
+	}
+	s.resolve(clause, field.Text)
+}
+
+// CheckColumnIn validates a column that must exist in one specific table,
+// such as a USING column
+func (s *Scope) CheckColumnIn(clause string, ref TableRef, column string) {
+	table, ok := s.registry.table(ref.Name, s.fold)
+	if !ok {
+		return // Already reported when the table was added
+	}
+	if _, ok := table.column(column, s.fold); !ok {
+		s.fail(UnknownColumn, clause, fmt.Sprintf("%s.%s", ref.Name, column), "")
+	}
+}
+
+// CheckAggregate validates an aggregate function applied to a column
+func (s *Scope) CheckAggregate(clause string, agg aggregate.AggregateField) {
+	column, ok := s.resolve(clause, agg.Field)
+	if !ok {
+		return
+	}
+	if agg.Field == "*" {
+		if agg.Function != aggregate.Count {
+			s.fail(AggregateNotAllowed, clause, agg.Field, fmt.Sprintf("%s is not allowed", agg.Function))
+		}
+		return
+	}
+	if !column.AllowsAggregate(agg.Function) {
+		s.fail(AggregateNotAllowed, clause, agg.Field, fmt.Sprintf("%s is not allowed", agg.Function))
+	}
+}
+
+// CheckCondition walks a condition tree and validates every field condition in it.
+// Condition types it does not know fail validation, their fields cannot be checked.
+func (s *Scope) CheckCondition(clause string, condition querybuilder.QueryCondition) {
+	switch c := condition.(type) {
+	case *fields.FieldCondition:
+		if c.RawField {
+			s.fail(RawExpressionBlocked, clause, c.Field, "")
+			return
+		}
+		if s.isAlias(clause, c.Field) {
+			return
+		}
+		column, ok := s.resolve(clause, c.Field)
+		if ok && !column.AllowsOperator(c.Operator) {
+			s.fail(OperatorNotAllowed, clause, c.Field, fmt.Sprintf("%s is not allowed", c.Operator))
+		}
+	case *fields.ColumnCondition:
+		for _, field := range []string{c.Left, c.Right} {
+			column, ok := s.resolve(clause, field)
+			if ok && !column.AllowsOperator(c.Operator) {
+				s.fail(OperatorNotAllowed, clause, field, fmt.Sprintf("%s is not allowed", c.Operator))
+			}
+		}
+	case *daterange.DateRangeCondition:
+		types := dateRangeTypes[c.ColumnMode()]
+		for _, field := range c.Columns() {
+			column, ok := s.resolve(clause, field)
+			if ok && !hasType(types, column.Type) {
+				s.fail(ColumnTypeMismatch, clause, field,
+					fmt.Sprintf("the %s mode requires a %s column, got %s", c.ColumnMode(), joinTypes(types), column.Type))
+			}
+		}
+	case *querybuilder.WhereGroup:
+		for _, cond := range c.Conditions {
+			s.CheckCondition(clause, cond)
+		}
+	case *querybuilder.CompoundCondition:
+		for _, cond := range c.Conditions {
+			s.CheckCondition(clause, cond)
+		}
+	case *querybuilder.NotCondition:
+		s.CheckCondition(clause, c.Condition)
+	case *wheregroups.WhereGroups:
+		for _, group := range c.Groups {
+			s.CheckCondition(clause, group)
+		}
+	case *having.Having:
+		s.CheckCondition(clause, c.Group)
+	case *keyset.Keyset:
+		for _, key := range c.Keys {
+			column, ok := s.resolve(clause, key.Field)
+			operator := keyset.Comparison(key)
+			if ok && !column.AllowsOperator(operator) {
+				s.fail(OperatorNotAllowed, clause, key.Field, fmt.Sprintf("%s is not allowed", operator))
+			}
+		}
+	default:
+		s.fail(UnsupportedCondition, clause, fmt.Sprintf("%T", condition), "its fields cannot be validated")
+	}
+}
+
+// dateRangeTypes lists the column types each date range column mode can filter
+var dateRangeTypes = map[daterange.ColumnMode][]ColumnType{
+	daterange.DateColumn:       {Date, Timestamp},
+	daterange.YearMonthColumns: {Integer, Numeric},
+	daterange.PeriodKeyColumn:  {Integer, Numeric},
+}
+
+func hasType(types []ColumnType, columnType ColumnType) bool {
+	for _, t := range types {
+		if t == columnType {
+			return true
+		}
+	}
+	return false
+}
+
+func joinTypes(types []ColumnType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, " or ")
+}
@@ -0,0 +1,266 @@
+package schema
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/pagination/keyset"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistry() *Registry {
+	return NewRegistry().
+		Register("transactions",
+			NewColumn("account_code", Text).WithOperators(fields.Equals, fields.In),
+			NewColumn("department", Text),
+			NewColumn("amount", Numeric),
+			NewColumn("is_active", Boolean),
+		).
+		Register("chart_of_accounts",
+			NewColumn("account_code", Text),
+			NewColumn("coadescription", Text),
+		).
+		Register("ledger",
+			NewColumn("entry_id", Integer),
+			NewColumn("period_year", Integer),
+			NewColumn("period_month", Text),
+			NewColumn("posted_at", Date),
+		)
+}
+
+// customCondition is a condition type the scope does not know
+type customCondition struct{}
+
+func (customCondition) Build(dialect.Dialect, int) (string, []interface{}, error) {
+	return "password = 'secret'", nil, nil
+}
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		check          func(s *Scope)
+		dialect        dialect.Dialect // Matches names exactly when nil
+		tables         []TableRef
+		expectedErrors []FieldError
+	}{
+		{
+			name:   "Valid fields, conditions and aggregates",
+			tables: []TableRef{{Name: "transactions", Alias: "t"}, {Name: "chart_of_accounts", Alias: "coa"}},
+			check: func(s *Scope) {
+				s.CheckField(ClauseSelect, identifier.Name("coa.coadescription"))
+				s.CheckField(ClauseSelect, identifier.Name("t.*"))
+				s.CheckAggregate(ClauseSelect, aggregate.AggregateField{Function: aggregate.Sum, Field: "amount"})
+				s.CheckAggregate(ClauseSelect, aggregate.AggregateField{Function: aggregate.Count, Field: "*"})
+
+				group := querybuilder.NewWhereGroup(querybuilder.AND)
+				group.Add(fields.NewFieldCondition("t.account_code", fields.In, []interface{}{"1001"}))
+				group.Add(fields.NewFieldCondition("is_active", fields.Equals, true))
+				group.Add(fields.NewColumnCondition("t.account_code", fields.Equals, "coa.account_code"))
+				s.CheckCondition(ClauseWhere, group)
+			},
+		},
+		{
+			name:   "Unknown table",
+			tables: []TableRef{{Name: "users"}},
+			expectedErrors: []FieldError{
+				{Kind: UnknownTable, Clause: ClauseFrom, Field: "users"},
+			},
+		},
+		{
+			name:   "Unknown and ambiguous columns",
+			tables: []TableRef{{Name: "transactions"}, {Name: "chart_of_accounts"}},
+			check: func(s *Scope) {
+				s.CheckField(ClauseSelect, identifier.Name("password"))
+				s.CheckField(ClauseGroupBy, identifier.Name("account_code"))
+				s.CheckField(ClauseSelect, identifier.Name("x.amount"))
+			},
+			expectedErrors: []FieldError{
+				{Kind: UnknownColumn, Clause: ClauseSelect, Field: "password"},
+				{Kind: AmbiguousColumn, Clause: ClauseGroupBy, Field: "account_code"},
+				{Kind: UnknownTable, Clause: ClauseSelect, Field: "x.amount"},
+			},
+		},
+		{
+			name:   "Operator and aggregate not allowed",
+			tables: []TableRef{{Name: "transactions"}},
+			check: func(s *Scope) {
+				s.CheckCondition(ClauseWhere, fields.NewFieldCondition("account_code", fields.Like, "10%"))
+				s.CheckCondition(ClauseWhere, fields.NewFieldCondition("department", fields.GreaterThan, "A"))
+				s.CheckAggregate(ClauseSelect, aggregate.AggregateField{Function: aggregate.Sum, Field: "department"})
+			},
+			expectedErrors: []FieldError{
+				{Kind: OperatorNotAllowed, Clause: ClauseWhere, Field: "account_code"},
+				{Kind: OperatorNotAllowed, Clause: ClauseWhere, Field: "department"},
+				{Kind: AggregateNotAllowed, Clause: ClauseSelect, Field: "department"},
+			},
+		},
+		{
+			name:   "Column comparison operators not allowed",
+			tables: []TableRef{{Name: "transactions", Alias: "t"}, {Name: "chart_of_accounts", Alias: "coa"}},
+			check: func(s *Scope) {
+				s.CheckCondition(ClauseWhere, fields.NewColumnCondition("t.account_code", fields.GreaterThan, "coa.account_code"))
+				s.CheckCondition(ClauseWhere, fields.NewColumnCondition("coa.account_code", fields.Like, "t.department"))
+			},
+			expectedErrors: []FieldError{
+				{Kind: OperatorNotAllowed, Clause: ClauseWhere, Field: "t.account_code"},
+				{Kind: OperatorNotAllowed, Clause: ClauseWhere, Field: "coa.account_code"},
+			},
+		},
+		{
+			name:   "Unsupported conditions are rejected",
+			tables: []TableRef{{Name: "transactions"}},
+			check: func(s *Scope) {
+				s.CheckCondition(ClauseWhere, customCondition{})
+				s.CheckCondition(ClauseHaving, querybuilder.Or(fields.NewFieldCondition("amount", fields.GreaterThan, 0), customCondition{}))
+			},
+			expectedErrors: []FieldError{
+				{Kind: UnsupportedCondition, Clause: ClauseWhere, Field: "schema.customCondition"},
+				{Kind: UnsupportedCondition, Clause: ClauseHaving, Field: "schema.customCondition"},
+			},
+		},
+		{
+			name:   "Raw expressions are blocked",
+			tables: []TableRef{{Name: "transactions"}},
+			check: func(s *Scope) {
+				s.CheckField(ClauseSelect, identifier.UnsafeRaw("pg_sleep(10)"))
+				s.CheckCondition(ClauseWhere, fields.NewUnsafeRawCondition("1", fields.Equals, 1))
+			},
+			expectedErrors: []FieldError{
+				{Kind: RawExpressionBlocked, Clause: ClauseSelect, Field: "pg_sleep(10)"},
+				{Kind: RawExpressionBlocked, Clause: ClauseWhere, Field: "1"},
+			},
+		},
+		{
+			name:   "Aliases only in HAVING and ORDER BY",
+			tables: []TableRef{{Name: "transactions"}},
+			check: func(s *Scope) {
+				s.AddAliases("total_amount")
+				s.CheckCondition(ClauseHaving, fields.NewFieldCondition("total_amount", fields.GreaterThan, 0))
+				s.CheckField(ClauseOrderBy, identifier.Name("total_amount"))
+				s.CheckCondition(ClauseWhere, fields.NewFieldCondition("total_amount", fields.GreaterThan, 0))
+			},
+			expectedErrors: []FieldError{
+				{Kind: UnknownColumn, Clause: ClauseWhere, Field: "total_amount"},
+			},
+		},
+		{
+			name:   "Date range columns",
+			tables: []TableRef{{Name: "transactions"}},
+			check: func(s *Scope) {
+				s.CheckCondition(ClauseWhere, daterange.NewDateRangeCondition(daterange.DateConfig{Type: daterange.YTD}))
+				s.CheckCondition(ClauseWhere, daterange.NewDateRangeCondition(daterange.DateConfig{
+					Type:    daterange.YTD,
+					Columns: &daterange.PeriodColumns{Mode: daterange.DateColumn, Column: "posted_at"},
+				}))
+			},
+			expectedErrors: []FieldError{
+				{Kind: UnknownColumn, Clause: ClauseWhere, Field: "period_year"},
+				{Kind: UnknownColumn, Clause: ClauseWhere, Field: "period_month"},
+				{Kind: UnknownColumn, Clause: ClauseWhere, Field: "posted_at"},
+			},
+		},
+		{
+			name:   "Date range column types must match the mode",
+			tables: []TableRef{{Name: "ledger"}},
+			check: func(s *Scope) {
+				s.CheckCondition(ClauseWhere, daterange.NewDateRangeCondition(daterange.DateConfig{
+					Type:    daterange.YTD,
+					Columns: &daterange.PeriodColumns{Mode: daterange.DateColumn, Column: "posted_at"},
+				}))
+				s.CheckCondition(ClauseWhere, daterange.NewDateRangeCondition(daterange.DateConfig{
+					Type:    daterange.YTD,
+					Columns: &daterange.PeriodColumns{Mode: daterange.DateColumn, Column: "entry_id"},
+				}))
+				s.CheckCondition(ClauseWhere, daterange.NewDateRangeCondition(daterange.DateConfig{Type: daterange.YTD}))
+				s.CheckCondition(ClauseWhere, daterange.NewDateRangeCondition(daterange.DateConfig{
+					Type:    daterange.YTD,
+					Columns: &daterange.PeriodColumns{Mode: daterange.PeriodKeyColumn, Column: "posted_at"},
+				}))
+			},
+			expectedErrors: []FieldError{
+				{Kind: ColumnTypeMismatch, Clause: ClauseWhere, Field: "entry_id"},
+				{Kind: ColumnTypeMismatch, Clause: ClauseWhere, Field: "period_month"},
+				{Kind: ColumnTypeMismatch, Clause: ClauseWhere, Field: "posted_at"},
+			},
+		},
+		{
+			name:   "Keyset comparison operators",
+			tables: []TableRef{{Name: "transactions"}, {Name: "ledger"}},
+			check: func(s *Scope) {
+				s.CheckCondition(ClauseWhere, keyset.NewKeyset(
+					[]simpleorderby.OrderKey{simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("entry_id")},
+					[]interface{}{"2024-05-01", 10}))
+				s.CheckCondition(ClauseWhere, keyset.NewKeyset(
+					[]simpleorderby.OrderKey{simpleorderby.Field("account_code").Desc()}, []interface{}{"1001"}))
+			},
+			expectedErrors: []FieldError{
+				{Kind: OperatorNotAllowed, Clause: ClauseWhere, Field: "account_code"},
+			},
+		},
+		{
+			name:    "Folded names match ignoring case",
+			dialect: dialect.PostgresFolded,
+			tables:  []TableRef{{Name: "Ledger", Alias: "L"}},
+			check: func(s *Scope) {
+				s.AddAliases("Last_Posted")
+				s.CheckField(ClauseSelect, identifier.Name("l.Posted_At"))
+				s.CheckField(ClauseSelect, identifier.Name("Entry_ID"))
+				s.CheckField(ClauseOrderBy, identifier.Name("last_posted"))
+			},
+		},
+		{
+			name:    "Names match exactly without folding",
+			dialect: dialect.Postgres,
+			tables:  []TableRef{{Name: "Ledger"}, {Name: "ledger", Alias: "l"}},
+			check: func(s *Scope) {
+				s.CheckField(ClauseSelect, identifier.Name("L.posted_at"))
+				s.CheckField(ClauseSelect, identifier.Name("l.Posted_At"))
+			},
+			expectedErrors: []FieldError{
+				{Kind: UnknownTable, Clause: ClauseFrom, Field: "Ledger"},
+				{Kind: UnknownTable, Clause: ClauseSelect, Field: "L.posted_at"},
+				{Kind: UnknownColumn, Clause: ClauseSelect, Field: "l.Posted_At"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := testRegistry().NewScope(tt.tables...)
+			if tt.dialect != nil {
+				scope = testRegistry().NewDialectScope(tt.dialect, tt.tables...)
+			}
+			if tt.check != nil {
+				tt.check(scope)
+			}
+
+			err := scope.Err()
+			if len(tt.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fieldErrors FieldErrors
+			require.True(t, errors.As(err, &fieldErrors))
+			require.Len(t, fieldErrors, len(tt.expectedErrors))
+			for i, expected := range tt.expectedErrors {
+				assert.Equal(t, expected.Kind, fieldErrors[i].Kind)
+				assert.Equal(t, expected.Clause, fieldErrors[i].Clause)
+				assert.Equal(t, expected.Field, fieldErrors[i].Field)
+			}
+		})
+	}
+}
+
+func TestFieldError_Error(t *testing.T) {
+	err := &FieldError{Kind: OperatorNotAllowed, Clause: ClauseWhere, Field: "account_code", Detail: "LIKE is not allowed"}
+	assert.Equal(t, "WHERE: operator not allowed account_code (LIKE is not allowed)", err.Error())
+}
@@ -1,7 +1,11 @@
 package aggregate
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -16,6 +20,12 @@ const (
 	Min   AggregateFunction = "MIN"
 )
 
+// Condition restricts the rows an aggregate sees. It matches
+// querybuilder.QueryCondition, which this package cannot import.
+type Condition interface {
+	Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error)
+}
+
 // AggregateField represents a field with its aggregate function
 type AggregateField struct {
 	Function AggregateFunction
@@ -23,20 +33,104 @@ type AggregateField struct {
 	Alias    string
 }
 
+// argument returns the quoted aggregate argument, * for COUNT(*)
+func (af AggregateField) argument(d dialect.Dialect) (string, error) {
+	switch af.Function {
+	case Sum, Avg, Count, Max, Min:
+	default:
+		return "", fmt.Errorf("invalid aggregate function: %s", af.Function)
+	}
+
+	if af.Field == "*" {
+		if af.Function != Count {
+			return "", fmt.Errorf("%s(*) is not allowed, only COUNT(*)", af.Function)
+		}
+		return "*", nil
+	}
+	return identifier.Quote(d, af.Field)
+}
+
+// Expression returns the SQL aggregate expression without its alias, e.g. SUM("amount")
+func (af AggregateField) Expression(d dialect.Dialect) (string, error) {
+	argument, err := af.argument(d)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s(%s)", af.Function, argument), nil
+}
+
+// FilteredExpression returns the aggregate over the rows matching filter, e.g.
+// SUM("amount") FILTER (WHERE ...). Dialects without FILTER get
+// SUM(CASE WHEN ... THEN "amount" END), which ignores the same rows.
+func (af AggregateField) FilteredExpression(d dialect.Dialect, filter Condition, paramOffset int) (string, []interface{}, error) {
+	argument, err := af.argument(d)
+	if err != nil {
+		return "", nil, err
+	}
+	condition, args, err := filter.Build(d, paramOffset)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if d.Features().AggregateFilter {
+		return fmt.Sprintf("%s(%s) FILTER (WHERE %s)", af.Function, argument, condition), args, nil
+	}
+	if argument == "*" {
+		argument = "1"
+	}
+	return fmt.Sprintf("%s(CASE WHEN %s THEN %s END)", af.Function, condition, argument), args, nil
+}
+
+// Comparison prefixes for the aggregate aliases of a period comparison
+const (
+	CurrentPrefix    = "current_"
+	ComparisonPrefix = "comparison_"
+)
+
+// Bucket is one period of a bucketed select, such as a month of a year
+type Bucket struct {
+	Key    string    // Period label, bound as a parameter of the label column
+	Alias  string    // Column alias of the period in a pivot, e.g. m_2024_01
+	Filter Condition // Restricts the rows to the period
+}
+
+// BucketSource lists the buckets of a select. It is called when the select is
+// built, so a relative range such as the last 3 months resolves at build time.
+type BucketSource interface {
+	Buckets() ([]Bucket, error)
+}
+
 // AggregateSelect implements SELECT with aggregate functions
 type AggregateSelect struct {
-	regularFields []string
+	regularFields []identifier.Expr
 	aggregates    []AggregateField
+	autoGroupBy   bool // Derive GROUP BY from regular fields
+
+	// Period comparison, every aggregate is split into a current_ and a comparison_ column
+	current, comparison Condition
+
+	// Period buckets, a pivot column per bucket and aggregate, or a label
+	// column named labelAlias when set
+	buckets    BucketSource
+	labelAlias string
 }
 
 func NewAggregateSelect() *AggregateSelect {
 	return &AggregateSelect{
-		regularFields: make([]string, 0),
+		regularFields: make([]identifier.Expr, 0),
 		aggregates:    make([]AggregateField, 0),
 	}
 }
 func (as *AggregateSelect) AddRegularField(field string) *AggregateSelect {
-	as.regularFields = append(as.regularFields, field)
+	as.regularFields = append(as.regularFields, identifier.Name(field))
+	return as
+}
+
+// AddUnsafeRawField adds a raw non-aggregated expression such as
+// date_trunc('month', posted_at). It is emitted verbatim and must never come
+// from user input.
+func (as *AggregateSelect) AddUnsafeRawField(expression string) *AggregateSelect {
+	as.regularFields = append(as.regularFields, identifier.UnsafeRaw(expression))
 	return as
 }
 func (as *AggregateSelect) AddAggregate(fn AggregateFunction, field, alias string) *AggregateSelect {
@@ -48,26 +142,344 @@ func (as *AggregateSelect) AddAggregate(fn AggregateFunction, field, alias strin
 	return as
 }
 
-func (as *AggregateSelect) Build() (string, error) {
+// WithAutoGroupBy makes the builder derive the GROUP BY list from the regular fields
+func (as *AggregateSelect) WithAutoGroupBy() *AggregateSelect {
+	as.autoGroupBy = true
+	return as
+}
+
+// WithComparison splits every aggregate into two columns, current_<alias>
+// filtered by current and comparison_<alias> filtered by comparison, e.g.
+// SUM("amount") FILTER (WHERE <current>) AS "current_total". Each aggregate
+// needs an alias.
+func (as *AggregateSelect) WithComparison(current, comparison Condition) *AggregateSelect {
+	as.current, as.comparison = current, comparison
+	return as
+}
+
+// WithPivot splits every aggregate into one column per bucket, e.g.
+// SUM("amount") FILTER (WHERE <bucket>) AS "total_m_2024_01". The column is
+// named by the bucket alone when the aggregate has no alias, which only one
+// aggregate may leave out.
+func (as *AggregateSelect) WithPivot(buckets BucketSource) *AggregateSelect {
+	as.buckets, as.labelAlias = buckets, ""
+	return as
+}
+
+// WithPeriodLabel adds a column after the regular fields that labels each row
+// with its bucket, CASE WHEN <bucket> THEN <key> ... END AS alias, and groups by
+// it. The label is grouped by its position, which the dialect must support.
+func (as *AggregateSelect) WithPeriodLabel(alias string, buckets BucketSource) *AggregateSelect {
+	as.buckets, as.labelAlias = buckets, alias
+	return as
+}
+
+// Comparison returns the conditions set with WithComparison, nil when not comparing
+func (as *AggregateSelect) Comparison() (current, comparison Condition) {
+	return as.current, as.comparison
+}
+
+// PeriodBuckets returns the buckets set with WithPivot or WithPeriodLabel and
+// the label alias, empty for a pivot. Buckets is nil when not bucketed.
+func (as *AggregateSelect) PeriodBuckets() (buckets BucketSource, labelAlias string) {
+	return as.buckets, as.labelAlias
+}
+
+// AutoGroupBy reports whether the GROUP BY list should be derived from the regular fields
+func (as *AggregateSelect) AutoGroupBy() bool {
+	return as.autoGroupBy
+}
+
+// RegularFields returns the non-aggregated fields in select order
+func (as *AggregateSelect) RegularFields() []identifier.Expr {
+	fields := make([]identifier.Expr, len(as.regularFields))
+	copy(fields, as.regularFields)
+	return fields
+}
+
+// Aggregates returns the aggregate fields in select order
+func (as *AggregateSelect) Aggregates() []AggregateField {
+	aggregates := make([]AggregateField, len(as.aggregates))
+	copy(aggregates, as.aggregates)
+	return aggregates
+}
+
+// GroupByFields returns the regular (non-aggregated) fields in select order,
+// followed by the position of the period label column
+func (as *AggregateSelect) GroupByFields() []identifier.Expr {
+	fields := as.RegularFields()
+	if as.labelled() {
+		fields = append(fields, as.labelPosition())
+	}
+	return fields
+}
+
+// AliasExpressions maps each aggregate alias to its aggregate expression so
+// clauses that cannot see select aliases (such as HAVING) can resolve them.
+// Comparison and pivot columns carry parameters and are not included.
+func (as *AggregateSelect) AliasExpressions(d dialect.Dialect) (map[string]string, error) {
+	aliases := make(map[string]string)
+	if as.comparing() || as.pivoted() {
+		return aliases, nil
+	}
+	for _, agg := range as.aggregates {
+		if agg.Alias != "" {
+			expression, err := agg.Expression(d)
+			if err != nil {
+				return nil, err
+			}
+			aliases[agg.Alias] = expression
+		}
+	}
+	return aliases, nil
+}
+
+// Aliases returns the aliases of the period label and aggregate fields in select order
+func (as *AggregateSelect) Aliases() []string {
+	aliases := make([]string, 0)
+	if as.labelled() {
+		aliases = append(aliases, as.labelAlias)
+	}
+	if as.pivoted() {
+		// A bucket error fails the select itself, which is built first
+		buckets, _ := as.buckets.Buckets()
+		for _, agg := range as.aggregates {
+			for _, bucket := range buckets {
+				aliases = append(aliases, pivotAlias(agg, bucket))
+			}
+		}
+		return aliases
+	}
+	for _, agg := range as.aggregates {
+		if agg.Alias == "" {
+			continue
+		}
+		if as.comparing() {
+			aliases = append(aliases, CurrentPrefix+agg.Alias, ComparisonPrefix+agg.Alias)
+		} else {
+			aliases = append(aliases, agg.Alias)
+		}
+	}
+	return aliases
+}
+
+// ColumnCount returns the number of output columns
+func (as *AggregateSelect) ColumnCount() int {
+	switch {
+	case as.comparing():
+		return len(as.regularFields) + 2*len(as.aggregates)
+	case as.pivoted():
+		buckets, _ := as.buckets.Buckets()
+		return len(as.regularFields) + len(buckets)*len(as.aggregates)
+	case as.labelled():
+		return len(as.regularFields) + 1 + len(as.aggregates)
+	}
+	return len(as.regularFields) + len(as.aggregates)
+}
+
+func (as *AggregateSelect) comparing() bool {
+	return as.current != nil && as.comparison != nil
+}
+
+func (as *AggregateSelect) pivoted() bool {
+	return as.buckets != nil && as.labelAlias == ""
+}
+
+func (as *AggregateSelect) labelled() bool {
+	return as.buckets != nil && as.labelAlias != ""
+}
+
+// labelPosition returns the GROUP BY entry of the period label column, its
+// position in the select list. It is generated here and never user input.
+func (as *AggregateSelect) labelPosition() identifier.Expr {
+	return identifier.UnsafeRaw(strconv.Itoa(len(as.regularFields) + 1))
+}
+
+// pivotAlias names the column of an aggregate over a bucket
+func pivotAlias(agg AggregateField, bucket Bucket) string {
+	if agg.Alias == "" {
+		return bucket.Alias
+	}
+	return agg.Alias + "_" + bucket.Alias
+}
+
+// ValidateGroupBy checks that every regular field appears in the GROUP BY list.
+// Postgres rejects a non-aggregated column that is not grouped, so this is
+// enforced whenever aggregates or a GROUP BY list are present.
+func (as *AggregateSelect) ValidateGroupBy(groupBy []identifier.Expr) error {
+	if len(as.aggregates) == 0 && len(groupBy) == 0 {
+		return nil
+	}
+
+	grouped := make(map[identifier.Expr]bool, len(groupBy))
+	for _, field := range groupBy {
+		grouped[field] = true
+	}
+
+	for _, field := range as.regularFields {
+		if !grouped[field] {
+			return fmt.Errorf("field %s must appear in GROUP BY or be used in an aggregate function", field.Text)
+		}
+	}
+	if as.labelled() && !grouped[as.labelPosition()] {
+		return fmt.Errorf("period label %s is grouped by position, use AutoGroupBy", as.labelAlias)
+	}
+	return nil
+}
+
+func (as *AggregateSelect) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
 	var fields []string
+	var args []interface{}
+
+	var buckets []Bucket
+	if as.buckets != nil {
+		if as.comparing() {
+			return "", nil, errors.New("period buckets cannot be combined with a period comparison")
+		}
+		var err error
+		if buckets, err = as.buckets.Buckets(); err != nil {
+			return "", nil, err
+		}
+		if len(buckets) == 0 {
+			return "", nil, errors.New("no period buckets to select")
+		}
+	}
 
 	// Add regular fields
-	fields = append(fields, as.regularFields...)
+	for _, field := range as.regularFields {
+		sql, err := field.Build(d)
+		if err != nil {
+			return "", nil, err
+		}
+		fields = append(fields, sql)
+	}
+
+	if as.labelled() {
+		label, labelArgs, err := as.buildLabel(d, buckets, paramOffset+len(args))
+		if err != nil {
+			return "", nil, err
+		}
+		fields = append(fields, label)
+		args = append(args, labelArgs...)
+	}
 
 	// Add aggregate fields
 	for _, agg := range as.aggregates {
+		if as.pivoted() {
+			pivotFields, pivotArgs, err := as.buildPivot(d, agg, buckets, paramOffset+len(args))
+			if err != nil {
+				return "", nil, err
+			}
+			fields = append(fields, pivotFields...)
+			args = append(args, pivotArgs...)
+			continue
+		}
+		if as.comparing() {
+			comparisonFields, comparisonArgs, err := as.buildComparison(d, agg, paramOffset+len(args))
+			if err != nil {
+				return "", nil, err
+			}
+			fields = append(fields, comparisonFields...)
+			args = append(args, comparisonArgs...)
+			continue
+		}
+
+		expression, err := agg.Expression(d)
+		if err != nil {
+			return "", nil, err
+		}
 		if agg.Alias != "" {
-			fields = append(fields, fmt.Sprintf("%s(%s) AS %s",
-				agg.Function, agg.Field, agg.Alias))
+			alias, err := identifier.QuoteAlias(d, agg.Alias)
+			if err != nil {
+				return "", nil, err
+			}
+			fields = append(fields, fmt.Sprintf("%s AS %s", expression, alias))
 		} else {
-			fields = append(fields, fmt.Sprintf("%s(%s)",
-				agg.Function, agg.Field))
+			fields = append(fields, expression)
 		}
 	}
 
 	if len(fields) == 0 {
-		return "", fmt.Errorf("no fields specified for select")
+		return "", nil, fmt.Errorf("no fields specified for select")
+	}
+
+	return fmt.Sprintf("SELECT %s", strings.Join(fields, ", ")), args, nil
+}
+
+// buildComparison renders the current_ and comparison_ columns of an aggregate
+func (as *AggregateSelect) buildComparison(d dialect.Dialect, agg AggregateField, paramOffset int) ([]string, []interface{}, error) {
+	if agg.Alias == "" {
+		return nil, nil, fmt.Errorf("aggregate %s(%s) needs an alias to be compared", agg.Function, agg.Field)
 	}
 
-	return fmt.Sprintf("SELECT %s", strings.Join(fields, ", ")), nil
+	var fields []string
+	var args []interface{}
+	for _, column := range []struct {
+		prefix string
+		filter Condition
+	}{
+		{CurrentPrefix, as.current},
+		{ComparisonPrefix, as.comparison},
+	} {
+		expression, filterArgs, err := agg.FilteredExpression(d, column.filter, paramOffset+len(args))
+		if err != nil {
+			return nil, nil, err
+		}
+		alias, err := identifier.QuoteAlias(d, column.prefix+agg.Alias)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, fmt.Sprintf("%s AS %s", expression, alias))
+		args = append(args, filterArgs...)
+	}
+	return fields, args, nil
+}
+
+// buildLabel renders the period label column, CASE WHEN <bucket> THEN <key> ... END
+func (as *AggregateSelect) buildLabel(d dialect.Dialect, buckets []Bucket, paramOffset int) (string, []interface{}, error) {
+	if !d.Features().GroupByPosition {
+		return "", nil, fmt.Errorf("period labels are grouped by position, which %s does not support", d.Name())
+	}
+	alias, err := identifier.QuoteAlias(d, as.labelAlias)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var label strings.Builder
+	var args []interface{}
+	label.WriteString("CASE")
+	for _, bucket := range buckets {
+		condition, conditionArgs, err := bucket.Filter.Build(d, paramOffset+len(args))
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, conditionArgs...)
+		args = append(args, bucket.Key)
+		fmt.Fprintf(&label, " WHEN %s THEN %s", condition, d.Placeholder(paramOffset+len(args)-1))
+	}
+	fmt.Fprintf(&label, " END AS %s", alias)
+	return label.String(), args, nil
+}
+
+// buildPivot renders one column of an aggregate per bucket
+func (as *AggregateSelect) buildPivot(d dialect.Dialect, agg AggregateField, buckets []Bucket, paramOffset int) ([]string, []interface{}, error) {
+	if agg.Alias == "" && len(as.aggregates) > 1 {
+		return nil, nil, fmt.Errorf("aggregate %s(%s) needs an alias to be pivoted next to other aggregates", agg.Function, agg.Field)
+	}
+
+	var fields []string
+	var args []interface{}
+	for _, bucket := range buckets {
+		expression, filterArgs, err := agg.FilteredExpression(d, bucket.Filter, paramOffset+len(args))
+		if err != nil {
+			return nil, nil, err
+		}
+		alias, err := identifier.QuoteAlias(d, pivotAlias(agg, bucket))
+		if err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, fmt.Sprintf("%s AS %s", expression, alias))
+		args = append(args, filterArgs...)
+	}
+	return fields, args, nil
 }
@@ -1,6 +1,9 @@
 package aggregate
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,7 +32,7 @@ func TestAggregateSelect_Build(t *testing.T) {
 			}{
 				{Sum, "amount", "total_amount"},
 			},
-			expectedSQL: "SELECT SUM(amount) AS total_amount",
+			expectedSQL: `SELECT SUM("amount") AS "total_amount"`,
 			description: "Should generate SQL with a single aggregate function",
 		},
 		{
@@ -43,13 +46,13 @@ func TestAggregateSelect_Build(t *testing.T) {
 				{Avg, "price", "avg_price"},
 				{Count, "id", "count"},
 			},
-			expectedSQL: "SELECT SUM(amount) AS total_amount, AVG(price) AS avg_price, COUNT(id) AS count",
+			expectedSQL: `SELECT SUM("amount") AS "total_amount", AVG("price") AS "avg_price", COUNT("id") AS "count"`,
 			description: "Should generate SQL with multiple aggregate functions",
 		},
 		{
 			name:          "Regular Fields Only",
 			regularFields: []string{"id", "name", "date"},
-			expectedSQL:   "SELECT id, name, date",
+			expectedSQL:   `SELECT "id", "name", "date"`,
 			description:   "Should generate SQL with only regular fields",
 		},
 		{
@@ -63,7 +66,7 @@ func TestAggregateSelect_Build(t *testing.T) {
 				{Sum, "amount", "total"},
 				{Count, "*", "count"},
 			},
-			expectedSQL: "SELECT date, category, SUM(amount) AS total, COUNT(*) AS count",
+			expectedSQL: `SELECT "date", "category", SUM("amount") AS "total", COUNT(*) AS "count"`,
 			description: "Should generate SQL with both regular and aggregate fields",
 		},
 		{
@@ -76,7 +79,7 @@ func TestAggregateSelect_Build(t *testing.T) {
 				{Min, "price", ""},
 				{Max, "price", ""},
 			},
-			expectedSQL: "SELECT MIN(price), MAX(price)",
+			expectedSQL: `SELECT MIN("price"), MAX("price")`,
 			description: "Should generate SQL with aggregate functions without aliases",
 		},
 		{
@@ -97,9 +100,63 @@ func TestAggregateSelect_Build(t *testing.T) {
 				{Max, "amount", "max"},
 				{Min, "amount", "min"},
 			},
-			expectedSQL: "SELECT SUM(amount) AS sum, AVG(amount) AS avg, COUNT(id) AS count, MAX(amount) AS max, MIN(amount) AS min",
+			expectedSQL: `SELECT SUM("amount") AS "sum", AVG("amount") AS "avg", COUNT("id") AS "count", MAX("amount") AS "max", MIN("amount") AS "min"`,
 			description: "Should handle all supported aggregate functions",
 		},
+		{
+			name:          "Invalid Regular Field",
+			regularFields: []string{"id; DROP TABLE users"},
+			expectedError: true,
+			description:   "Should reject regular fields that are not valid identifiers",
+		},
+		{
+			name: "Invalid Aggregate Field",
+			aggregates: []struct {
+				function AggregateFunction
+				field    string
+				alias    string
+			}{
+				{Sum, "amount) FROM users --", "total"},
+			},
+			expectedError: true,
+			description:   "Should reject aggregate fields that are not valid identifiers",
+		},
+		{
+			name: "Invalid Alias",
+			aggregates: []struct {
+				function AggregateFunction
+				field    string
+				alias    string
+			}{
+				{Sum, "amount", "total amount"},
+			},
+			expectedError: true,
+			description:   "Should reject aliases that are not valid identifiers",
+		},
+		{
+			name: "Star Outside Count",
+			aggregates: []struct {
+				function AggregateFunction
+				field    string
+				alias    string
+			}{
+				{Sum, "*", "total"},
+			},
+			expectedError: true,
+			description:   "Should only allow * with COUNT",
+		},
+		{
+			name: "Invalid Aggregate Function",
+			aggregates: []struct {
+				function AggregateFunction
+				field    string
+				alias    string
+			}{
+				{"PG_SLEEP", "amount", "total"},
+			},
+			expectedError: true,
+			description:   "Should reject unknown aggregate functions",
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,7 +177,7 @@ func TestAggregateSelect_Build(t *testing.T) {
 			}
 
 			// Build the SQL
-			sql, err := as.Build()
+			sql, _, err := as.Build(dialect.Postgres, 1)
 
 			if tt.expectedError {
 				assert.Error(t, err, "Expected an error but got none")
@@ -141,9 +198,9 @@ func TestAggregateSelect_Chaining(t *testing.T) {
 			AddAggregate(Count, "*", "count").
 			AddRegularField("category")
 
-		sql, err := as.Build()
+		sql, _, err := as.Build(dialect.Postgres, 1)
 		require.NoError(t, err)
-		assert.Equal(t, "SELECT date, category, SUM(amount) AS total, COUNT(*) AS count", sql)
+		assert.Equal(t, `SELECT "date", "category", SUM("amount") AS "total", COUNT(*) AS "count"`, sql)
 	})
 }
 
@@ -155,3 +212,230 @@ func TestNewAggregateSelect(t *testing.T) {
 		assert.Empty(t, as.aggregates)
 	})
 }
+
+func TestAggregateSelect_GroupBy(t *testing.T) {
+	t.Run("Group By Fields", func(t *testing.T) {
+		as := NewAggregateSelect().
+			AddRegularField("account_type").
+			AddRegularField("department").
+			AddAggregate(Sum, "amount", "total_amount")
+
+		assert.Equal(t, identifier.Names("account_type", "department"), as.GroupByFields())
+		assert.False(t, as.AutoGroupBy())
+		assert.True(t, as.WithAutoGroupBy().AutoGroupBy())
+	})
+
+	tests := []struct {
+		name          string
+		regularFields []string
+		withAggregate bool
+		groupBy       []string
+		expectedError string
+		description   string
+	}{
+		{
+			name:          "All regular fields grouped",
+			regularFields: []string{"account_type", "department"},
+			withAggregate: true,
+			groupBy:       []string{"department", "account_type"},
+			description:   "Should accept a GROUP BY covering every regular field in any order",
+		},
+		{
+			name:          "Missing regular field",
+			regularFields: []string{"account_type", "department"},
+			withAggregate: true,
+			groupBy:       []string{"account_type"},
+			expectedError: "field department must appear in GROUP BY",
+			description:   "Should reject a non-aggregated field missing from GROUP BY",
+		},
+		{
+			name:          "Aggregate without GROUP BY",
+			regularFields: []string{"department"},
+			withAggregate: true,
+			expectedError: "field department must appear in GROUP BY",
+			description:   "Should reject regular fields mixed with aggregates and no GROUP BY",
+		},
+		{
+			name:          "Only aggregates",
+			withAggregate: true,
+			description:   "Should accept aggregates without any regular field",
+		},
+		{
+			name:          "Only regular fields",
+			regularFields: []string{"department"},
+			description:   "Should accept plain fields when nothing is aggregated or grouped",
+		},
+		{
+			name:          "Extra GROUP BY field",
+			regularFields: []string{"department"},
+			withAggregate: true,
+			groupBy:       []string{"department", "region"},
+			description:   "Should allow grouping by fields that are not selected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log(tt.description)
+			as := NewAggregateSelect()
+			for _, field := range tt.regularFields {
+				as.AddRegularField(field)
+			}
+			if tt.withAggregate {
+				as.AddAggregate(Sum, "amount", "total_amount")
+			}
+
+			err := as.ValidateGroupBy(identifier.Names(tt.groupBy...))
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAggregateSelect_AliasExpressions(t *testing.T) {
+	t.Run("Aliases Map To Expressions", func(t *testing.T) {
+		as := NewAggregateSelect().
+			AddRegularField("department").
+			AddAggregate(Sum, "amount", "total_amount").
+			AddAggregate(Count, "*", "").
+			AddAggregate(Avg, "profit_margin", "avg_margin")
+
+		aliases, err := as.AliasExpressions(dialect.Postgres)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"total_amount": `SUM("amount")`,
+			"avg_margin":   `AVG("profit_margin")`,
+		}, aliases)
+	})
+
+	t.Run("Expression", func(t *testing.T) {
+		agg := AggregateField{Function: Max, Field: "price", Alias: "max_price"}
+		expression, err := agg.Expression(dialect.Postgres)
+		require.NoError(t, err)
+		assert.Equal(t, `MAX("price")`, expression)
+	})
+}
+
+func TestAggregateSelect_Columns(t *testing.T) {
+	as := NewAggregateSelect().
+		AddRegularField("department").
+		AddAggregate(Sum, "amount", "total_amount").
+		AddAggregate(Count, "*", "")
+
+	assert.Equal(t, []string{"total_amount"}, as.Aliases())
+	assert.Equal(t, 3, as.ColumnCount())
+}
+
+func TestAggregateSelect_UnsafeRawField(t *testing.T) {
+	as := NewAggregateSelect().
+		AddUnsafeRawField("date_trunc('month', posted_at)").
+		AddAggregate(Sum, "amount", "total")
+
+	sql, _, err := as.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT date_trunc('month', posted_at), SUM("amount") AS "total"`, sql)
+	assert.Equal(t, []identifier.Expr{identifier.UnsafeRaw("date_trunc('month', posted_at)")}, as.GroupByFields())
+}
+
+type staticCondition string
+
+func (c staticCondition) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
+	return fmt.Sprintf("%s = %s", c, d.Placeholder(paramOffset)), []interface{}{string(c)}, nil
+}
+
+func TestAggregateSelect_Comparison(t *testing.T) {
+	as := NewAggregateSelect().
+		AddRegularField("department").
+		AddAggregate(Avg, "amount", "average").
+		WithComparison(staticCondition("now"), staticCondition("before"))
+
+	sql, args, err := as.Build(dialect.SQLServer, 3)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT [department], AVG(CASE WHEN now = @p3 THEN [amount] END) AS [current_average], `+
+		`AVG(CASE WHEN before = @p4 THEN [amount] END) AS [comparison_average]`, sql)
+	assert.Equal(t, []interface{}{"now", "before"}, args)
+	assert.Equal(t, []string{"current_average", "comparison_average"}, as.Aliases())
+	assert.Equal(t, 3, as.ColumnCount())
+
+	aliases, err := as.AliasExpressions(dialect.SQLServer)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+}
+
+type staticBuckets []Bucket
+
+func (b staticBuckets) Buckets() ([]Bucket, error) {
+	return b, nil
+}
+
+var quarters = staticBuckets{
+	{Key: "2024-Q1", Alias: "q1", Filter: staticCondition("q1")},
+	{Key: "2024-Q2", Alias: "q2", Filter: staticCondition("q2")},
+}
+
+func TestAggregateSelect_Pivot(t *testing.T) {
+	as := NewAggregateSelect().
+		AddRegularField("department").
+		AddAggregate(Sum, "amount", "total").
+		AddAggregate(Count, "*", "orders").
+		WithPivot(quarters)
+
+	sql, args, err := as.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "department", `+
+		`SUM("amount") FILTER (WHERE q1 = $1) AS "total_q1", SUM("amount") FILTER (WHERE q2 = $2) AS "total_q2", `+
+		`COUNT(*) FILTER (WHERE q1 = $3) AS "orders_q1", COUNT(*) FILTER (WHERE q2 = $4) AS "orders_q2"`, sql)
+	assert.Equal(t, []interface{}{"q1", "q2", "q1", "q2"}, args)
+	assert.Equal(t, []string{"total_q1", "total_q2", "orders_q1", "orders_q2"}, as.Aliases())
+	assert.Equal(t, 5, as.ColumnCount())
+
+	aliases, err := as.AliasExpressions(dialect.Postgres)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+}
+
+func TestAggregateSelect_PivotWithoutAlias(t *testing.T) {
+	as := NewAggregateSelect().AddAggregate(Sum, "amount", "").WithPivot(quarters)
+
+	sql, _, err := as.Build(dialect.Postgres, 1)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT SUM("amount") FILTER (WHERE q1 = $1) AS "q1", SUM("amount") FILTER (WHERE q2 = $2) AS "q2"`, sql)
+
+	_, _, err = as.AddAggregate(Count, "*", "orders").Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "aggregate SUM(amount) needs an alias to be pivoted next to other aggregates")
+}
+
+func TestAggregateSelect_PeriodLabel(t *testing.T) {
+	as := NewAggregateSelect().
+		AddRegularField("department").
+		AddAggregate(Sum, "amount", "total").
+		WithPeriodLabel("quarter", quarters)
+
+	sql, args, err := as.Build(dialect.MySQL, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `department`, CASE WHEN q1 = ? THEN ? WHEN q2 = ? THEN ? END AS `quarter`, SUM(`amount`) AS `total`", sql)
+	assert.Equal(t, []interface{}{"q1", "2024-Q1", "q2", "2024-Q2"}, args)
+	assert.Equal(t, []string{"quarter", "total"}, as.Aliases())
+	assert.Equal(t, 3, as.ColumnCount())
+	assert.Equal(t, []identifier.Expr{identifier.Name("department"), identifier.UnsafeRaw("2")}, as.GroupByFields())
+
+	assert.NoError(t, as.ValidateGroupBy(as.GroupByFields()))
+	assert.EqualError(t, as.ValidateGroupBy(identifier.Names("department")), "period label quarter is grouped by position, use AutoGroupBy")
+
+	_, _, err = as.Build(dialect.SQLServer, 1)
+	assert.EqualError(t, err, "period labels are grouped by position, which sqlserver does not support")
+}
+
+func TestAggregateSelect_BucketsAndComparison(t *testing.T) {
+	as := NewAggregateSelect().
+		AddAggregate(Sum, "amount", "total").
+		WithComparison(staticCondition("now"), staticCondition("before")).
+		WithPivot(quarters)
+
+	_, _, err := as.Build(dialect.Postgres, 1)
+	assert.EqualError(t, err, "period buckets cannot be combined with a period comparison")
+}
@@ -1,13 +1,15 @@
 package simpleselect
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
 	"fmt"
 	"strings"
 )
 
 // SimpleSelect implements basic SELECT clause
 type SimpleSelect struct {
-	fields []string
+	fields []identifier.Expr
 }
 
 func NewSimpleSelect(fields ...string) *SimpleSelect {
@@ -15,13 +17,103 @@ func NewSimpleSelect(fields ...string) *SimpleSelect {
 		fields = []string{"*"}
 	}
 	return &SimpleSelect{
-		fields: fields,
+		fields: identifier.Names(fields...),
 	}
 }
 
-func (s *SimpleSelect) Build() (string, error) {
+// AddUnsafeRaw appends raw select expressions such as COUNT(*) AS total.
+// They are emitted verbatim and must never come from user input.
+func (s *SimpleSelect) AddUnsafeRaw(expressions ...string) *SimpleSelect {
+	for _, expression := range expressions {
+		s.fields = append(s.fields, identifier.UnsafeRaw(expression))
+	}
+	return s
+}
+
+// splitAlias splits "field AS alias" into its parts
+func splitAlias(field string) (string, string, bool) {
+	lower := strings.ToLower(field)
+	if idx := strings.LastIndex(lower, " as "); idx >= 0 {
+		return field[:idx], field[idx+len(" as "):], true
+	}
+	return field, "", false
+}
+
+// Fields returns the selected expressions as written, including aliases
+func (s *SimpleSelect) Fields() []identifier.Expr {
+	return append([]identifier.Expr(nil), s.fields...)
+}
+
+// SourceFields returns the selected expressions without their aliases
+func (s *SimpleSelect) SourceFields() []identifier.Expr {
+	fields := make([]identifier.Expr, len(s.fields))
+	for i, field := range s.fields {
+		if !field.Raw {
+			name, _, _ := splitAlias(field.Text)
+			field = identifier.Name(name)
+		}
+		fields[i] = field
+	}
+	return fields
+}
+
+// Aliases returns the output aliases declared with "expr AS alias"
+func (s *SimpleSelect) Aliases() []string {
+	aliases := make([]string, 0)
+	for _, field := range s.fields {
+		if _, alias, ok := splitAlias(field.Text); ok {
+			aliases = append(aliases, strings.TrimSpace(alias))
+		}
+	}
+	return aliases
+}
+
+// ColumnCount returns the number of output columns, or 0 when it cannot be
+// known from the select list (e.g. SELECT * or table.*)
+func (s *SimpleSelect) ColumnCount() int {
+	for _, field := range s.fields {
+		text := strings.TrimSpace(field.Text)
+		if text == "*" || strings.HasSuffix(text, ".*") {
+			return 0
+		}
+	}
+	return len(s.fields)
+}
+
+// buildField quotes a field name and its optional alias, raw fields are kept as is
+func buildField(d dialect.Dialect, field identifier.Expr) (string, error) {
+	if field.Raw {
+		return field.Build(d)
+	}
+
+	name, alias, ok := splitAlias(field.Text)
+	quotedName, err := identifier.Quote(d, name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return quotedName, nil
+	}
+
+	quotedAlias, err := identifier.QuoteAlias(d, alias)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s AS %s", quotedName, quotedAlias), nil
+}
+
+func (s *SimpleSelect) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
 	if len(s.fields) == 0 {
-		return "", fmt.Errorf("no fields specified for select")
+		return "", nil, fmt.Errorf("no fields specified for select")
+	}
+
+	fields := make([]string, len(s.fields))
+	for i, field := range s.fields {
+		sql, err := buildField(d, field)
+		if err != nil {
+			return "", nil, err
+		}
+		fields[i] = sql
 	}
-	return fmt.Sprintf("SELECT %s", strings.Join(s.fields, ", ")), nil
+	return fmt.Sprintf("SELECT %s", strings.Join(fields, ", ")), nil, nil
 }
@@ -1,6 +1,8 @@
 package simpleselect
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/identifier"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,7 +51,7 @@ func TestNewSimpleSelect(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Log(tt.description)
 			select_ := NewSimpleSelect(tt.fields...)
-			assert.Equal(t, tt.expectedField, select_.fields,
+			assert.Equal(t, identifier.Names(tt.expectedField...), select_.fields,
 				"Fields should match expected values")
 		})
 	}
@@ -73,44 +75,49 @@ func TestSimpleSelectBuild(t *testing.T) {
 		{
 			name:        "Single field",
 			fields:      []string{"name"},
-			expected:    "SELECT name",
+			expected:    `SELECT "name"`,
 			expectError: false,
 			description: "Should build query with single field",
 		},
 		{
 			name:        "Multiple fields",
 			fields:      []string{"id", "name", "email"},
-			expected:    "SELECT id, name, email",
+			expected:    `SELECT "id", "name", "email"`,
 			expectError: false,
 			description: "Should build query with multiple fields",
 		},
 		{
 			name:        "Table qualified fields",
 			fields:      []string{"users.id", "users.name", "profiles.avatar"},
-			expected:    "SELECT users.id, users.name, profiles.avatar",
+			expected:    `SELECT "users"."id", "users"."name", "profiles"."avatar"`,
 			expectError: false,
 			description: "Should build query with table qualified fields",
 		},
 		{
 			name:        "Aliased fields",
 			fields:      []string{"id AS user_id", "name AS full_name"},
-			expected:    "SELECT id AS user_id, name AS full_name",
+			expected:    `SELECT "id" AS "user_id", "name" AS "full_name"`,
 			expectError: false,
 			description: "Should build query with aliased fields",
 		},
 		{
 			name:        "With functions",
 			fields:      []string{"COUNT(*)", "MAX(score)", "MIN(created_at)"},
-			expected:    "SELECT COUNT(*), MAX(score), MIN(created_at)",
-			expectError: false,
-			description: "Should build query with SQL functions",
+			expectError: true,
+			description: "Should reject SQL functions passed as field names",
 		},
 		{
 			name:        "Mixed field types",
 			fields:      []string{"users.id AS user_id", "COUNT(*) as total", "MAX(score) as high_score"},
-			expected:    "SELECT users.id AS user_id, COUNT(*) as total, MAX(score) as high_score",
+			expectError: true,
+			description: "Should reject expressions mixed with qualified names and aliases",
+		},
+		{
+			name:        "Lowercase alias keyword",
+			fields:      []string{"users.id as user_id"},
+			expected:    `SELECT "users"."id" AS "user_id"`,
 			expectError: false,
-			description: "Should build query with mixed field types including qualified names, aliases, and functions",
+			description: "Should accept a lowercase AS keyword",
 		},
 	}
 
@@ -118,7 +125,7 @@ func TestSimpleSelectBuild(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Log(tt.description)
 			select_ := NewSimpleSelect(tt.fields...)
-			result, err := select_.Build()
+			result, _, err := select_.Build(dialect.Postgres, 1)
 
 			if tt.expectError {
 				assert.Error(t, err, "Should return an error")
@@ -149,16 +156,26 @@ func TestSimpleSelectEdgeCases(t *testing.T) {
 		{
 			name:        "Fields with special characters",
 			fields:      []string{"`special.field`", "\"quoted.field\"", "[bracketed.field]"},
-			expected:    "SELECT `special.field`, \"quoted.field\", [bracketed.field]",
-			expectError: false,
-			description: "Should handle fields with special characters and different quote styles",
+			expectError: true,
+			description: "Should reject fields with special characters and foreign quote styles",
 		},
 		{
 			name:        "Fields with whitespace",
 			fields:      []string{"  id  ", " name ", "email  "},
-			expected:    "SELECT   id  ,  name , email  ",
-			expectError: false,
-			description: "Should preserve whitespace in field names",
+			expectError: true,
+			description: "Should reject whitespace around field names",
+		},
+		{
+			name:        "Injection attempt",
+			fields:      []string{"id FROM users; DROP TABLE users --"},
+			expectError: true,
+			description: "Should reject fields carrying additional SQL",
+		},
+		{
+			name:        "Injection through alias",
+			fields:      []string{"id AS x FROM users --"},
+			expectError: true,
+			description: "Should reject aliases carrying additional SQL",
 		},
 	}
 
@@ -166,7 +183,7 @@ func TestSimpleSelectEdgeCases(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Log(tt.description)
 			select_ := NewSimpleSelect(tt.fields...)
-			result, err := select_.Build()
+			result, _, err := select_.Build(dialect.Postgres, 1)
 
 			if tt.expectError {
 				assert.Error(t, err, "Should return an error")
@@ -178,3 +195,56 @@ func TestSimpleSelectEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestSimpleSelectColumns(t *testing.T) {
+	tests := []struct {
+		name            string
+		fields          []string
+		expectedAliases []string
+		expectedCount   int
+		description     string
+	}{
+		{
+			name:            "Select all",
+			fields:          []string{},
+			expectedAliases: []string{},
+			expectedCount:   0,
+			description:     "Should report unknown column count for SELECT *",
+		},
+		{
+			name:            "Qualified star",
+			fields:          []string{"id", "users.*"},
+			expectedAliases: []string{},
+			expectedCount:   0,
+			description:     "Should report unknown column count for table.*",
+		},
+		{
+			name:            "Aliased fields",
+			fields:          []string{"id AS user_id", "name", "COUNT(*) as total"},
+			expectedAliases: []string{"user_id", "total"},
+			expectedCount:   3,
+			description:     "Should collect aliases regardless of AS case",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Log(tt.description)
+			select_ := NewSimpleSelect(tt.fields...)
+			assert.Equal(t, tt.expectedAliases, select_.Aliases())
+			assert.Equal(t, tt.expectedCount, select_.ColumnCount())
+		})
+	}
+}
+
+func TestSimpleSelectUnsafeRaw(t *testing.T) {
+	t.Run("Raw expressions are emitted verbatim", func(t *testing.T) {
+		select_ := NewSimpleSelect("users.id AS user_id").
+			AddUnsafeRaw("COUNT(*) AS total", "MAX(score) as high_score")
+
+		result, _, err := select_.Build(dialect.Postgres, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, `SELECT "users"."id" AS "user_id", COUNT(*) AS total, MAX(score) as high_score`, result)
+		assert.Equal(t, []string{"user_id", "total", "high_score"}, select_.Aliases())
+	})
+}
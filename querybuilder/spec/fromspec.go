@@ -0,0 +1,292 @@
+package spec
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// FromSpec creates a Postgres query builder from a spec. Only the structure is
+// checked here, identifiers and operators are validated when the query is built.
+func FromSpec(spec *Spec) (querybuilder.QueryBuilder, error) {
+	return FromSpecDialect(spec, dialect.Postgres)
+}
+
+// FromSpecDialect creates a query builder for the given dialect from a spec
+func FromSpecDialect(spec *Spec, d dialect.Dialect) (querybuilder.QueryBuilder, error) {
+	if spec == nil {
+		return nil, errors.New("spec must not be nil")
+	}
+
+	b := sqlbuilder.New(d)
+
+	if spec.Select != nil {
+		applySelect(b, spec.Select)
+	}
+
+	if spec.From != nil {
+		if err := applyFrom(b, spec.From); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, groupSpec := range spec.Where {
+		group, err := toGroup(groupSpec, fmt.Sprintf("where[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		b.WhereGroup(group.Operator, func(wg *querybuilder.WhereGroup) {
+			wg.Conditions = group.Conditions
+		})
+	}
+
+	if len(spec.GroupBy) > 0 {
+		b.GroupBy(spec.GroupBy...)
+	}
+
+	for i, conditionSpec := range spec.Having {
+		condition, err := toCondition(conditionSpec, fmt.Sprintf("having[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		b.Having(condition)
+	}
+
+	for i, orderSpec := range spec.OrderBy {
+		key, err := toOrderKey(orderSpec, fmt.Sprintf("order_by[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		b.OrderBy(key)
+	}
+
+	if spec.Limit != nil {
+		b.Limit(*spec.Limit)
+	}
+	if spec.Offset != nil {
+		b.Offset(*spec.Offset)
+	}
+	return b, nil
+}
+
+func applySelect(b *sqlbuilder.Builder, selectSpec *SelectSpec) {
+	if len(selectSpec.Aggregates) == 0 && !selectSpec.AutoGroupBy {
+		b.Select(selectSpec.Fields...)
+		return
+	}
+
+	b.SelectAggregate()
+	for _, field := range selectSpec.Fields {
+		b.AddRegularField(field)
+	}
+	for _, agg := range selectSpec.Aggregates {
+		b.AddAggregate(agg.Function, agg.Field, agg.Alias)
+	}
+	if selectSpec.AutoGroupBy {
+		b.AutoGroupBy()
+	}
+}
+
+func applyFrom(b *sqlbuilder.Builder, fromSpec *TableSpec) error {
+	if fromSpec.Table == "" {
+		return errors.New("from: table is required")
+	}
+	if fromSpec.Alias == "" && len(fromSpec.Joins) == 0 {
+		b.From(fromSpec.Table)
+		return nil
+	}
+
+	b.FromAs(fromSpec.Table, fromSpec.Alias)
+	for i, join := range fromSpec.Joins {
+		path := fmt.Sprintf("from.joins[%d]", i)
+		if join.Table == "" {
+			return fmt.Errorf("%s: table is required", path)
+		}
+		if len(join.Using) > 0 {
+			if join.On != nil {
+				return fmt.Errorf("%s: on and using are mutually exclusive", path)
+			}
+			b.JoinUsing(join.Type, join.Table, join.Alias, join.Using...)
+			continue
+		}
+
+		var on querybuilder.QueryCondition
+		if join.On != nil {
+			var err error
+			on, err = toCondition(*join.On, path+".on")
+			if err != nil {
+				return err
+			}
+		}
+		b.Join(join.Type, join.Table, join.Alias, on)
+	}
+	return nil
+}
+
+func toGroup(groupSpec GroupSpec, path string) (*querybuilder.WhereGroup, error) {
+	operator := groupSpec.Operator
+	switch operator {
+	case "":
+		operator = querybuilder.AND
+	case querybuilder.AND, querybuilder.OR:
+	default:
+		return nil, fmt.Errorf("%s: invalid logical operator: %s", path, operator)
+	}
+
+	group := querybuilder.NewWhereGroup(operator)
+	for i, conditionSpec := range groupSpec.Conditions {
+		condition, err := toCondition(conditionSpec, fmt.Sprintf("%s.conditions[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		group.Add(condition)
+	}
+	return group, nil
+}
+
+func toCondition(conditionSpec ConditionSpec, path string) (querybuilder.QueryCondition, error) {
+	set := 0
+	for _, isSet := range []bool{
+		conditionSpec.Field != nil,
+		conditionSpec.Column != nil,
+		conditionSpec.DateRange != nil,
+		conditionSpec.Group != nil,
+		conditionSpec.And != nil,
+		conditionSpec.Or != nil,
+		conditionSpec.Not != nil,
+	} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("%s: exactly one of field, column, date_range, group, and, or, not must be set", path)
+	}
+
+	switch {
+	case conditionSpec.Field != nil:
+		f := conditionSpec.Field
+		condition := fields.NewFieldCondition(f.Field, f.Operator, f.Value).WithEmptyList(f.EmptyList)
+		if f.ArrayParameter {
+			value, err := typedList(f.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s.field: %w", path, err)
+			}
+			condition.Value = value
+			condition.WithArrayParameter()
+		}
+		return condition, nil
+	case conditionSpec.Column != nil:
+		c := conditionSpec.Column
+		return fields.NewColumnCondition(c.Left, c.Operator, c.Right), nil
+	case conditionSpec.DateRange != nil:
+		condition, err := daterange.NewValidatedDateRangeCondition(*conditionSpec.DateRange)
+		if err != nil {
+			return nil, fmt.Errorf("%s.date_range: %w", path, err)
+		}
+		return condition, nil
+	case conditionSpec.And != nil:
+		conditions, err := toConditions(conditionSpec.And, path+".and")
+		if err != nil {
+			return nil, err
+		}
+		return querybuilder.And(conditions...), nil
+	case conditionSpec.Or != nil:
+		conditions, err := toConditions(conditionSpec.Or, path+".or")
+		if err != nil {
+			return nil, err
+		}
+		return querybuilder.Or(conditions...), nil
+	case conditionSpec.Not != nil:
+		condition, err := toCondition(*conditionSpec.Not, path+".not")
+		if err != nil {
+			return nil, err
+		}
+		return querybuilder.Not(condition), nil
+	default:
+		return toGroup(*conditionSpec.Group, path+".group")
+	}
+}
+
+func toConditions(conditionSpecs []ConditionSpec, path string) ([]querybuilder.QueryCondition, error) {
+	conditions := make([]querybuilder.QueryCondition, 0, len(conditionSpecs))
+	for i, conditionSpec := range conditionSpecs {
+		condition, err := toCondition(conditionSpec, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// typedList converts a decoded list, which is always []interface{}, to a slice
+// of its element type so the driver can bind it as one array parameter. JSON
+// decodes every number as float64, so whole numbers become []int64 to match
+// integer columns.
+func typedList(value interface{}) (interface{}, error) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return value, nil
+	}
+	elementType := reflect.TypeOf(values[0])
+	if elementType == nil {
+		return nil, errors.New("array parameter values must not be null")
+	}
+	list := reflect.MakeSlice(reflect.SliceOf(elementType), len(values), len(values))
+	for i, element := range values {
+		if reflect.TypeOf(element) != elementType {
+			return nil, errors.New("array parameter values must all have the same type")
+		}
+		list.Index(i).Set(reflect.ValueOf(element))
+	}
+	if floats, ok := list.Interface().([]float64); ok {
+		if ints, ok := integerList(floats); ok {
+			return ints, nil
+		}
+	}
+	return list.Interface(), nil
+}
+
+// integerList converts floats to int64 when every value is a whole number
+func integerList(floats []float64) ([]int64, bool) {
+	ints := make([]int64, len(floats))
+	for i, f := range floats {
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return nil, false
+		}
+		ints[i] = int64(f)
+	}
+	return ints, true
+}
+
+func toOrderKey(orderSpec OrderSpec, path string) (simpleorderby.OrderKey, error) {
+	var key simpleorderby.OrderKey
+	set := 0
+	if orderSpec.Field != "" {
+		key = simpleorderby.Field(orderSpec.Field)
+		set++
+	}
+	if orderSpec.Alias != "" {
+		key = simpleorderby.Alias(orderSpec.Alias)
+		set++
+	}
+	if orderSpec.Position != 0 {
+		key = simpleorderby.Ordinal(orderSpec.Position)
+		set++
+	}
+	if set != 1 {
+		return simpleorderby.OrderKey{}, fmt.Errorf("%s: exactly one of field, alias or position must be set", path)
+	}
+
+	key.Direction = orderSpec.Direction
+	key.Nulls = orderSpec.Nulls
+	return key, nil
+}
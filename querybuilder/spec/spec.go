@@ -0,0 +1,104 @@
+package spec
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"errors"
+)
+
+// ErrUnsafeRaw is returned by ToSpec when the builder holds raw SQL. Specs are
+// meant to be stored and edited by users, so raw expressions are never part of them.
+var ErrUnsafeRaw = errors.New("raw SQL expressions cannot be stored in a spec")
+
+// Spec is a serializable description of a query, e.g. a saved report.
+// It can be decoded from JSON or YAML and turned into a builder with FromSpec.
+type Spec struct {
+	Select  *SelectSpec     `json:"select,omitempty" yaml:"select,omitempty"`
+	From    *TableSpec      `json:"from,omitempty" yaml:"from,omitempty"`
+	Where   []GroupSpec     `json:"where,omitempty" yaml:"where,omitempty"`
+	GroupBy []string        `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	Having  []ConditionSpec `json:"having,omitempty" yaml:"having,omitempty"`
+	OrderBy []OrderSpec     `json:"order_by,omitempty" yaml:"order_by,omitempty"`
+	Limit   *int            `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Offset  *int            `json:"offset,omitempty" yaml:"offset,omitempty"`
+}
+
+// SelectSpec describes the select list. It becomes an aggregate select when
+// aggregates are present or auto_group_by is set, a plain select otherwise.
+type SelectSpec struct {
+	Fields      []string        `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Aggregates  []AggregateSpec `json:"aggregates,omitempty" yaml:"aggregates,omitempty"`
+	AutoGroupBy bool            `json:"auto_group_by,omitempty" yaml:"auto_group_by,omitempty"`
+}
+
+// AggregateSpec describes an aggregate such as SUM(amount) AS total_amount
+type AggregateSpec struct {
+	Function aggregate.AggregateFunction `json:"function" yaml:"function"`
+	Field    string                      `json:"field" yaml:"field"`
+	Alias    string                      `json:"alias,omitempty" yaml:"alias,omitempty"`
+}
+
+// TableSpec describes the FROM table and its joins
+type TableSpec struct {
+	Table string     `json:"table" yaml:"table"`
+	Alias string     `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Joins []JoinSpec `json:"joins,omitempty" yaml:"joins,omitempty"`
+}
+
+// JoinSpec describes a joined table, matched either ON a condition or USING columns
+type JoinSpec struct {
+	Type  querybuilder.JoinType `json:"type" yaml:"type"`
+	Table string                `json:"table" yaml:"table"`
+	Alias string                `json:"alias,omitempty" yaml:"alias,omitempty"`
+	On    *ConditionSpec        `json:"on,omitempty" yaml:"on,omitempty"`
+	Using []string              `json:"using,omitempty" yaml:"using,omitempty"`
+}
+
+// GroupSpec describes a group of conditions. At the top level of where the
+// operator also joins the group to the previous one, like WhereGroup.
+type GroupSpec struct {
+	Operator   querybuilder.LogicalOperator `json:"operator,omitempty" yaml:"operator,omitempty"` // Defaults to AND
+	Conditions []ConditionSpec              `json:"conditions" yaml:"conditions"`
+}
+
+// ConditionSpec holds exactly one kind of condition. And, or and not nest to
+// any depth, e.g. {"and": [a, {"or": [b, {"not": c}]}]}.
+type ConditionSpec struct {
+	Field     *FieldSpec            `json:"field,omitempty" yaml:"field,omitempty"`
+	Column    *ColumnSpec           `json:"column,omitempty" yaml:"column,omitempty"`
+	DateRange *daterange.DateConfig `json:"date_range,omitempty" yaml:"date_range,omitempty"`
+	Group     *GroupSpec            `json:"group,omitempty" yaml:"group,omitempty"`
+	And       []ConditionSpec       `json:"and,omitempty" yaml:"and,omitempty"`
+	Or        []ConditionSpec       `json:"or,omitempty" yaml:"or,omitempty"`
+	Not       *ConditionSpec        `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// FieldSpec describes a FieldCondition; IN and NOT IN take a list value,
+// empty_list sets what an empty list builds, e.g. IGNORE for an empty filter,
+// and array_parameter binds the list as one array, see WithArrayParameter
+type FieldSpec struct {
+	Field          string                    `json:"field" yaml:"field"`
+	Operator       fields.ComparisonOperator `json:"operator" yaml:"operator"`
+	Value          interface{}               `json:"value,omitempty" yaml:"value,omitempty"`
+	EmptyList      fields.EmptyListPolicy    `json:"empty_list,omitempty" yaml:"empty_list,omitempty"`
+	ArrayParameter bool                      `json:"array_parameter,omitempty" yaml:"array_parameter,omitempty"`
+}
+
+// ColumnSpec describes a ColumnCondition comparing two columns
+type ColumnSpec struct {
+	Left     string                    `json:"left" yaml:"left"`
+	Operator fields.ComparisonOperator `json:"operator" yaml:"operator"`
+	Right    string                    `json:"right" yaml:"right"`
+}
+
+// OrderSpec describes an ORDER BY key; exactly one of field, alias or position is set
+type OrderSpec struct {
+	Field     string                   `json:"field,omitempty" yaml:"field,omitempty"`
+	Alias     string                   `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Position  int                      `json:"position,omitempty" yaml:"position,omitempty"`
+	Direction simpleorderby.Direction  `json:"direction,omitempty" yaml:"direction,omitempty"`
+	Nulls     simpleorderby.NullsOrder `json:"nulls,omitempty" yaml:"nulls,omitempty"`
+}
@@ -0,0 +1,441 @@
+package spec
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	pgbuilder "dynamic-sqlbuilder/querybuilder/pgBuilder"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const reportJSON = `{
+	"select": {
+		"fields": ["coa.coadescription"],
+		"aggregates": [{"function": "SUM", "field": "t.amount", "alias": "total_amount"}],
+		"auto_group_by": true
+	},
+	"from": {
+		"table": "transactions",
+		"alias": "t",
+		"joins": [{
+			"type": "LEFT JOIN",
+			"table": "chart_of_accounts",
+			"alias": "coa",
+			"on": {"column": {"left": "t.account_code", "operator": "=", "right": "coa.account_code"}}
+		}]
+	},
+	"where": [
+		{"conditions": [
+			{"field": {"field": "t.department", "operator": "IN", "value": ["FIN", "OPS"]}},
+			{"date_range": {"type": "SPECIFIC_MONTH", "parameters": {"month": 3, "year": 2024}}}
+		]},
+		{"operator": "OR", "conditions": [
+			{"group": {"operator": "AND", "conditions": [
+				{"field": {"field": "t.is_active", "operator": "=", "value": true}},
+				{"field": {"field": "t.deleted_at", "operator": "IS NULL"}}
+			]}}
+		]}
+	],
+	"having": [
+		{"field": {"field": "total_amount", "operator": ">", "value": 1000}}
+	],
+	"order_by": [
+		{"alias": "total_amount", "direction": "DESC", "nulls": "NULLS LAST"},
+		{"position": 1}
+	],
+	"limit": 50,
+	"offset": 100
+}`
+
+const reportYAML = `
+select:
+  fields: [coa.coadescription]
+  aggregates:
+    - {function: SUM, field: t.amount, alias: total_amount}
+  auto_group_by: true
+from:
+  table: transactions
+  alias: t
+  joins:
+    - type: LEFT JOIN
+      table: chart_of_accounts
+      alias: coa
+      on:
+        column: {left: t.account_code, operator: "=", right: coa.account_code}
+where:
+  - conditions:
+      - field: {field: t.department, operator: IN, value: [FIN, OPS]}
+      - date_range: {type: SPECIFIC_MONTH, parameters: {month: 3, year: 2024}}
+  - operator: OR
+    conditions:
+      - group:
+          operator: AND
+          conditions:
+            - field: {field: t.is_active, operator: "=", value: true}
+            - field: {field: t.deleted_at, operator: IS NULL}
+having:
+  - field: {field: total_amount, operator: ">", value: 1000}
+order_by:
+  - {alias: total_amount, direction: DESC, nulls: NULLS LAST}
+  - {position: 1}
+limit: 50
+offset: 100
+`
+
+const reportSQL = `SELECT "coa"."coadescription", SUM("t"."amount") AS "total_amount" ` +
+	`FROM "transactions" AS "t" LEFT JOIN "chart_of_accounts" AS "coa" ON "t"."account_code" = "coa"."account_code" ` +
+	`WHERE ("t"."department" IN ($1,$2) AND "period_year" = $3 AND "period_month" BETWEEN $4 AND $5) OR ("t"."is_active" = $6 AND "t"."deleted_at" IS NULL) ` +
+	`GROUP BY "coa"."coadescription" HAVING SUM("t"."amount") > $7 ` +
+	`ORDER BY "total_amount" DESC NULLS LAST, 1 LIMIT $8 OFFSET $9`
+
+func TestFromSpec(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func(*Spec) error
+		args   []interface{}
+	}{
+		{
+			name:   "JSON",
+			decode: func(s *Spec) error { return json.Unmarshal([]byte(reportJSON), s) },
+			args:   []interface{}{"FIN", "OPS", 2024, 3, 3, true, float64(1000), 50, 100},
+		},
+		{
+			name:   "YAML",
+			decode: func(s *Spec) error { return yaml.Unmarshal([]byte(reportYAML), s) },
+			args:   []interface{}{"FIN", "OPS", 2024, 3, 3, true, 1000, 50, 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec Spec
+			require.NoError(t, tt.decode(&spec))
+
+			builder, err := FromSpec(&spec)
+			require.NoError(t, err)
+
+			sql, args, err := builder.Build()
+			require.NoError(t, err)
+			assert.Equal(t, reportSQL, sql)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestFromSpec_PeriodExpression(t *testing.T) {
+	var spec Spec
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"from": {"table": "transactions"},
+		"where": [{"conditions": [{"date_range": "Q1 2024"}]}]
+	}`), &spec))
+
+	builder, err := FromSpec(&spec)
+	require.NoError(t, err)
+
+	sql, args, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "transactions" WHERE "period_year" = $1 AND "period_month" BETWEEN $2 AND $3`, sql)
+	assert.Equal(t, []interface{}{2024, 1, 3}, args)
+}
+
+func TestFromSpec_Combinators(t *testing.T) {
+	var spec Spec
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"from": {"table": "transactions"},
+		"where": [{"operator": "AND", "conditions": [{"and": [
+			{"field": {"field": "department", "operator": "=", "value": "FIN"}},
+			{"or": [
+				{"field": {"field": "amount", "operator": ">", "value": 100}},
+				{"not": {"field": {"field": "is_active", "operator": "=", "value": true}}}
+			]}
+		]}]}]
+	}`), &spec))
+
+	builder, err := FromSpec(&spec)
+	require.NoError(t, err)
+
+	sql, args, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "transactions" WHERE "department" = $1 AND ("amount" > $2 OR NOT ("is_active" = $3))`, sql)
+	assert.Equal(t, []interface{}{"FIN", float64(100), true}, args)
+
+	stored, err := ToSpec(builder)
+	require.NoError(t, err)
+	assert.Equal(t, spec.Where, stored.Where)
+}
+
+func TestFromSpec_EmptyList(t *testing.T) {
+	var spec Spec
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"from": {"table": "transactions"},
+		"where": [{"conditions": [
+			{"field": {"field": "department", "operator": "IN", "value": [], "empty_list": "IGNORE"}},
+			{"field": {"field": "account_code", "operator": "IN", "value": [], "empty_list": "MATCH_NONE"}},
+			{"field": {"field": "is_active", "operator": "=", "value": true}}
+		]}]
+	}`), &spec))
+
+	builder, err := FromSpec(&spec)
+	require.NoError(t, err)
+
+	sql, args, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "transactions" WHERE (1 = 0 AND "is_active" = $1)`, sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	stored, err := ToSpec(builder)
+	require.NoError(t, err)
+	assert.Equal(t, fields.EmptyListIgnore, stored.Where[0].Conditions[0].Field.EmptyList)
+}
+
+func TestFromSpec_ArrayParameter(t *testing.T) {
+	var spec Spec
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"from": {"table": "transactions"},
+		"where": [{"operator": "AND", "conditions": [
+			{"field": {"field": "account_code", "operator": "IN", "value": ["1001", "1002"], "array_parameter": true}}
+		]}]
+	}`), &spec))
+
+	builder, err := FromSpec(&spec)
+	require.NoError(t, err)
+
+	sql, args, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "transactions" WHERE "account_code" = ANY($1)`, sql)
+	assert.Equal(t, []interface{}{[]string{"1001", "1002"}}, args)
+
+	stored, err := ToSpec(builder)
+	require.NoError(t, err)
+	assert.True(t, stored.Where[0].Conditions[0].Field.ArrayParameter)
+
+	data, err := json.Marshal(stored)
+	require.NoError(t, err)
+	var decoded Spec
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	rebuilt, err := FromSpec(&decoded)
+	require.NoError(t, err)
+	rebuiltSQL, rebuiltArgs, err := rebuilt.Build()
+	require.NoError(t, err)
+	assert.Equal(t, sql, rebuiltSQL)
+	assert.Equal(t, args, rebuiltArgs)
+
+	spec.Where[0].Conditions[0].Field.Value = []interface{}{"1001", float64(1002)}
+	_, err = FromSpec(&spec)
+	assert.EqualError(t, err, "where[0].conditions[0].field: array parameter values must all have the same type")
+}
+
+func TestFromSpec_IntegerArrayParameter(t *testing.T) {
+	original := pgbuilder.NewPostgresQueryBuilder().
+		From("users").
+		Where(fields.NewFieldCondition("id", fields.In, []int64{3, 5, 8}).WithArrayParameter())
+
+	expectedSQL, expectedArgs, err := original.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "users" WHERE "id" = ANY($1)`, expectedSQL)
+
+	spec, err := ToSpec(original)
+	require.NoError(t, err)
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	var decoded Spec
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	builder, err := FromSpec(&decoded)
+	require.NoError(t, err)
+
+	sql, args, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, expectedArgs, args)
+
+	decoded.Where[0].Conditions[0].Field.Value = []interface{}{float64(1), 2.5}
+	builder, err = FromSpec(&decoded)
+	require.NoError(t, err)
+	_, args, err = builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{[]float64{1, 2.5}}, args)
+}
+
+func TestToSpec_RoundTrip(t *testing.T) {
+	original := pgbuilder.NewPostgresQueryBuilder().SelectAggregate().
+		AddRegularField("department").
+		AddAggregate(aggregate.Count, "*", "row_count").
+		From("transactions").
+		WhereGroup(querybuilder.OR, func(wg *querybuilder.WhereGroup) {
+			wg.Add(fields.NewFieldCondition("department", fields.Equals, "FIN"))
+			wg.Add(fields.NewFieldCondition("department", fields.Equals, "OPS"))
+		}).
+		GroupBy("department").
+		HavingGroup(querybuilder.OR, func(wg *querybuilder.WhereGroup) {
+			wg.Add(fields.NewFieldCondition("row_count", fields.GreaterThan, 10))
+			wg.Add(fields.NewFieldCondition("row_count", fields.LessThan, 2))
+		}).
+		OrderBy(simpleorderby.Field("department").Asc()).
+		Limit(10)
+
+	expectedSQL, expectedArgs, err := original.Build()
+	require.NoError(t, err)
+
+	spec, err := ToSpec(original)
+	require.NoError(t, err)
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(spec)
+		require.NoError(t, err)
+
+		var decoded Spec
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		builder, err := FromSpec(&decoded)
+		require.NoError(t, err)
+
+		sql, args, err := builder.Build()
+		require.NoError(t, err)
+		assert.Equal(t, expectedSQL, sql)
+		assert.Equal(t, []interface{}{"FIN", "OPS", float64(10), float64(2), 10}, args)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		data, err := yaml.Marshal(spec)
+		require.NoError(t, err)
+
+		var decoded Spec
+		require.NoError(t, yaml.Unmarshal(data, &decoded))
+		builder, err := FromSpec(&decoded)
+		require.NoError(t, err)
+
+		sql, args, err := builder.Build()
+		require.NoError(t, err)
+		assert.Equal(t, expectedSQL, sql)
+		assert.Equal(t, expectedArgs, args)
+	})
+}
+
+func TestToSpec_UnsafeRaw(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder querybuilder.QueryBuilder
+	}{
+		{
+			name:    "Raw select",
+			builder: pgbuilder.NewPostgresQueryBuilder().SelectUnsafeRaw("COUNT(*) AS total").From("transactions"),
+		},
+		{
+			name: "Raw condition",
+			builder: pgbuilder.NewPostgresQueryBuilder().From("transactions").
+				Where(fields.NewUnsafeRawCondition("LOWER(name)", fields.Equals, "x")),
+		},
+		{
+			name:    "Raw order key",
+			builder: pgbuilder.NewPostgresQueryBuilder().From("transactions").OrderBy(simpleorderby.UnsafeRaw("random()")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToSpec(tt.builder)
+			assert.True(t, errors.Is(err, ErrUnsafeRaw), "expected ErrUnsafeRaw, got %v", err)
+		})
+	}
+}
+
+func TestToSpec_Comparison(t *testing.T) {
+	b := pgbuilder.NewPostgresQueryBuilder()
+	b.SelectAggregate().AddAggregate(aggregate.Sum, "amount", "total").From("transactions")
+	b.ComparePeriods(daterange.NewDateRangeCondition(daterange.DateConfig{Type: daterange.YTD}),
+		daterange.Comparison{Type: daterange.SamePeriodLastYear})
+
+	_, err := ToSpec(b)
+	assert.ErrorContains(t, err, "period comparisons cannot be stored in a spec")
+}
+
+func TestToSpec_Buckets(t *testing.T) {
+	b := pgbuilder.NewPostgresQueryBuilder()
+	b.SelectAggregate().AddAggregate(aggregate.Sum, "amount", "total").From("transactions")
+	b.PivotPeriods(daterange.NewDateRangeCondition(daterange.DateConfig{Type: daterange.YTD}), daterange.MonthGranularity)
+
+	_, err := ToSpec(b)
+	assert.ErrorContains(t, err, "period buckets cannot be stored in a spec")
+}
+
+func TestFromSpec_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          *Spec
+		expectedError string
+	}{
+		{
+			name:          "Nil spec",
+			expectedError: "spec must not be nil",
+		},
+		{
+			name:          "Missing table",
+			spec:          &Spec{From: &TableSpec{Alias: "t"}},
+			expectedError: "from: table is required",
+		},
+		{
+			name: "Empty condition",
+			spec: &Spec{
+				From:  &TableSpec{Table: "transactions"},
+				Where: []GroupSpec{{Conditions: []ConditionSpec{{}}}},
+			},
+			expectedError: "where[0].conditions[0]: exactly one of field, column, date_range, group, and, or, not must be set",
+		},
+		{
+			name: "Invalid date range",
+			spec: &Spec{
+				From:  &TableSpec{Table: "transactions"},
+				Where: []GroupSpec{{Conditions: []ConditionSpec{{DateRange: &daterange.DateConfig{Type: daterange.BackMonth}}}}},
+			},
+			expectedError: "where[0].conditions[0].date_range: parameters.months: months parameter required for BACK_MONTH",
+		},
+		{
+			name: "Invalid logical operator",
+			spec: &Spec{
+				From: &TableSpec{Table: "transactions"},
+				Where: []GroupSpec{{Conditions: []ConditionSpec{{Group: &GroupSpec{
+					Operator:   "XOR",
+					Conditions: []ConditionSpec{{Field: &FieldSpec{Field: "a", Operator: fields.Equals, Value: 1}}},
+				}}}}},
+			},
+			expectedError: "where[0].conditions[0].group: invalid logical operator: XOR",
+		},
+		{
+			name: "Join with on and using",
+			spec: &Spec{From: &TableSpec{
+				Table: "transactions",
+				Joins: []JoinSpec{{
+					Type:  querybuilder.InnerJoin,
+					Table: "chart_of_accounts",
+					On:    &ConditionSpec{Column: &ColumnSpec{Left: "a", Operator: fields.Equals, Right: "b"}},
+					Using: []string{"account_code"},
+				}},
+			}},
+			expectedError: "from.joins[0]: on and using are mutually exclusive",
+		},
+		{
+			name: "Ambiguous order key",
+			spec: &Spec{
+				From:    &TableSpec{Table: "transactions"},
+				OrderBy: []OrderSpec{{Field: "amount", Position: 1}},
+			},
+			expectedError: "order_by[0]: exactly one of field, alias or position must be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromSpec(tt.spec)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package spec
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/condition/having"
+	"dynamic-sqlbuilder/querybuilder/condition/wheregroups"
+	"dynamic-sqlbuilder/querybuilder/from/joinfrom"
+	"dynamic-sqlbuilder/querybuilder/from/simplefrom"
+	"dynamic-sqlbuilder/querybuilder/groupby/simplegroupby"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/pagination/limitoffset"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"dynamic-sqlbuilder/querybuilder/select/simpleselect"
+	"errors"
+	"fmt"
+)
+
+// queryProvider is implemented by builders that expose their clauses
+type queryProvider interface {
+	Query() *querybuilder.Query
+}
+
+// ToSpec describes a builder as a spec so it can be persisted and reloaded
+// with FromSpec. A keyset Seek is a page position rather than part of the
+// report and is not included. Raw SQL fails with ErrUnsafeRaw.
+func ToSpec(builder querybuilder.QueryBuilder) (*Spec, error) {
+	provider, ok := builder.(queryProvider)
+	if !ok {
+		return nil, fmt.Errorf("builder %T does not expose its query", builder)
+	}
+	query := provider.Query()
+	spec := &Spec{}
+
+	var err error
+	if spec.Select, err = selectToSpec(query.SelectClause); err != nil {
+		return nil, err
+	}
+	if spec.From, err = fromToSpec(query.FromClause); err != nil {
+		return nil, err
+	}
+
+	if query.WhereClause != nil {
+		whereGroups, ok := query.WhereClause.(*wheregroups.WhereGroups)
+		if !ok {
+			return nil, fmt.Errorf("where: unsupported clause %T", query.WhereClause)
+		}
+		for _, group := range whereGroups.Groups {
+			if len(group.Conditions) == 0 {
+				continue
+			}
+			groupSpec, err := groupToSpec(group, fmt.Sprintf("where[%d]", len(spec.Where)))
+			if err != nil {
+				return nil, err
+			}
+			spec.Where = append(spec.Where, *groupSpec)
+		}
+	}
+
+	if query.GroupByClause != nil {
+		groupBy, ok := query.GroupByClause.(*simplegroupby.SimpleGroupBy)
+		if !ok {
+			return nil, fmt.Errorf("group_by: unsupported clause %T", query.GroupByClause)
+		}
+		if spec.GroupBy, err = exprsToSpec(groupBy.Fields, "group_by"); err != nil {
+			return nil, err
+		}
+	}
+
+	if query.HavingClause != nil {
+		havingClause, ok := query.HavingClause.(*having.Having)
+		if !ok {
+			return nil, fmt.Errorf("having: unsupported clause %T", query.HavingClause)
+		}
+		for i, condition := range havingClause.Group.Conditions {
+			conditionSpec, err := conditionToSpec(condition, fmt.Sprintf("having[%d]", i))
+			if err != nil {
+				return nil, err
+			}
+			spec.Having = append(spec.Having, *conditionSpec)
+		}
+	}
+
+	if query.OrderByClause != nil {
+		orderBy, ok := query.OrderByClause.(*simpleorderby.SimpleOrderBy)
+		if !ok {
+			return nil, fmt.Errorf("order_by: unsupported clause %T", query.OrderByClause)
+		}
+		for i, key := range orderBy.Keys {
+			orderSpec, err := orderKeyToSpec(key, fmt.Sprintf("order_by[%d]", i))
+			if err != nil {
+				return nil, err
+			}
+			spec.OrderBy = append(spec.OrderBy, orderSpec)
+		}
+	}
+
+	if query.LimitClause != nil {
+		limitOffset, ok := query.LimitClause.(*limitoffset.LimitOffset)
+		if !ok {
+			return nil, fmt.Errorf("limit: unsupported clause %T", query.LimitClause)
+		}
+		spec.Limit, spec.Offset = limitOffset.Limit, limitOffset.Offset
+	}
+	return spec, nil
+}
+
+func selectToSpec(clause querybuilder.SelectClause) (*SelectSpec, error) {
+	switch s := clause.(type) {
+	case nil:
+		return nil, nil
+	case *simpleselect.SimpleSelect:
+		selectFields, err := exprsToSpec(s.Fields(), "select.fields")
+		if err != nil {
+			return nil, err
+		}
+		return &SelectSpec{Fields: selectFields}, nil
+	case *aggregate.AggregateSelect:
+		if current, _ := s.Comparison(); current != nil {
+			return nil, errors.New("select: period comparisons cannot be stored in a spec, store the date range instead")
+		}
+		if buckets, _ := s.PeriodBuckets(); buckets != nil {
+			return nil, errors.New("select: period buckets cannot be stored in a spec, store the date range instead")
+		}
+		selectFields, err := exprsToSpec(s.RegularFields(), "select.fields")
+		if err != nil {
+			return nil, err
+		}
+		selectSpec := &SelectSpec{Fields: selectFields, AutoGroupBy: s.AutoGroupBy()}
+		for _, agg := range s.Aggregates() {
+			selectSpec.Aggregates = append(selectSpec.Aggregates, AggregateSpec{
+				Function: agg.Function,
+				Field:    agg.Field,
+				Alias:    agg.Alias,
+			})
+		}
+		return selectSpec, nil
+	default:
+		return nil, fmt.Errorf("select: unsupported clause %T", clause)
+	}
+}
+
+func fromToSpec(clause querybuilder.FromClause) (*TableSpec, error) {
+	switch f := clause.(type) {
+	case nil:
+		return nil, nil
+	case *simplefrom.SimpleFrom:
+		return &TableSpec{Table: f.Table}, nil
+	case *joinfrom.JoinFrom:
+		fromSpec := &TableSpec{Table: f.Table, Alias: f.Alias}
+		for i, join := range f.Joins {
+			joinSpec := JoinSpec{
+				Type:  join.Type,
+				Table: join.Table,
+				Alias: join.Alias,
+				Using: join.Using,
+			}
+			if join.On != nil {
+				on, err := conditionToSpec(join.On, fmt.Sprintf("from.joins[%d].on", i))
+				if err != nil {
+					return nil, err
+				}
+				joinSpec.On = on
+			}
+			fromSpec.Joins = append(fromSpec.Joins, joinSpec)
+		}
+		return fromSpec, nil
+	default:
+		return nil, fmt.Errorf("from: unsupported clause %T", clause)
+	}
+}
+
+func groupToSpec(group *querybuilder.WhereGroup, path string) (*GroupSpec, error) {
+	groupSpec := &GroupSpec{
+		Operator:   group.Operator,
+		Conditions: make([]ConditionSpec, 0, len(group.Conditions)),
+	}
+	for i, condition := range group.Conditions {
+		conditionSpec, err := conditionToSpec(condition, fmt.Sprintf("%s.conditions[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		groupSpec.Conditions = append(groupSpec.Conditions, *conditionSpec)
+	}
+	return groupSpec, nil
+}
+
+func conditionToSpec(condition querybuilder.QueryCondition, path string) (*ConditionSpec, error) {
+	switch c := condition.(type) {
+	case *fields.FieldCondition:
+		if c.RawField {
+			return nil, fmt.Errorf("%s: %w: %s", path, ErrUnsafeRaw, c.Field)
+		}
+		return &ConditionSpec{Field: &FieldSpec{
+			Field:          c.Field,
+			Operator:       c.Operator,
+			Value:          c.Value,
+			EmptyList:      c.EmptyList,
+			ArrayParameter: c.ArrayParameter,
+		}}, nil
+	case *fields.ColumnCondition:
+		return &ConditionSpec{Column: &ColumnSpec{Left: c.Left, Operator: c.Operator, Right: c.Right}}, nil
+	case *daterange.DateRangeCondition:
+		config := c.Config()
+		return &ConditionSpec{DateRange: &config}, nil
+	case *querybuilder.WhereGroup:
+		group, err := groupToSpec(c, path+".group")
+		if err != nil {
+			return nil, err
+		}
+		return &ConditionSpec{Group: group}, nil
+	case *querybuilder.CompoundCondition:
+		if c.Operator == querybuilder.OR {
+			conditions, err := conditionsToSpec(c.Conditions, path+".or")
+			if err != nil {
+				return nil, err
+			}
+			return &ConditionSpec{Or: conditions}, nil
+		}
+		conditions, err := conditionsToSpec(c.Conditions, path+".and")
+		if err != nil {
+			return nil, err
+		}
+		return &ConditionSpec{And: conditions}, nil
+	case *querybuilder.NotCondition:
+		negated, err := conditionToSpec(c.Condition, path+".not")
+		if err != nil {
+			return nil, err
+		}
+		return &ConditionSpec{Not: negated}, nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported condition %T", path, condition)
+	}
+}
+
+func conditionsToSpec(conditions []querybuilder.QueryCondition, path string) ([]ConditionSpec, error) {
+	conditionSpecs := make([]ConditionSpec, 0, len(conditions))
+	for i, condition := range conditions {
+		conditionSpec, err := conditionToSpec(condition, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		conditionSpecs = append(conditionSpecs, *conditionSpec)
+	}
+	return conditionSpecs, nil
+}
+
+func orderKeyToSpec(key simpleorderby.OrderKey, path string) (OrderSpec, error) {
+	orderSpec := OrderSpec{Direction: key.Direction, Nulls: key.Nulls}
+	switch key.Kind {
+	case simpleorderby.FieldKey:
+		orderSpec.Field = key.Field
+	case simpleorderby.AliasKey:
+		orderSpec.Alias = key.Field
+	case simpleorderby.OrdinalKey:
+		orderSpec.Position = key.Position
+	case simpleorderby.RawKey:
+		return OrderSpec{}, fmt.Errorf("%s: %w: %s", path, ErrUnsafeRaw, key.Field)
+	default:
+		return OrderSpec{}, fmt.Errorf("%s: invalid order by key kind: %s", path, key.Kind)
+	}
+	return orderSpec, nil
+}
+
+func exprsToSpec(exprs []identifier.Expr, path string) ([]string, error) {
+	names := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		if expr.Raw {
+			return nil, fmt.Errorf("%s: %w: %s", path, ErrUnsafeRaw, expr.Text)
+		}
+		names = append(names, expr.Text)
+	}
+	return names, nil
+}
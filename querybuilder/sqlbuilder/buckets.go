@@ -0,0 +1,66 @@
+package sqlbuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+)
+
+// PivotPeriods turns the aggregate select into one column per period of the
+// range, e.g. the months of a year as 12 columns, and restricts the rows to
+// the range:
+//
+//	SELECT department,
+//	  SUM(amount) FILTER (WHERE <January>) AS total_m_2024_01,
+//	  SUM(amount) FILTER (WHERE <February>) AS total_m_2024_02, ...
+//	FROM ... WHERE <range> GROUP BY department
+//
+// The periods are resolved when the query is built. Like AddAggregate it needs
+// SelectAggregate to be called first.
+func (b *Builder) PivotPeriods(condition *daterange.DateRangeCondition, granularity daterange.Granularity) *Builder {
+	if b.aggregateSelect != nil {
+		b.aggregateSelect.WithPivot(periodBuckets{condition: condition, granularity: granularity})
+	}
+	b.WhereGroup(querybuilder.AND, func(wg *querybuilder.WhereGroup) {
+		wg.Add(condition)
+	})
+	return b
+}
+
+// GroupByPeriod adds a column named alias that labels every row with its
+// period of the range, e.g. 2024-01, groups by it and restricts the rows to
+// the range:
+//
+//	SELECT department, CASE WHEN <January> THEN '2024-01' ... END AS period,
+//	  SUM(amount) AS total
+//	FROM ... WHERE <range> GROUP BY department, 2
+//
+// The label is grouped by position, so AutoGroupBy must be used. Like
+// AddAggregate it needs SelectAggregate to be called first.
+func (b *Builder) GroupByPeriod(condition *daterange.DateRangeCondition, granularity daterange.Granularity, alias string) *Builder {
+	if b.aggregateSelect != nil {
+		b.aggregateSelect.WithPeriodLabel(alias, periodBuckets{condition: condition, granularity: granularity})
+	}
+	b.WhereGroup(querybuilder.AND, func(wg *querybuilder.WhereGroup) {
+		wg.Add(condition)
+	})
+	return b
+}
+
+// periodBuckets splits a date range into the buckets of an aggregate select
+type periodBuckets struct {
+	condition   *daterange.DateRangeCondition
+	granularity daterange.Granularity
+}
+
+func (p periodBuckets) Buckets() ([]aggregate.Bucket, error) {
+	periods, err := p.condition.Buckets(p.granularity)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]aggregate.Bucket, len(periods))
+	for i, period := range periods {
+		buckets[i] = aggregate.Bucket{Key: period.Key, Alias: period.Alias, Filter: period.Condition}
+	}
+	return buckets, nil
+}
@@ -0,0 +1,78 @@
+package sqlbuilder_test
+
+import (
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func firstQuarter(t *testing.T) *daterange.DateRangeCondition {
+	jakarta, err := time.LoadLocation(daterange.DefaultTimezone)
+	require.NoError(t, err)
+	return daterange.NewDateRangeCondition(daterange.DateConfig{
+		Type:    daterange.QTD,
+		Columns: &daterange.PeriodColumns{Mode: daterange.PeriodKeyColumn, Column: "period_key"},
+	}, daterange.WithAsOf(time.Date(2024, 3, 10, 0, 0, 0, 0, jakarta)))
+}
+
+func TestBuilder_PivotPeriods(t *testing.T) {
+	b := sqlbuilder.New(dialect.Postgres)
+	b.SelectAggregate().
+		AddRegularField("department").
+		AddAggregate(aggregate.Sum, "amount", "total").
+		AutoGroupBy().
+		From("transactions")
+	b.PivotPeriods(firstQuarter(t), daterange.MonthGranularity)
+
+	sql, args, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "department", `+
+		`SUM("amount") FILTER (WHERE "period_key" BETWEEN $1 AND $2) AS "total_m_2024_01", `+
+		`SUM("amount") FILTER (WHERE "period_key" BETWEEN $3 AND $4) AS "total_m_2024_02", `+
+		`SUM("amount") FILTER (WHERE "period_key" BETWEEN $5 AND $6) AS "total_m_2024_03" `+
+		`FROM "transactions" WHERE "period_key" BETWEEN $7 AND $8 GROUP BY "department"`, sql)
+	assert.Equal(t, []interface{}{202401, 202401, 202402, 202402, 202403, 202403, 202401, 202403}, args)
+}
+
+func TestBuilder_GroupByPeriod(t *testing.T) {
+	b := sqlbuilder.New(dialect.MySQL)
+	b.SelectAggregate().
+		AddRegularField("department").
+		AddAggregate(aggregate.Sum, "amount", "total").
+		AutoGroupBy().
+		From("transactions")
+	b.GroupByPeriod(firstQuarter(t), daterange.MonthGranularity, "period")
+
+	sql, args, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `department`, CASE "+
+		"WHEN `period_key` BETWEEN ? AND ? THEN ? "+
+		"WHEN `period_key` BETWEEN ? AND ? THEN ? "+
+		"WHEN `period_key` BETWEEN ? AND ? THEN ? END AS `period`, SUM(`amount`) AS `total` "+
+		"FROM `transactions` WHERE `period_key` BETWEEN ? AND ? GROUP BY `department`, 2", sql)
+	assert.Equal(t, []interface{}{
+		202401, 202401, "2024-01",
+		202402, 202402, "2024-02",
+		202403, 202403, "2024-03",
+		202401, 202403,
+	}, args)
+}
+
+func TestBuilder_GroupByPeriodNeedsAutoGroupBy(t *testing.T) {
+	b := sqlbuilder.New(dialect.Postgres)
+	b.SelectAggregate().
+		AddRegularField("department").
+		AddAggregate(aggregate.Sum, "amount", "total").
+		From("transactions").
+		GroupBy("department")
+	b.GroupByPeriod(firstQuarter(t), daterange.QuarterGranularity, "quarter")
+
+	_, _, err := b.Build()
+	assert.EqualError(t, err, "failed to build GROUP BY clause: period label quarter is grouped by position, use AutoGroupBy")
+}
@@ -0,0 +1,521 @@
+package sqlbuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/having"
+	"dynamic-sqlbuilder/querybuilder/condition/wheregroups"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/from/joinfrom"
+	"dynamic-sqlbuilder/querybuilder/from/simplefrom"
+	"dynamic-sqlbuilder/querybuilder/groupby/simplegroupby"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/pagination/keyset"
+	"dynamic-sqlbuilder/querybuilder/pagination/limitoffset"
+	"dynamic-sqlbuilder/querybuilder/schema"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"dynamic-sqlbuilder/querybuilder/select/simpleselect"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Builder implements QueryBuilder for any SQL dialect. The dialect decides
+// placeholders, identifier quoting and row limiting when the query is built.
+type Builder struct {
+	dialect         dialect.Dialect
+	query           *querybuilder.Query
+	aggregateSelect *aggregate.AggregateSelect
+	joinFrom        *joinfrom.JoinFrom
+	chainErr        error // First call made out of order or with invalid input, returned by Build
+	groupBy         *simplegroupby.SimpleGroupBy
+	having          *having.Having
+	orderBy         *simpleorderby.SimpleOrderBy
+	limitOffset     *limitoffset.LimitOffset
+	seek            *keyset.Keyset
+	whereGroups     *wheregroups.WhereGroups // Keep track of where groups
+	currentGroup    *querybuilder.WhereGroup // Group Where adds to, nil until And, Or or the first Where
+	registry        *schema.Registry         // Optional allowlist checked on Build
+	logger          *slog.Logger             // Optional, Build is silent without one
+}
+
+func New(d dialect.Dialect) *Builder {
+	whereGroups := wheregroups.NewWhereGroups()
+
+	return &Builder{
+		dialect: d,
+		query: &querybuilder.Query{
+			SelectClause: simpleselect.NewSimpleSelect("*"),
+			WhereClause:  whereGroups,
+			Args:         make([]interface{}, 0),
+		},
+		whereGroups: whereGroups,
+	}
+}
+
+// Dialect returns the dialect the query is built for
+func (b *Builder) Dialect() dialect.Dialect {
+	return b.dialect
+}
+
+// Query returns the clauses collected so far, e.g. to serialize the builder
+func (b *Builder) Query() *querybuilder.Query {
+	return b.query
+}
+
+// Where adds the condition to the current group, which is the group started
+// by the last And or Or. Without one, or after a WhereGroup, Where starts an
+// AND group, so Where(a).Where(b) is (a AND b) and
+// Where(a).Or().Where(b).Where(c) is a OR (b OR c).
+//
+// Groups are joined left to right whatever SQL's AND/OR precedence: when the
+// operator changes, the groups before it are parenthesized, so
+// Where(a).Or().Where(b).And().Where(c) is (a OR b) AND c.
+func (b *Builder) Where(condition querybuilder.QueryCondition) querybuilder.QueryBuilder {
+	if b.currentGroup == nil {
+		b.startGroup(querybuilder.AND)
+	}
+	b.currentGroup.Add(condition)
+	return b
+}
+
+// WhereGroup adds the group built by buildGroup, joined to the previous groups
+// with operator. The group is complete: a following Where starts a new group.
+func (b *Builder) WhereGroup(operator querybuilder.LogicalOperator, buildGroup func(*querybuilder.WhereGroup)) querybuilder.QueryBuilder {
+	group := querybuilder.NewWhereGroup(operator)
+	buildGroup(group)
+	b.whereGroups.Add(group)
+	b.currentGroup = nil
+	return b
+}
+
+// And starts a group joined to the previous groups with AND. The conditions
+// added to it by Where are also joined with AND.
+func (b *Builder) And() querybuilder.QueryBuilder {
+	b.startGroup(querybuilder.AND)
+	return b
+}
+
+// Or starts a group joined to the previous groups with OR. The conditions
+// added to it by Where are also joined with OR.
+func (b *Builder) Or() querybuilder.QueryBuilder {
+	b.startGroup(querybuilder.OR)
+	return b
+}
+
+// startGroup adds an empty group that Where adds to. Groups left empty are
+// skipped when the query is built.
+func (b *Builder) startGroup(operator querybuilder.LogicalOperator) {
+	b.currentGroup = querybuilder.NewWhereGroup(operator)
+	b.whereGroups.Add(b.currentGroup)
+}
+
+// From sets the FROM table. It must be called before any Join, replacing the
+// table afterwards would drop the joins and fails the build.
+func (b *Builder) From(table string) querybuilder.QueryBuilder {
+	if b.hasJoins() {
+		b.fail("FROM", fmt.Errorf("FROM %s set after a join would drop the joins, call From before Join", table))
+		return b
+	}
+	b.query.FromClause = simplefrom.NewSimpleFrom(table)
+	b.joinFrom = nil
+	return b
+}
+
+// FromAs sets the FROM table with an alias, like From it must be called before any Join
+func (b *Builder) FromAs(table, alias string) querybuilder.QueryBuilder {
+	if b.hasJoins() {
+		b.fail("FROM", fmt.Errorf("FROM %s AS %s set after a join would drop the joins, call FromAs before Join", table, alias))
+		return b
+	}
+	b.joinFrom = joinfrom.NewJoinFrom(table, alias)
+	b.query.FromClause = b.joinFrom
+	return b
+}
+
+// Join joins a table to the one set by From or FromAs. Without one the
+// build fails.
+func (b *Builder) Join(joinType querybuilder.JoinType, table, alias string, on querybuilder.QueryCondition) querybuilder.QueryBuilder {
+	if joinFrom := b.joinClause(table); joinFrom != nil {
+		joinFrom.Join(joinType, table, alias, on)
+	}
+	return b
+}
+
+// JoinUsing joins a table on columns with the same name, see Join
+func (b *Builder) JoinUsing(joinType querybuilder.JoinType, table, alias string, columns ...string) querybuilder.QueryBuilder {
+	if joinFrom := b.joinClause(table); joinFrom != nil {
+		joinFrom.JoinUsing(joinType, table, alias, columns...)
+	}
+	return b
+}
+
+// joinClause upgrades the FROM clause to one that supports joins, keeping the
+// table set by From. It returns nil when no table was set to join to.
+func (b *Builder) joinClause(table string) *joinfrom.JoinFrom {
+	if b.joinFrom != nil {
+		return b.joinFrom
+	}
+	simpleFrom, ok := b.query.FromClause.(*simplefrom.SimpleFrom)
+	if !ok {
+		b.fail("FROM", fmt.Errorf("JOIN %s has no table to join to, call From or FromAs first", table))
+		return nil
+	}
+	b.joinFrom = joinfrom.NewJoinFrom(simpleFrom.Table, "")
+	b.query.FromClause = b.joinFrom
+	return b.joinFrom
+}
+
+func (b *Builder) hasJoins() bool {
+	return b.joinFrom != nil && len(b.joinFrom.Joins) > 0
+}
+
+// fail keeps the first misuse of the chain for Build to return
+func (b *Builder) fail(clause string, err error) {
+	if b.chainErr == nil {
+		b.chainErr = fmt.Errorf("failed to build %s clause: %w", clause, err)
+	}
+}
+func (b *Builder) Select(fields ...string) querybuilder.QueryBuilder {
+	b.query.SelectClause = simpleselect.NewSimpleSelect(fields...)
+	b.aggregateSelect = nil
+	return b
+}
+
+// SelectUnsafeRaw selects raw SQL expressions that bypass identifier validation
+func (b *Builder) SelectUnsafeRaw(expressions ...string) querybuilder.QueryBuilder {
+	b.query.SelectClause = (&simpleselect.SimpleSelect{}).AddUnsafeRaw(expressions...)
+	b.aggregateSelect = nil
+	return b
+}
+
+// Now returns QueryBuilder to satisfy interface
+func (b *Builder) SelectAggregate() querybuilder.QueryBuilder {
+	aggSelect := aggregate.NewAggregateSelect()
+	b.query.SelectClause = aggSelect
+	b.aggregateSelect = aggSelect // Store reference
+	return b
+}
+
+// Add methods to access aggregate functions
+func (b *Builder) AddRegularField(field string) querybuilder.QueryBuilder {
+	if b.aggregateSelect != nil {
+		b.aggregateSelect.AddRegularField(field)
+	}
+	return b
+}
+
+func (b *Builder) AddUnsafeRawField(expression string) querybuilder.QueryBuilder {
+	if b.aggregateSelect != nil {
+		b.aggregateSelect.AddUnsafeRawField(expression)
+	}
+	return b
+}
+
+func (b *Builder) AddAggregate(fn aggregate.AggregateFunction, field, alias string) querybuilder.QueryBuilder {
+	if b.aggregateSelect != nil {
+		b.aggregateSelect.AddAggregate(fn, field, alias)
+	}
+	return b
+}
+func (b *Builder) GroupBy(fields ...string) querybuilder.QueryBuilder {
+	if b.groupBy == nil {
+		b.groupBy = simplegroupby.NewSimpleGroupBy()
+		b.query.GroupByClause = b.groupBy
+	}
+	b.groupBy.Add(fields...)
+	return b
+}
+
+// AutoGroupBy derives the GROUP BY list from the regular fields of the aggregate select
+func (b *Builder) AutoGroupBy() querybuilder.QueryBuilder {
+	if b.aggregateSelect != nil {
+		b.aggregateSelect.WithAutoGroupBy()
+	}
+	return b
+}
+
+func (b *Builder) Having(condition querybuilder.QueryCondition) querybuilder.QueryBuilder {
+	b.havingClause().Add(condition)
+	return b
+}
+
+func (b *Builder) HavingGroup(operator querybuilder.LogicalOperator, buildGroup func(*querybuilder.WhereGroup)) querybuilder.QueryBuilder {
+	group := querybuilder.NewWhereGroup(operator)
+	buildGroup(group)
+	b.havingClause().Add(group)
+	return b
+}
+
+func (b *Builder) havingClause() *having.Having {
+	if b.having == nil {
+		b.having = having.NewHaving()
+		b.query.HavingClause = b.having
+	}
+	return b.having
+}
+
+func (b *Builder) OrderBy(keys ...simpleorderby.OrderKey) querybuilder.QueryBuilder {
+	if b.orderBy == nil {
+		b.orderBy = simpleorderby.NewSimpleOrderBy()
+		b.query.OrderByClause = b.orderBy
+	}
+	b.orderBy.Add(keys...)
+	return b
+}
+
+func (b *Builder) Limit(limit int) querybuilder.QueryBuilder {
+	b.limitClause().SetLimit(limit)
+	return b
+}
+
+func (b *Builder) Offset(offset int) querybuilder.QueryBuilder {
+	b.limitClause().SetOffset(offset)
+	return b
+}
+
+func (b *Builder) limitClause() *limitoffset.LimitOffset {
+	if b.limitOffset == nil {
+		b.limitOffset = limitoffset.NewLimitOffset()
+		b.query.LimitClause = b.limitOffset
+	}
+	return b.limitOffset
+}
+
+// Seek restricts the query to rows after the keyset position. The keyset keys
+// become the ORDER BY when none has been set, so a decoded cursor is enough to
+// fetch the next page. Otherwise they must be the leading ORDER BY keys with
+// the same directions, Build fails when the page would not follow the sort.
+func (b *Builder) Seek(ks *keyset.Keyset) querybuilder.QueryBuilder {
+	if ks == nil {
+		b.fail("WHERE", errors.New("seek requires a keyset"))
+		return b
+	}
+	b.seek = ks
+	if b.orderBy == nil {
+		b.OrderBy(ks.Keys...)
+	}
+	return b
+}
+
+// buildWhere builds the WHERE clause combined with the keyset predicate
+func (b *Builder) buildWhere(paramOffset int) (string, []interface{}, error) {
+	if b.seek == nil {
+		if b.query.WhereClause == nil {
+			return "", nil, nil
+		}
+		return b.query.WhereClause.Build(b.dialect, paramOffset)
+	}
+
+	var whereSQL string
+	var args []interface{}
+	if b.query.WhereClause != nil {
+		var err error
+		whereSQL, args, err = b.whereGroups.BuildExpression(b.dialect, paramOffset)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := b.seek.MatchesOrder(b.orderBy.Keys); err != nil {
+		return "", nil, err
+	}
+	seekSQL, seekArgs, err := b.seek.Build(b.dialect, paramOffset+len(args))
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, seekArgs...)
+
+	if whereSQL == "" {
+		return fmt.Sprintf("WHERE %s", seekSQL), args, nil
+	}
+	return fmt.Sprintf("WHERE (%s) AND %s", whereSQL, seekSQL), args, nil
+}
+
+// resolveGroupBy returns the effective GROUP BY clause, deriving it from the
+// aggregate select when requested, and validates it against the select list
+func (b *Builder) resolveGroupBy() (querybuilder.GroupByClause, error) {
+	if b.aggregateSelect == nil {
+		return b.query.GroupByClause, nil
+	}
+
+	var groupByFields []identifier.Expr
+	if b.groupBy != nil {
+		groupByFields = b.groupBy.Fields
+	}
+
+	groupByClause := b.query.GroupByClause
+	if b.groupBy == nil && b.aggregateSelect.AutoGroupBy() {
+		groupByFields = b.aggregateSelect.GroupByFields()
+		if len(groupByFields) > 0 {
+			groupByClause = simplegroupby.NewSimpleGroupBy().AddExpr(groupByFields...)
+		}
+	}
+
+	if err := b.aggregateSelect.ValidateGroupBy(groupByFields); err != nil {
+		return nil, err
+	}
+	return groupByClause, nil
+}
+
+func (b *Builder) Build() (string, []interface{}, error) {
+	started := time.Now()
+	sql, args, err := b.build()
+	b.logQuery(started, len(args), err)
+	return sql, args, err
+}
+
+func (b *Builder) build() (string, []interface{}, error) {
+	var queryParts []string
+	var args []interface{}
+
+	if b.chainErr != nil {
+		return "", nil, b.chainErr
+	}
+	if b.registry != nil {
+		if err := b.validateSchema(); err != nil {
+			return "", nil, fmt.Errorf("schema validation failed: %w", err)
+		}
+	}
+
+	// Build SELECT clause
+	started := time.Now()
+	selectSQL, selectArgs, err := b.query.SelectClause.Build(b.dialect, 1)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build SELECT clause: %w", err)
+	}
+	b.logClause("SELECT", started, len(selectArgs))
+	queryParts = append(queryParts, selectSQL)
+	args = append(args, selectArgs...)
+
+	// Build FROM clause
+	if b.query.FromClause != nil {
+		started := time.Now()
+		fromSQL, fromArgs, err := b.query.FromClause.Build(b.dialect, len(args)+1)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build FROM clause: %w", err)
+		}
+		b.logClause("FROM", started, len(fromArgs))
+		queryParts = append(queryParts, fromSQL)
+		args = append(args, fromArgs...)
+	}
+	// Build WHERE clause
+	if b.query.WhereClause != nil || b.seek != nil {
+		started := time.Now()
+		whereSQL, whereArgs, err := b.buildWhere(len(args) + 1)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build WHERE clause: %w", err)
+		}
+		if whereSQL != "" {
+			queryParts = append(queryParts, whereSQL)
+			args = append(args, whereArgs...)
+			b.logClause("WHERE", started, len(whereArgs))
+		}
+	}
+
+	// Build GROUP BY clause
+	started = time.Now()
+	groupByClause, err := b.resolveGroupBy()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build GROUP BY clause: %w", err)
+	}
+	if groupByClause != nil {
+		groupBySQL, err := groupByClause.Build(b.dialect)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build GROUP BY clause: %w", err)
+		}
+		queryParts = append(queryParts, groupBySQL)
+		b.logClause("GROUP BY", started, 0)
+	}
+
+	// Build HAVING clause, parameters continue after the WHERE parameters
+	if b.query.HavingClause != nil {
+		started := time.Now()
+		havingClause := b.query.HavingClause
+		if b.having != nil && b.aggregateSelect != nil {
+			aliases, err := b.aggregateSelect.AliasExpressions(b.dialect)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to build HAVING clause: %w", err)
+			}
+			havingClause = b.having.ResolveAliases(aliases)
+		}
+		havingSQL, havingArgs, err := havingClause.Build(b.dialect, len(args)+1)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build HAVING clause: %w", err)
+		}
+		if havingSQL != "" {
+			queryParts = append(queryParts, havingSQL)
+			args = append(args, havingArgs...)
+			b.logClause("HAVING", started, len(havingArgs))
+		}
+	}
+
+	// Build ORDER BY clause
+	if b.query.OrderByClause != nil {
+		started := time.Now()
+		orderByClause := b.query.OrderByClause
+		if b.orderBy != nil {
+			var aliases []string
+			var columnCount int
+			if columns, ok := b.query.SelectClause.(querybuilder.SelectColumns); ok {
+				aliases, columnCount = columns.Aliases(), columns.ColumnCount()
+			}
+			if err := b.orderBy.Validate(aliases, columnCount); err != nil {
+				return "", nil, fmt.Errorf("failed to build ORDER BY clause: %w", err)
+			}
+			if b.aggregateSelect != nil {
+				expressions, err := b.aggregateSelect.AliasExpressions(b.dialect)
+				if err != nil {
+					return "", nil, fmt.Errorf("failed to build ORDER BY clause: %w", err)
+				}
+				orderByClause = b.orderBy.ResolveAliases(expressions)
+			}
+		}
+		orderBySQL, err := orderByClause.Build(b.dialect)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build ORDER BY clause: %w", err)
+		}
+		queryParts = append(queryParts, orderBySQL)
+		b.logClause("ORDER BY", started, 0)
+	}
+
+	// Build LIMIT/OFFSET clause
+	started = time.Now()
+	if b.useTop() {
+		// Named placeholders keep their numbers, so TOP can take the last one
+		topSQL, topArgs, err := b.limitOffset.Top(b.dialect, len(args)+1)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build LIMIT clause: %w", err)
+		}
+		queryParts[0] = strings.Replace(queryParts[0], "SELECT ", "SELECT "+topSQL+" ", 1)
+		args = append(args, topArgs...)
+		b.logClause("TOP", started, len(topArgs))
+	} else if b.query.LimitClause != nil {
+		limitSQL, limitArgs, err := b.query.LimitClause.Build(b.dialect, len(args)+1)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build LIMIT clause: %w", err)
+		}
+		if limitSQL != "" {
+			// OFFSET ... FETCH is only valid after an ORDER BY
+			if b.dialect.Features().Limit == dialect.OffsetFetch && b.query.OrderByClause == nil {
+				queryParts = append(queryParts, "ORDER BY (SELECT NULL)")
+			}
+			queryParts = append(queryParts, limitSQL)
+			args = append(args, limitArgs...)
+			b.logClause("LIMIT", started, len(limitArgs))
+		}
+	}
+	return strings.Join(queryParts, " "), args, nil
+}
+
+// useTop reports whether the limit is rendered as SELECT TOP (n), which
+// OFFSET ... FETCH dialects use when there is no ORDER BY and no offset
+func (b *Builder) useTop() bool {
+	return b.dialect.Features().Limit == dialect.OffsetFetch &&
+		b.query.OrderByClause == nil &&
+		b.limitOffset != nil &&
+		b.limitOffset.Limit != nil &&
+		b.limitOffset.Offset == nil
+}
@@ -0,0 +1,197 @@
+package sqlbuilder_test
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	mysqlbuilder "dynamic-sqlbuilder/querybuilder/mysqlBuilder"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/pagination/keyset"
+	pgbuilder "dynamic-sqlbuilder/querybuilder/pgBuilder"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	sqlitebuilder "dynamic-sqlbuilder/querybuilder/sqliteBuilder"
+	sqlserverbuilder "dynamic-sqlbuilder/querybuilder/sqlserverBuilder"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// builders lists every dialect builder; each scenario runs against all of them
+var builders = []struct {
+	name string
+	new  func() querybuilder.QueryBuilder
+}{
+	{name: "postgres", new: func() querybuilder.QueryBuilder { return pgbuilder.NewPostgresQueryBuilder() }},
+	{name: "mysql", new: func() querybuilder.QueryBuilder { return mysqlbuilder.NewMySQLQueryBuilder() }},
+	{name: "sqlite", new: func() querybuilder.QueryBuilder { return sqlitebuilder.NewSQLiteQueryBuilder() }},
+	{name: "sqlserver", new: func() querybuilder.QueryBuilder { return sqlserverbuilder.NewSQLServerQueryBuilder() }},
+}
+
+type expectation struct {
+	sql  string
+	args []interface{}
+	err  string // Build fails with this error instead
+}
+
+// dialectCase is a query built with every dialect builder. It either fails
+// with the same error everywhere or has an expectation per dialect.
+type dialectCase struct {
+	name          string
+	build         func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder
+	expected      map[string]expectation
+	expectedError string
+}
+
+func runDialectCases(t *testing.T, tests []dialectCase) {
+	for _, tt := range tests {
+		for _, builder := range builders {
+			t.Run(tt.name+"/"+builder.name, func(t *testing.T) {
+				sql, args, err := tt.build(builder.new()).Build()
+				if tt.expectedError != "" {
+					assert.ErrorContains(t, err, tt.expectedError)
+					return
+				}
+				expected, ok := tt.expected[builder.name]
+				require.True(t, ok, "missing expectation for %s", builder.name)
+				if expected.err != "" {
+					assert.ErrorContains(t, err, expected.err)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, expected.sql, sql)
+				assert.Equal(t, expected.args, args)
+			})
+		}
+	}
+}
+
+func TestBuilder_Dialects(t *testing.T) {
+	runDialectCases(t, []dialectCase{
+		{
+			name: "Report with join, ILIKE, HAVING, NULLS ordering and pagination",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("coa.coadescription").
+					AddAggregate(aggregate.Sum, "t.amount", "total_amount").
+					AutoGroupBy().
+					FromAs("transactions", "t").
+					Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+						fields.NewColumnCondition("t.account_code", fields.Equals, "coa.account_code")).
+					WhereGroup(querybuilder.AND, func(wg *querybuilder.WhereGroup) {
+						wg.Add(fields.NewFieldCondition("t.department", fields.In, []interface{}{"FIN", "OPS"}))
+						wg.Add(fields.NewFieldCondition("coa.coadescription", fields.ILike, "%cash%"))
+					}).
+					Having(fields.NewFieldCondition("total_amount", fields.GreaterThan, 1000)).
+					OrderBy(simpleorderby.Alias("total_amount").Desc(), simpleorderby.Field("coa.coadescription").NullsFirst()).
+					Limit(10).
+					Offset(20)
+			},
+			expected: map[string]expectation{
+				"postgres": {
+					sql: `SELECT "coa"."coadescription", SUM("t"."amount") AS "total_amount" ` +
+						`FROM "transactions" AS "t" LEFT JOIN "chart_of_accounts" AS "coa" ON "t"."account_code" = "coa"."account_code" ` +
+						`WHERE ("t"."department" IN ($1,$2) AND "coa"."coadescription" ILIKE $3) ` +
+						`GROUP BY "coa"."coadescription" HAVING SUM("t"."amount") > $4 ` +
+						`ORDER BY "total_amount" DESC, "coa"."coadescription" NULLS FIRST LIMIT $5 OFFSET $6`,
+					args: []interface{}{"FIN", "OPS", "%cash%", 1000, 10, 20},
+				},
+				"mysql": {
+					sql: "SELECT `coa`.`coadescription`, SUM(`t`.`amount`) AS `total_amount` " +
+						"FROM `transactions` AS `t` LEFT JOIN `chart_of_accounts` AS `coa` ON `t`.`account_code` = `coa`.`account_code` " +
+						"WHERE (`t`.`department` IN (?,?) AND LOWER(`coa`.`coadescription`) LIKE LOWER(?)) " +
+						"GROUP BY `coa`.`coadescription` HAVING SUM(`t`.`amount`) > ? " +
+						"ORDER BY `total_amount` DESC, CASE WHEN `coa`.`coadescription` IS NULL THEN 0 ELSE 1 END, `coa`.`coadescription` LIMIT ? OFFSET ?",
+					args: []interface{}{"FIN", "OPS", "%cash%", 1000, 10, 20},
+				},
+				"sqlite": {
+					sql: `SELECT "coa"."coadescription", SUM("t"."amount") AS "total_amount" ` +
+						`FROM "transactions" AS "t" LEFT JOIN "chart_of_accounts" AS "coa" ON "t"."account_code" = "coa"."account_code" ` +
+						`WHERE ("t"."department" IN (?,?) AND LOWER("coa"."coadescription") LIKE LOWER(?)) ` +
+						`GROUP BY "coa"."coadescription" HAVING SUM("t"."amount") > ? ` +
+						`ORDER BY "total_amount" DESC, "coa"."coadescription" NULLS FIRST LIMIT ? OFFSET ?`,
+					args: []interface{}{"FIN", "OPS", "%cash%", 1000, 10, 20},
+				},
+				"sqlserver": {
+					sql: "SELECT [coa].[coadescription], SUM([t].[amount]) AS [total_amount] " +
+						"FROM [transactions] AS [t] LEFT JOIN [chart_of_accounts] AS [coa] ON [t].[account_code] = [coa].[account_code] " +
+						"WHERE ([t].[department] IN (@p1,@p2) AND LOWER([coa].[coadescription]) LIKE LOWER(@p3)) " +
+						"GROUP BY [coa].[coadescription] HAVING SUM([t].[amount]) > @p4 " +
+						"ORDER BY [total_amount] DESC, CASE WHEN [coa].[coadescription] IS NULL THEN 0 ELSE 1 END, [coa].[coadescription] " +
+						"OFFSET @p5 ROWS FETCH NEXT @p6 ROWS ONLY",
+					args: []interface{}{"FIN", "OPS", "%cash%", 1000, 20, 10},
+				},
+			},
+		},
+		{
+			name: "Limit without ORDER BY",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id", "name").From("accounts").Limit(5)
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "id", "name" FROM "accounts" LIMIT $1`, args: []interface{}{5}},
+				"mysql":     {sql: "SELECT `id`, `name` FROM `accounts` LIMIT ?", args: []interface{}{5}},
+				"sqlite":    {sql: `SELECT "id", "name" FROM "accounts" LIMIT ?`, args: []interface{}{5}},
+				"sqlserver": {sql: "SELECT TOP (@p1) [id], [name] FROM [accounts]", args: []interface{}{5}},
+			},
+		},
+		{
+			name: "Offset without limit",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id").From("accounts").Offset(40)
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "id" FROM "accounts" OFFSET $1`, args: []interface{}{40}},
+				"mysql":     {sql: "SELECT `id` FROM `accounts` LIMIT 18446744073709551615 OFFSET ?", args: []interface{}{40}},
+				"sqlite":    {sql: `SELECT "id" FROM "accounts" LIMIT -1 OFFSET ?`, args: []interface{}{40}},
+				"sqlserver": {sql: "SELECT [id] FROM [accounts] ORDER BY (SELECT NULL) OFFSET @p1 ROWS", args: []interface{}{40}},
+			},
+		},
+		{
+			name: "Keyset seek",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id").From("journal").
+					Where(fields.NewFieldCondition("status", fields.Equals, "posted")).
+					Seek(keyset.NewKeyset(
+						[]simpleorderby.OrderKey{simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("id").Desc()},
+						[]interface{}{"2024-03-31", 42},
+					)).
+					Limit(20)
+			},
+			expected: map[string]expectation{
+				"postgres": {
+					sql:  `SELECT "id" FROM "journal" WHERE ("status" = $1) AND ("posted_at", "id") < ($2, $3) ORDER BY "posted_at" DESC, "id" DESC LIMIT $4`,
+					args: []interface{}{"posted", "2024-03-31", 42, 20},
+				},
+				"mysql": {
+					sql:  "SELECT `id` FROM `journal` WHERE (`status` = ?) AND (`posted_at`, `id`) < (?, ?) ORDER BY `posted_at` DESC, `id` DESC LIMIT ?",
+					args: []interface{}{"posted", "2024-03-31", 42, 20},
+				},
+				"sqlite": {
+					sql:  `SELECT "id" FROM "journal" WHERE ("status" = ?) AND ("posted_at", "id") < (?, ?) ORDER BY "posted_at" DESC, "id" DESC LIMIT ?`,
+					args: []interface{}{"posted", "2024-03-31", 42, 20},
+				},
+				"sqlserver": {
+					sql: "SELECT [id] FROM [journal] WHERE ([status] = @p1) AND ([posted_at] < @p2 OR ([posted_at] = @p3 AND [id] < @p4)) " +
+						"ORDER BY [posted_at] DESC, [id] DESC OFFSET 0 ROWS FETCH NEXT @p5 ROWS ONLY",
+					args: []interface{}{"posted", "2024-03-31", "2024-03-31", 42, 20},
+				},
+			},
+		},
+	})
+}
+
+func TestBuilder_DialectErrors(t *testing.T) {
+	_, _, err := sqlserverbuilder.NewSQLServerQueryBuilder().
+		From("transactions").
+		JoinUsing(querybuilder.InnerJoin, "chart_of_accounts", "", "account_code").
+		Build()
+	assert.ErrorContains(t, err, "USING is not supported by sqlserver")
+
+	_, _, err = mysqlbuilder.NewMySQLQueryBuilder().
+		Select("id").
+		From("accounts").
+		OrderBy(simpleorderby.Ordinal(1).NullsLast()).
+		Build()
+	assert.ErrorContains(t, err, "NULLS LAST by position is not supported by mysql")
+}
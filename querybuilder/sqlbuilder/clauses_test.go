@@ -0,0 +1,513 @@
+package sqlbuilder_test
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/pagination/keyset"
+	"dynamic-sqlbuilder/querybuilder/schema"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_GroupBy(t *testing.T) {
+	runDialectCases(t, []dialectCase{
+		{
+			name: "Explicit GROUP BY",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					From("transactions").
+					Where(fields.NewFieldCondition("is_active", fields.Equals, true)).
+					GroupBy("department")
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" WHERE "is_active" = $1 GROUP BY "department"`, args: []interface{}{true}},
+				"mysql":     {sql: "SELECT `department`, SUM(`amount`) AS `total_amount` FROM `transactions` WHERE `is_active` = ? GROUP BY `department`", args: []interface{}{true}},
+				"sqlite":    {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" WHERE "is_active" = ? GROUP BY "department"`, args: []interface{}{true}},
+				"sqlserver": {sql: "SELECT [department], SUM([amount]) AS [total_amount] FROM [transactions] WHERE [is_active] = @p1 GROUP BY [department]", args: []interface{}{true}},
+			},
+		},
+		{
+			name: "Auto GROUP BY",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("account_type").
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					AutoGroupBy().
+					From("transactions")
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "account_type", "department", SUM("amount") AS "total_amount" FROM "transactions" GROUP BY "account_type", "department"`},
+				"mysql":     {sql: "SELECT `account_type`, `department`, SUM(`amount`) AS `total_amount` FROM `transactions` GROUP BY `account_type`, `department`"},
+				"sqlite":    {sql: `SELECT "account_type", "department", SUM("amount") AS "total_amount" FROM "transactions" GROUP BY "account_type", "department"`},
+				"sqlserver": {sql: "SELECT [account_type], [department], SUM([amount]) AS [total_amount] FROM [transactions] GROUP BY [account_type], [department]"},
+			},
+		},
+		{
+			name: "Explicit GROUP BY wins over auto",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					AutoGroupBy().
+					From("transactions").
+					GroupBy("department", "region")
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" GROUP BY "department", "region"`},
+				"mysql":     {sql: "SELECT `department`, SUM(`amount`) AS `total_amount` FROM `transactions` GROUP BY `department`, `region`"},
+				"sqlite":    {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" GROUP BY "department", "region"`},
+				"sqlserver": {sql: "SELECT [department], SUM([amount]) AS [total_amount] FROM [transactions] GROUP BY [department], [region]"},
+			},
+		},
+		{
+			name: "Only aggregates",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddAggregate(aggregate.Count, "*", "total").
+					AutoGroupBy().
+					From("transactions")
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT COUNT(*) AS "total" FROM "transactions"`},
+				"mysql":     {sql: "SELECT COUNT(*) AS `total` FROM `transactions`"},
+				"sqlite":    {sql: `SELECT COUNT(*) AS "total" FROM "transactions"`},
+				"sqlserver": {sql: "SELECT COUNT(*) AS [total] FROM [transactions]"},
+			},
+		},
+		{
+			name: "Missing GROUP BY field",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("account_type").
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					From("transactions").
+					GroupBy("account_type")
+			},
+			expectedError: "field department must appear in GROUP BY",
+		},
+		{
+			name: "Aggregate without GROUP BY",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					From("transactions")
+			},
+			expectedError: "field department must appear in GROUP BY",
+		},
+		{
+			name: "GROUP BY on simple select",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("department").
+					From("transactions").
+					GroupBy("department")
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department" FROM "transactions" GROUP BY "department"`},
+				"mysql":     {sql: "SELECT `department` FROM `transactions` GROUP BY `department`"},
+				"sqlite":    {sql: `SELECT "department" FROM "transactions" GROUP BY "department"`},
+				"sqlserver": {sql: "SELECT [department] FROM [transactions] GROUP BY [department]"},
+			},
+		},
+	})
+}
+
+func TestBuilder_Having(t *testing.T) {
+	runDialectCases(t, []dialectCase{
+		{
+			name: "Having by alias after WHERE args",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					From("transactions").
+					Where(fields.NewFieldCondition("is_active", fields.Equals, true)).
+					GroupBy("department").
+					Having(fields.NewFieldCondition("total_amount", fields.GreaterThan, 1000000))
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" WHERE "is_active" = $1 GROUP BY "department" HAVING SUM("amount") > $2`, args: []interface{}{true, 1000000}},
+				"mysql":     {sql: "SELECT `department`, SUM(`amount`) AS `total_amount` FROM `transactions` WHERE `is_active` = ? GROUP BY `department` HAVING SUM(`amount`) > ?", args: []interface{}{true, 1000000}},
+				"sqlite":    {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" WHERE "is_active" = ? GROUP BY "department" HAVING SUM("amount") > ?`, args: []interface{}{true, 1000000}},
+				"sqlserver": {sql: "SELECT [department], SUM([amount]) AS [total_amount] FROM [transactions] WHERE [is_active] = @p1 GROUP BY [department] HAVING SUM([amount]) > @p2", args: []interface{}{true, 1000000}},
+			},
+		},
+		{
+			name: "Having group with expressions",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					AddAggregate(aggregate.Count, "*", "").
+					From("transactions").
+					GroupBy("department").
+					HavingGroup(querybuilder.OR, func(group *querybuilder.WhereGroup) {
+						group.Add(fields.NewFieldCondition("total_amount", fields.LessThan, 0))
+						group.Add(fields.NewUnsafeRawCondition("COUNT(*)", fields.GreaterThan, 100))
+					})
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department", SUM("amount") AS "total_amount", COUNT(*) FROM "transactions" GROUP BY "department" HAVING (SUM("amount") < $1 OR COUNT(*) > $2)`, args: []interface{}{0, 100}},
+				"mysql":     {sql: "SELECT `department`, SUM(`amount`) AS `total_amount`, COUNT(*) FROM `transactions` GROUP BY `department` HAVING (SUM(`amount`) < ? OR COUNT(*) > ?)", args: []interface{}{0, 100}},
+				"sqlite":    {sql: `SELECT "department", SUM("amount") AS "total_amount", COUNT(*) FROM "transactions" GROUP BY "department" HAVING (SUM("amount") < ? OR COUNT(*) > ?)`, args: []interface{}{0, 100}},
+				"sqlserver": {sql: "SELECT [department], SUM([amount]) AS [total_amount], COUNT(*) FROM [transactions] GROUP BY [department] HAVING (SUM([amount]) < @p1 OR COUNT(*) > @p2)", args: []interface{}{0, 100}},
+			},
+		},
+	})
+}
+
+// TestBuilder_HavingRebuild checks that aliases are resolved on each Build
+// without being kept by the builder
+func TestBuilder_HavingRebuild(t *testing.T) {
+	b := sqlbuilder.New(dialect.Postgres)
+	b.SelectAggregate().
+		AddRegularField("department").
+		AddAggregate(aggregate.Sum, "amount", "total_amount").
+		AutoGroupBy().
+		From("transactions").
+		Having(fields.NewFieldCondition("total_amount", fields.GreaterThan, 0))
+
+	expected := `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" GROUP BY "department" HAVING SUM("amount") > $1`
+	for i := 0; i < 2; i++ {
+		sql, _, err := b.Build()
+		require.NoError(t, err)
+		assert.Equal(t, expected, sql)
+	}
+
+	// Without the aggregate select the alias is no longer resolved
+	sql, _, err := b.Select("department").GroupBy("department").Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "department" FROM "transactions" GROUP BY "department" HAVING "total_amount" > $1`, sql)
+}
+
+func TestBuilder_OrderBy(t *testing.T) {
+	runDialectCases(t, []dialectCase{
+		{
+			name: "Order by aggregate alias and field",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					AutoGroupBy().
+					From("transactions").
+					OrderBy(
+						simpleorderby.Alias("total_amount").Desc().NullsLast(),
+						simpleorderby.Field("department").Asc(),
+					)
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" GROUP BY "department" ORDER BY "total_amount" DESC NULLS LAST, "department" ASC`},
+				"mysql":     {sql: "SELECT `department`, SUM(`amount`) AS `total_amount` FROM `transactions` GROUP BY `department` ORDER BY CASE WHEN SUM(`amount`) IS NULL THEN 1 ELSE 0 END, `total_amount` DESC, `department` ASC"},
+				"sqlite":    {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" GROUP BY "department" ORDER BY "total_amount" DESC NULLS LAST, "department" ASC`},
+				"sqlserver": {sql: "SELECT [department], SUM([amount]) AS [total_amount] FROM [transactions] GROUP BY [department] ORDER BY CASE WHEN SUM([amount]) IS NULL THEN 1 ELSE 0 END, [total_amount] DESC, [department] ASC"},
+			},
+		},
+		{
+			name: "NULLS FIRST by aggregate alias",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Max, "posted_at", "last_posted").
+					AutoGroupBy().
+					From("transactions").
+					OrderBy(simpleorderby.Alias("last_posted").Desc().NullsFirst())
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department", MAX("posted_at") AS "last_posted" FROM "transactions" GROUP BY "department" ORDER BY "last_posted" DESC NULLS FIRST`},
+				"mysql":     {sql: "SELECT `department`, MAX(`posted_at`) AS `last_posted` FROM `transactions` GROUP BY `department` ORDER BY CASE WHEN MAX(`posted_at`) IS NULL THEN 0 ELSE 1 END, `last_posted` DESC"},
+				"sqlite":    {sql: `SELECT "department", MAX("posted_at") AS "last_posted" FROM "transactions" GROUP BY "department" ORDER BY "last_posted" DESC NULLS FIRST`},
+				"sqlserver": {sql: "SELECT [department], MAX([posted_at]) AS [last_posted] FROM [transactions] GROUP BY [department] ORDER BY CASE WHEN MAX([posted_at]) IS NULL THEN 0 ELSE 1 END, [last_posted] DESC"},
+			},
+		},
+		{
+			name: "Order by ordinal",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id", "name").
+					From("users").
+					OrderBy(simpleorderby.Ordinal(2))
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "id", "name" FROM "users" ORDER BY 2`},
+				"mysql":     {sql: "SELECT `id`, `name` FROM `users` ORDER BY 2"},
+				"sqlite":    {sql: `SELECT "id", "name" FROM "users" ORDER BY 2`},
+				"sqlserver": {sql: "SELECT [id], [name] FROM [users] ORDER BY 2"},
+			},
+		},
+		{
+			name: "Unknown alias",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					From("transactions").
+					OrderBy(simpleorderby.Alias("total"))
+			},
+			expectedError: "order by alias total does not exist in select clause",
+		},
+		{
+			name: "Ordinal out of range",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id", "name").
+					From("users").
+					OrderBy(simpleorderby.Ordinal(3))
+			},
+			expectedError: "order by position 3 is out of range",
+		},
+	})
+}
+
+func TestBuilder_Pagination(t *testing.T) {
+	token, err := keyset.NewKeyset(
+		[]simpleorderby.OrderKey{simpleorderby.Field("id")},
+		[]interface{}{100},
+	).Cursor()
+	require.NoError(t, err)
+
+	runDialectCases(t, []dialectCase{
+		{
+			name: "Limit and offset after WHERE and HAVING args",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("department").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					AutoGroupBy().
+					From("transactions").
+					Where(fields.NewFieldCondition("is_active", fields.Equals, true)).
+					Having(fields.NewFieldCondition("total_amount", fields.GreaterThan, 0)).
+					OrderBy(simpleorderby.Alias("total_amount").Desc()).
+					Limit(20).
+					Offset(40)
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" WHERE "is_active" = $1 GROUP BY "department" HAVING SUM("amount") > $2 ORDER BY "total_amount" DESC LIMIT $3 OFFSET $4`, args: []interface{}{true, 0, 20, 40}},
+				"mysql":     {sql: "SELECT `department`, SUM(`amount`) AS `total_amount` FROM `transactions` WHERE `is_active` = ? GROUP BY `department` HAVING SUM(`amount`) > ? ORDER BY `total_amount` DESC LIMIT ? OFFSET ?", args: []interface{}{true, 0, 20, 40}},
+				"sqlite":    {sql: `SELECT "department", SUM("amount") AS "total_amount" FROM "transactions" WHERE "is_active" = ? GROUP BY "department" HAVING SUM("amount") > ? ORDER BY "total_amount" DESC LIMIT ? OFFSET ?`, args: []interface{}{true, 0, 20, 40}},
+				"sqlserver": {sql: "SELECT [department], SUM([amount]) AS [total_amount] FROM [transactions] WHERE [is_active] = @p1 GROUP BY [department] HAVING SUM([amount]) > @p2 ORDER BY [total_amount] DESC OFFSET @p3 ROWS FETCH NEXT @p4 ROWS ONLY", args: []interface{}{true, 0, 40, 20}},
+			},
+		},
+		{
+			name: "Seek without WHERE",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id", "posted_at").
+					From("transactions").
+					Seek(keyset.NewKeyset(
+						[]simpleorderby.OrderKey{simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("id").Desc()},
+						[]interface{}{"2024-03-01", 10},
+					)).
+					Limit(50)
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "id", "posted_at" FROM "transactions" WHERE ("posted_at", "id") < ($1, $2) ORDER BY "posted_at" DESC, "id" DESC LIMIT $3`, args: []interface{}{"2024-03-01", 10, 50}},
+				"mysql":     {sql: "SELECT `id`, `posted_at` FROM `transactions` WHERE (`posted_at`, `id`) < (?, ?) ORDER BY `posted_at` DESC, `id` DESC LIMIT ?", args: []interface{}{"2024-03-01", 10, 50}},
+				"sqlite":    {sql: `SELECT "id", "posted_at" FROM "transactions" WHERE ("posted_at", "id") < (?, ?) ORDER BY "posted_at" DESC, "id" DESC LIMIT ?`, args: []interface{}{"2024-03-01", 10, 50}},
+				"sqlserver": {sql: "SELECT [id], [posted_at] FROM [transactions] WHERE ([posted_at] < @p1 OR ([posted_at] = @p2 AND [id] < @p3)) ORDER BY [posted_at] DESC, [id] DESC OFFSET 0 ROWS FETCH NEXT @p4 ROWS ONLY", args: []interface{}{"2024-03-01", "2024-03-01", 10, 50}},
+			},
+		},
+		{
+			name: "Seek combined with WHERE from cursor",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				ks, err := keyset.DecodeCursor(token)
+				require.NoError(t, err)
+				return b.Select("id").
+					From("transactions").
+					WhereGroup(querybuilder.OR, func(group *querybuilder.WhereGroup) {
+						group.Add(fields.NewFieldCondition("status", fields.Equals, "OPEN"))
+						group.Add(fields.NewFieldCondition("status", fields.Equals, "HELD"))
+					}).
+					Seek(ks).
+					Limit(10)
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "id" FROM "transactions" WHERE (("status" = $1 OR "status" = $2)) AND "id" > $3 ORDER BY "id" LIMIT $4`, args: []interface{}{"OPEN", "HELD", 100, 10}},
+				"mysql":     {sql: "SELECT `id` FROM `transactions` WHERE ((`status` = ? OR `status` = ?)) AND `id` > ? ORDER BY `id` LIMIT ?", args: []interface{}{"OPEN", "HELD", 100, 10}},
+				"sqlite":    {sql: `SELECT "id" FROM "transactions" WHERE (("status" = ? OR "status" = ?)) AND "id" > ? ORDER BY "id" LIMIT ?`, args: []interface{}{"OPEN", "HELD", 100, 10}},
+				"sqlserver": {sql: "SELECT [id] FROM [transactions] WHERE (([status] = @p1 OR [status] = @p2)) AND [id] > @p3 ORDER BY [id] OFFSET 0 ROWS FETCH NEXT @p4 ROWS ONLY", args: []interface{}{"OPEN", "HELD", 100, 10}},
+			},
+		},
+		{
+			name: "Seek without keyset",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id").From("transactions").Seek(nil).Limit(10)
+			},
+			expectedError: "seek requires a keyset",
+		},
+		{
+			name: "Seek against the order direction",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id").
+					From("transactions").
+					OrderBy(simpleorderby.Field("id").Desc()).
+					Seek(keyset.NewKeyset([]simpleorderby.OrderKey{simpleorderby.Field("id")}, []interface{}{100})).
+					Limit(10)
+			},
+			expectedError: "keyset key 1 (id ASC) does not match order by key id DESC",
+		},
+		{
+			name: "Seek on keys after the order",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("id").
+					From("transactions").
+					OrderBy(simpleorderby.Field("posted_at").Desc(), simpleorderby.Field("id").Desc()).
+					Seek(keyset.NewKeyset([]simpleorderby.OrderKey{simpleorderby.Field("id").Desc()}, []interface{}{100})).
+					Limit(10)
+			},
+			expectedError: "keyset key 1 (id DESC) does not match order by key posted_at DESC",
+		},
+	})
+}
+
+func TestBuilder_Join(t *testing.T) {
+	runDialectCases(t, []dialectCase{
+		{
+			name: "Join after From keeps table",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.SelectAggregate().
+					AddRegularField("coa.coadescription").
+					AddAggregate(aggregate.Sum, "amount", "total_amount").
+					AutoGroupBy().
+					From("transactions").
+					Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+						fields.NewColumnCondition("transactions.account_code", fields.Equals, "coa.account_code"))
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "coa"."coadescription", SUM("amount") AS "total_amount" FROM "transactions" LEFT JOIN "chart_of_accounts" AS "coa" ON "transactions"."account_code" = "coa"."account_code" GROUP BY "coa"."coadescription"`},
+				"mysql":     {sql: "SELECT `coa`.`coadescription`, SUM(`amount`) AS `total_amount` FROM `transactions` LEFT JOIN `chart_of_accounts` AS `coa` ON `transactions`.`account_code` = `coa`.`account_code` GROUP BY `coa`.`coadescription`"},
+				"sqlite":    {sql: `SELECT "coa"."coadescription", SUM("amount") AS "total_amount" FROM "transactions" LEFT JOIN "chart_of_accounts" AS "coa" ON "transactions"."account_code" = "coa"."account_code" GROUP BY "coa"."coadescription"`},
+				"sqlserver": {sql: "SELECT [coa].[coadescription], SUM([amount]) AS [total_amount] FROM [transactions] LEFT JOIN [chart_of_accounts] AS [coa] ON [transactions].[account_code] = [coa].[account_code] GROUP BY [coa].[coadescription]"},
+			},
+		},
+		{
+			name: "Join parameters come before WHERE parameters",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Select("t.id", "r.rate").
+					FromAs("transactions", "t").
+					WhereGroup(querybuilder.AND, func(group *querybuilder.WhereGroup) {
+						group.Add(fields.NewFieldCondition("t.amount", fields.GreaterThan, 100))
+					}).
+					Join(querybuilder.InnerJoin, "fx_rates", "r",
+						fields.NewFieldCondition("r.rate_type", fields.Equals, "SPOT")).
+					JoinUsing(querybuilder.LeftJoin, "departments", "", "department_id")
+			},
+			expected: map[string]expectation{
+				"postgres":  {sql: `SELECT "t"."id", "r"."rate" FROM "transactions" AS "t" INNER JOIN "fx_rates" AS "r" ON "r"."rate_type" = $1 LEFT JOIN "departments" USING ("department_id") WHERE "t"."amount" > $2`, args: []interface{}{"SPOT", 100}},
+				"mysql":     {sql: "SELECT `t`.`id`, `r`.`rate` FROM `transactions` AS `t` INNER JOIN `fx_rates` AS `r` ON `r`.`rate_type` = ? LEFT JOIN `departments` USING (`department_id`) WHERE `t`.`amount` > ?", args: []interface{}{"SPOT", 100}},
+				"sqlite":    {sql: `SELECT "t"."id", "r"."rate" FROM "transactions" AS "t" INNER JOIN "fx_rates" AS "r" ON "r"."rate_type" = ? LEFT JOIN "departments" USING ("department_id") WHERE "t"."amount" > ?`, args: []interface{}{"SPOT", 100}},
+				"sqlserver": {err: "USING is not supported by sqlserver"},
+			},
+		},
+		{
+			name: "From after Join",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.From("transactions").
+					Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+						fields.NewColumnCondition("transactions.account_code", fields.Equals, "coa.account_code")).
+					From("ledger")
+			},
+			expectedError: "FROM ledger set after a join would drop the joins",
+		},
+		{
+			name: "FromAs after JoinUsing",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.FromAs("transactions", "t").
+					JoinUsing(querybuilder.InnerJoin, "departments", "", "department_id").
+					FromAs("ledger", "l")
+			},
+			expectedError: "FROM ledger AS l set after a join would drop the joins",
+		},
+		{
+			name: "Join before From",
+			build: func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+				return b.Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+					fields.NewColumnCondition("transactions.account_code", fields.Equals, "coa.account_code")).
+					From("transactions")
+			},
+			expectedError: "JOIN chart_of_accounts has no table to join to",
+		},
+	})
+}
+
+func TestBuilder_Registry(t *testing.T) {
+	registry := schema.NewRegistry().
+		Register("transactions",
+			schema.NewColumn("account_code", schema.Text),
+			schema.NewColumn("department", schema.Text),
+			schema.NewColumn("amount", schema.Numeric),
+		).
+		Register("chart_of_accounts",
+			schema.NewColumn("account_code", schema.Text),
+			schema.NewColumn("coadescription", schema.Text),
+		)
+	withRegistry := func(b querybuilder.QueryBuilder) querybuilder.QueryBuilder {
+		return b.(*sqlbuilder.Builder).WithRegistry(registry)
+	}
+
+	for _, builder := range builders {
+		t.Run("Valid query/"+builder.name, func(t *testing.T) {
+			_, _, err := withRegistry(builder.new()).SelectAggregate().
+				AddRegularField("coa.coadescription").
+				AddAggregate(aggregate.Sum, "t.amount", "total_amount").
+				AutoGroupBy().
+				FromAs("transactions", "t").
+				Join(querybuilder.LeftJoin, "chart_of_accounts", "coa",
+					fields.NewColumnCondition("t.account_code", fields.Equals, "coa.account_code")).
+				Where(fields.NewFieldCondition("t.department", fields.In, []interface{}{"FIN", "OPS"})).
+				Having(fields.NewFieldCondition("total_amount", fields.GreaterThan, 0)).
+				OrderBy(simpleorderby.Alias("total_amount").Desc(), simpleorderby.Field("coa.coadescription")).
+				Build()
+			assert.NoError(t, err)
+		})
+
+		t.Run("Offending fields are reported/"+builder.name, func(t *testing.T) {
+			_, _, err := withRegistry(builder.new()).SelectAggregate().
+				AddRegularField("department").
+				AddAggregate(aggregate.Sum, "department", "total").
+				From("transactions").
+				Where(fields.NewFieldCondition("salary", fields.GreaterThan, 0)).
+				GroupBy("department").
+				OrderBy(simpleorderby.UnsafeRaw("random()")).
+				Build()
+			require.Error(t, err)
+
+			var fieldErrors schema.FieldErrors
+			require.True(t, errors.As(err, &fieldErrors))
+			require.Len(t, fieldErrors, 3)
+			assert.Equal(t, schema.AggregateNotAllowed, fieldErrors[0].Kind)
+			assert.Equal(t, "department", fieldErrors[0].Field)
+			assert.Equal(t, schema.UnknownColumn, fieldErrors[1].Kind)
+			assert.Equal(t, "salary", fieldErrors[1].Field)
+			assert.Equal(t, schema.RawExpressionBlocked, fieldErrors[2].Kind)
+		})
+
+		t.Run("Unknown table/"+builder.name, func(t *testing.T) {
+			_, _, err := withRegistry(builder.new()).
+				Select("id").
+				From("users").
+				Build()
+			assert.ErrorContains(t, err, "unknown table users")
+		})
+	}
+
+	t.Run("Folded names", func(t *testing.T) {
+		_, _, err := withRegistry(sqlbuilder.New(dialect.PostgresFolded)).
+			Select("Department").
+			From("Transactions").
+			Build()
+		assert.NoError(t, err)
+
+		_, _, err = withRegistry(sqlbuilder.New(dialect.Postgres)).
+			Select("Department").
+			From("Transactions").
+			Build()
+		assert.ErrorContains(t, err, "unknown table Transactions")
+	})
+}
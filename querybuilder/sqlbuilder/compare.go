@@ -0,0 +1,28 @@
+package sqlbuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+)
+
+// ComparePeriods turns the aggregate select into a period-over-period report
+// in a single query. Every aggregate becomes a current_<alias> column over the
+// current range and a comparison_<alias> column over the derived comparison
+// range, and the rows are restricted to the two ranges:
+//
+//	SELECT department,
+//	  SUM(amount) FILTER (WHERE <current>) AS current_total,
+//	  SUM(amount) FILTER (WHERE <comparison>) AS comparison_total
+//	FROM ... WHERE (<current> OR <comparison>) GROUP BY department
+//
+// Like AddAggregate it needs SelectAggregate to be called first.
+func (b *Builder) ComparePeriods(current *daterange.DateRangeCondition, comparison daterange.Comparison) *Builder {
+	previous := current.CompareTo(comparison)
+	if b.aggregateSelect != nil {
+		b.aggregateSelect.WithComparison(current, previous)
+	}
+	b.WhereGroup(querybuilder.AND, func(wg *querybuilder.WhereGroup) {
+		wg.Add(querybuilder.NewWhereGroup(querybuilder.OR).Add(current).Add(previous))
+	})
+	return b
+}
@@ -0,0 +1,102 @@
+package sqlbuilder_test
+
+import (
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	aggregate "dynamic-sqlbuilder/querybuilder/select/aggregateselect"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_ComparePeriods(t *testing.T) {
+	jakarta, err := time.LoadLocation(daterange.DefaultTimezone)
+	require.NoError(t, err)
+	asOf := daterange.WithAsOf(time.Date(2024, 5, 15, 0, 0, 0, 0, jakarta))
+
+	tests := []struct {
+		name     string
+		dialect  dialect.Dialect
+		expected expectation
+	}{
+		{
+			name:    "postgres",
+			dialect: dialect.Postgres,
+			expected: expectation{
+				sql: `SELECT "department", ` +
+					`SUM("amount") FILTER (WHERE "period_year" = $1 AND "period_month" BETWEEN $2 AND $3) AS "current_total", ` +
+					`SUM("amount") FILTER (WHERE "period_year" = $4 AND "period_month" BETWEEN $5 AND $6) AS "comparison_total", ` +
+					`COUNT(*) FILTER (WHERE "period_year" = $7 AND "period_month" BETWEEN $8 AND $9) AS "current_orders", ` +
+					`COUNT(*) FILTER (WHERE "period_year" = $10 AND "period_month" BETWEEN $11 AND $12) AS "comparison_orders" ` +
+					`FROM "transactions" ` +
+					`WHERE ("period_year" = $13 AND "period_month" BETWEEN $14 AND $15 OR "period_year" = $16 AND "period_month" BETWEEN $17 AND $18) ` +
+					`AND "is_active" = $19 ` +
+					`GROUP BY "department"`,
+				args: []interface{}{
+					2024, 1, 5, 2023, 1, 5,
+					2024, 1, 5, 2023, 1, 5,
+					2024, 1, 5, 2023, 1, 5,
+					true,
+				},
+			},
+		},
+		{
+			name:    "mysql",
+			dialect: dialect.MySQL,
+			expected: expectation{
+				sql: "SELECT `department`, " +
+					"SUM(CASE WHEN `period_year` = ? AND `period_month` BETWEEN ? AND ? THEN `amount` END) AS `current_total`, " +
+					"SUM(CASE WHEN `period_year` = ? AND `period_month` BETWEEN ? AND ? THEN `amount` END) AS `comparison_total`, " +
+					"COUNT(CASE WHEN `period_year` = ? AND `period_month` BETWEEN ? AND ? THEN 1 END) AS `current_orders`, " +
+					"COUNT(CASE WHEN `period_year` = ? AND `period_month` BETWEEN ? AND ? THEN 1 END) AS `comparison_orders` " +
+					"FROM `transactions` " +
+					"WHERE (`period_year` = ? AND `period_month` BETWEEN ? AND ? OR `period_year` = ? AND `period_month` BETWEEN ? AND ?) " +
+					"AND `is_active` = ? " +
+					"GROUP BY `department`",
+				args: []interface{}{
+					2024, 1, 5, 2023, 1, 5,
+					2024, 1, 5, 2023, 1, 5,
+					2024, 1, 5, 2023, 1, 5,
+					true,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := daterange.NewDateRangeCondition(daterange.DateConfig{Type: daterange.YTD}, asOf)
+
+			b := sqlbuilder.New(tt.dialect)
+			b.SelectAggregate().
+				AddRegularField("department").
+				AddAggregate(aggregate.Sum, "amount", "total").
+				AddAggregate(aggregate.Count, "*", "orders").
+				AutoGroupBy().
+				From("transactions")
+			b.ComparePeriods(current, daterange.Comparison{Type: daterange.SamePeriodLastYear}).
+				And().
+				Where(fields.NewFieldCondition("is_active", fields.Equals, true))
+
+			sql, args, err := b.Build()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.sql, sql)
+			assert.Equal(t, tt.expected.args, args)
+		})
+	}
+}
+
+func TestBuilder_ComparePeriodsNeedsAlias(t *testing.T) {
+	current := daterange.NewDateRangeCondition(daterange.DateConfig{Type: daterange.CurrentMonth})
+
+	b := sqlbuilder.New(dialect.Postgres)
+	b.SelectAggregate().AddAggregate(aggregate.Sum, "amount", "").From("transactions")
+	b.ComparePeriods(current, daterange.Comparison{Type: daterange.PreviousPeriod})
+
+	_, _, err := b.Build()
+	assert.ErrorContains(t, err, "aggregate SUM(amount) needs an alias to be compared")
+}
@@ -0,0 +1,49 @@
+package sqlbuilder
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger makes Build report what it does to logger at debug level: a
+// "clause built" event per clause with its args count and duration, then a
+// "query built" or "query failed" event for the whole query. Bound values are
+// never logged. Without a logger Build is silent.
+func (b *Builder) WithLogger(logger *slog.Logger) *Builder {
+	b.logger = logger
+	return b
+}
+
+// logClause reports a clause that was added to the query
+func (b *Builder) logClause(clause string, started time.Time, args int) {
+	if b.logger == nil {
+		return
+	}
+	b.logger.LogAttrs(context.Background(), slog.LevelDebug, "clause built",
+		slog.String("dialect", b.dialect.Name()),
+		slog.String("clause", clause),
+		slog.Int("args", args),
+		slog.Duration("duration", time.Since(started)),
+	)
+}
+
+// logQuery reports the outcome of Build
+func (b *Builder) logQuery(started time.Time, args int, err error) {
+	if b.logger == nil {
+		return
+	}
+	if err != nil {
+		b.logger.LogAttrs(context.Background(), slog.LevelDebug, "query failed",
+			slog.String("dialect", b.dialect.Name()),
+			slog.Duration("duration", time.Since(started)),
+			slog.Any("error", err),
+		)
+		return
+	}
+	b.logger.LogAttrs(context.Background(), slog.LevelDebug, "query built",
+		slog.String("dialect", b.dialect.Name()),
+		slog.Int("args", args),
+		slog.Duration("duration", time.Since(started)),
+	)
+}
@@ -0,0 +1,89 @@
+package sqlbuilder_test
+
+import (
+	"context"
+	"dynamic-sqlbuilder/querybuilder/condition/daterange"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler keeps the events a builder logs, without their durations
+type recordingHandler struct {
+	events []string
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	event := record.Message
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key != "duration" {
+			event += " " + attr.String()
+		}
+		return true
+	})
+	h.events = append(h.events, event)
+	return nil
+}
+
+func TestBuilder_WithLogger(t *testing.T) {
+	handler := &recordingHandler{}
+	b := sqlbuilder.New(dialect.Postgres).WithLogger(slog.New(handler))
+	b.From("transactions").
+		Where(fields.NewFieldCondition("department", fields.Equals, "FIN")).
+		OrderBy(simpleorderby.Field("amount").Desc()).
+		Limit(10)
+
+	_, _, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"clause built dialect=postgres clause=SELECT args=0",
+		"clause built dialect=postgres clause=FROM args=0",
+		"clause built dialect=postgres clause=WHERE args=1",
+		"clause built dialect=postgres clause=ORDER BY args=0",
+		"clause built dialect=postgres clause=LIMIT args=1",
+		"query built dialect=postgres args=2",
+	}, handler.events)
+}
+
+func TestBuilder_WithLoggerFailure(t *testing.T) {
+	handler := &recordingHandler{}
+	b := sqlbuilder.New(dialect.Postgres).WithLogger(slog.New(handler))
+	b.From("transactions").Where(fields.NewFieldCondition("bad name", fields.Equals, 1))
+
+	_, _, err := b.Build()
+	require.Error(t, err)
+	require.Len(t, handler.events, 3)
+	assert.Equal(t, "query failed dialect=postgres error="+err.Error(), handler.events[2])
+}
+
+func TestBuilder_SilentByDefault(t *testing.T) {
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	b := sqlbuilder.New(dialect.Postgres)
+	b.From("transactions").Where(daterange.NewDateRangeCondition(daterange.DateConfig{Type: daterange.YTD}))
+	_, _, err = b.Build()
+	require.NoError(t, err)
+	_, _, err = sqlbuilder.New(dialect.Postgres).From("transactions").Build()
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Close())
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Empty(t, string(output))
+}
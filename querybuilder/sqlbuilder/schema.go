@@ -0,0 +1,92 @@
+package sqlbuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/from/joinfrom"
+	"dynamic-sqlbuilder/querybuilder/from/simplefrom"
+	"dynamic-sqlbuilder/querybuilder/identifier"
+	"dynamic-sqlbuilder/querybuilder/orderby/simpleorderby"
+	"dynamic-sqlbuilder/querybuilder/schema"
+	"dynamic-sqlbuilder/querybuilder/select/simpleselect"
+)
+
+// WithRegistry makes Build validate every table, field, operator and aggregate
+// against the registry. Failures are returned as schema.FieldErrors.
+func (b *Builder) WithRegistry(registry *schema.Registry) *Builder {
+	b.registry = registry
+	return b
+}
+
+// validateSchema checks the whole query against the registry
+func (b *Builder) validateSchema() error {
+	scope := b.registry.NewDialectScope(b.dialect)
+
+	// FROM and JOIN tables come first so every clause can resolve them
+	switch from := b.query.FromClause.(type) {
+	case *simplefrom.SimpleFrom:
+		scope.AddTable(schema.ClauseFrom, schema.TableRef{Name: from.Table})
+	case *joinfrom.JoinFrom:
+		scope.AddTable(schema.ClauseFrom, schema.TableRef{Name: from.Table, Alias: from.Alias})
+		for _, join := range from.Joins {
+			scope.AddTable(schema.ClauseJoin, schema.TableRef{Name: join.Table, Alias: join.Alias})
+		}
+		for _, join := range from.Joins {
+			if join.On != nil {
+				scope.CheckCondition(schema.ClauseJoin, join.On)
+			}
+			for _, column := range join.Using {
+				scope.CheckColumnIn(schema.ClauseJoin, schema.TableRef{Name: from.Table}, column)
+				scope.CheckColumnIn(schema.ClauseJoin, schema.TableRef{Name: join.Table}, column)
+			}
+		}
+	}
+
+	// SELECT
+	if b.aggregateSelect != nil {
+		for _, field := range b.aggregateSelect.RegularFields() {
+			scope.CheckField(schema.ClauseSelect, field)
+		}
+		for _, agg := range b.aggregateSelect.Aggregates() {
+			scope.CheckAggregate(schema.ClauseSelect, agg)
+		}
+	} else if simpleSelect, ok := b.query.SelectClause.(*simpleselect.SimpleSelect); ok {
+		for _, field := range simpleSelect.SourceFields() {
+			scope.CheckField(schema.ClauseSelect, field)
+		}
+	}
+	if columns, ok := b.query.SelectClause.(querybuilder.SelectColumns); ok {
+		scope.AddAliases(columns.Aliases()...)
+	}
+
+	// WHERE
+	scope.CheckCondition(schema.ClauseWhere, b.whereGroups)
+	if b.seek != nil {
+		scope.CheckCondition(schema.ClauseWhere, b.seek)
+	}
+
+	// GROUP BY
+	if b.groupBy != nil {
+		for _, field := range b.groupBy.Fields {
+			scope.CheckField(schema.ClauseGroupBy, field)
+		}
+	}
+
+	// HAVING
+	if b.having != nil {
+		scope.CheckCondition(schema.ClauseHaving, b.having)
+	}
+
+	// ORDER BY, alias keys are validated against the select list when building
+	if b.orderBy != nil {
+		for _, key := range b.orderBy.Keys {
+			switch key.Kind {
+			case simpleorderby.FieldKey:
+				scope.CheckField(schema.ClauseOrderBy, identifier.Name(key.Field))
+			case simpleorderby.RawKey:
+				scope.CheckField(schema.ClauseOrderBy, identifier.UnsafeRaw(key.Field))
+			}
+		}
+	}
+
+	return scope.Err()
+}
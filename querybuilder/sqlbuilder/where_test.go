@@ -0,0 +1,185 @@
+package sqlbuilder_test
+
+import (
+	"dynamic-sqlbuilder/querybuilder"
+	"dynamic-sqlbuilder/querybuilder/condition/fields"
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainStep is one call of a WHERE chain, conditions are numbered in call order
+type chainStep struct {
+	name  string
+	apply func(b querybuilder.QueryBuilder, next func() querybuilder.QueryCondition)
+}
+
+var (
+	where = chainStep{"Where", func(b querybuilder.QueryBuilder, next func() querybuilder.QueryCondition) {
+		b.Where(next())
+	}}
+	and = chainStep{"And", func(b querybuilder.QueryBuilder, _ func() querybuilder.QueryCondition) {
+		b.And()
+	}}
+	or = chainStep{"Or", func(b querybuilder.QueryBuilder, _ func() querybuilder.QueryCondition) {
+		b.Or()
+	}}
+	andGroup = chainStep{"WhereGroup(AND)", func(b querybuilder.QueryBuilder, next func() querybuilder.QueryCondition) {
+		b.WhereGroup(querybuilder.AND, func(wg *querybuilder.WhereGroup) {
+			wg.Add(next())
+		})
+	}}
+	orGroup = chainStep{"WhereGroup(OR)", func(b querybuilder.QueryBuilder, next func() querybuilder.QueryCondition) {
+		b.WhereGroup(querybuilder.OR, func(wg *querybuilder.WhereGroup) {
+			wg.Add(next()).Add(next())
+		})
+	}}
+	emptyGroup = chainStep{"WhereGroup()", func(b querybuilder.QueryBuilder, _ func() querybuilder.QueryCondition) {
+		b.WhereGroup(querybuilder.AND, func(*querybuilder.WhereGroup) {})
+	}}
+)
+
+// buildChain applies the steps to a new builder and returns it with the
+// number of conditions added
+func buildChain(steps []chainStep) (*sqlbuilder.Builder, int) {
+	b := sqlbuilder.New(dialect.Postgres)
+	b.From("t")
+	count := 0
+	next := func() querybuilder.QueryCondition {
+		count++
+		return fields.NewFieldCondition(fmt.Sprintf("c%d", count), fields.Equals, count)
+	}
+	for _, step := range steps {
+		step.apply(b, next)
+	}
+	return b, count
+}
+
+func chainName(steps []chainStep) string {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.name
+	}
+	return strings.Join(names, ".")
+}
+
+func TestBuilder_WhereChains(t *testing.T) {
+	tests := []struct {
+		steps    []chainStep
+		expected string
+	}{
+		{steps: []chainStep{where}, expected: `"c1" = $1`},
+		{steps: []chainStep{where, where}, expected: `("c1" = $1 AND "c2" = $2)`},
+		{steps: []chainStep{where, where, where}, expected: `("c1" = $1 AND "c2" = $2 AND "c3" = $3)`},
+		{steps: []chainStep{where, and, where}, expected: `"c1" = $1 AND "c2" = $2`},
+		{steps: []chainStep{where, or, where}, expected: `"c1" = $1 OR "c2" = $2`},
+		{steps: []chainStep{where, and, where, where}, expected: `"c1" = $1 AND ("c2" = $2 AND "c3" = $3)`},
+		{steps: []chainStep{where, or, where, where}, expected: `"c1" = $1 OR ("c2" = $2 OR "c3" = $3)`},
+		{steps: []chainStep{where, or, where, and, where}, expected: `("c1" = $1 OR "c2" = $2) AND "c3" = $3`},
+		{steps: []chainStep{and, where}, expected: `"c1" = $1`},
+		{steps: []chainStep{or, where, where}, expected: `("c1" = $1 OR "c2" = $2)`},
+		{steps: []chainStep{or, and, where}, expected: `"c1" = $1`},
+		{steps: []chainStep{where, and, and, where}, expected: `"c1" = $1 AND "c2" = $2`},
+		{steps: []chainStep{where, or}, expected: `"c1" = $1`},
+		{steps: []chainStep{orGroup, where}, expected: `("c1" = $1 OR "c2" = $2) AND "c3" = $3`},
+		{steps: []chainStep{orGroup, where, where}, expected: `("c1" = $1 OR "c2" = $2) AND ("c3" = $3 AND "c4" = $4)`},
+		{steps: []chainStep{where, orGroup}, expected: `"c1" = $1 OR ("c2" = $2 OR "c3" = $3)`},
+		{steps: []chainStep{where, orGroup, where}, expected: `("c1" = $1 OR ("c2" = $2 OR "c3" = $3)) AND "c4" = $4`},
+		{steps: []chainStep{andGroup, or, where}, expected: `"c1" = $1 OR "c2" = $2`},
+		{steps: []chainStep{andGroup, andGroup}, expected: `"c1" = $1 AND "c2" = $2`},
+		{steps: []chainStep{where, emptyGroup, where}, expected: `"c1" = $1 AND "c2" = $2`},
+		{steps: []chainStep{emptyGroup, or, where}, expected: `"c1" = $1`},
+		{steps: []chainStep{and, or}, expected: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(chainName(tt.steps), func(t *testing.T) {
+			b, count := buildChain(tt.steps)
+
+			sql, args, err := b.Build()
+			require.NoError(t, err)
+			expected := `SELECT * FROM "t"`
+			if tt.expected != "" {
+				expected += " WHERE " + tt.expected
+			}
+			assert.Equal(t, expected, sql)
+			assert.Len(t, args, count)
+		})
+	}
+}
+
+// TestBuilder_WhereChainsExhaustive runs every chain of up to five steps and
+// checks that each condition is built exactly once, in call order, and that
+// building again gives the same query
+func TestBuilder_WhereChainsExhaustive(t *testing.T) {
+	steps := []chainStep{where, and, or, andGroup, orGroup, emptyGroup}
+	column := regexp.MustCompile(`"c(\d+)" = \$(\d+)`)
+
+	var chains [][]chainStep
+	chains = append(chains, nil)
+	for length := 1; length <= 5; length++ {
+		for _, chain := range chains {
+			if len(chain) != length-1 {
+				continue
+			}
+			for _, step := range steps {
+				chains = append(chains, append(append([]chainStep{}, chain...), step))
+			}
+		}
+	}
+
+	for _, chain := range chains {
+		b, count := buildChain(chain)
+		name := chainName(chain)
+
+		sql, args, err := b.Build()
+		require.NoError(t, err, name)
+
+		matches := column.FindAllStringSubmatch(sql, -1)
+		require.Len(t, matches, count, "%s: %s", name, sql)
+		for i, match := range matches {
+			position := fmt.Sprint(i + 1)
+			assert.Equal(t, position, match[1], "%s: %s", name, sql)
+			assert.Equal(t, position, match[2], "%s: %s", name, sql)
+			assert.Equal(t, i+1, args[i], name)
+		}
+		assert.NotContains(t, sql, "WHERE AND", name)
+		assert.NotContains(t, sql, "WHERE OR", name)
+		assert.NotContains(t, sql, "()", name)
+		assert.LessOrEqual(t, len(topLevelOperators(sql)), 1, "%s mixes AND and OR without parentheses: %s", name, sql)
+
+		again, _, err := b.Build()
+		require.NoError(t, err, name)
+		assert.Equal(t, sql, again, name)
+	}
+}
+
+// topLevelOperators returns the logical operators outside any parentheses
+func topLevelOperators(sql string) map[string]bool {
+	operators := map[string]bool{}
+	depth := 0
+	for i, r := range sql {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ' ':
+			if depth > 0 {
+				continue
+			}
+			for _, operator := range []string{" AND ", " OR "} {
+				if strings.HasPrefix(sql[i:], operator) {
+					operators[operator] = true
+				}
+			}
+		}
+	}
+	return operators
+}
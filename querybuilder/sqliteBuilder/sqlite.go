@@ -0,0 +1,13 @@
+package sqlitebuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+)
+
+// SQLiteQueryBuilder builds SQLite queries with ? placeholders
+type SQLiteQueryBuilder = sqlbuilder.Builder
+
+func NewSQLiteQueryBuilder() *SQLiteQueryBuilder {
+	return sqlbuilder.New(dialect.SQLite)
+}
@@ -0,0 +1,14 @@
+package sqlserverbuilder
+
+import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
+	"dynamic-sqlbuilder/querybuilder/sqlbuilder"
+)
+
+// SQLServerQueryBuilder builds SQL Server queries with @pN placeholders,
+// bracket quoting and TOP / OFFSET ... FETCH row limiting
+type SQLServerQueryBuilder = sqlbuilder.Builder
+
+func NewSQLServerQueryBuilder() *SQLServerQueryBuilder {
+	return sqlbuilder.New(dialect.SQLServer)
+}
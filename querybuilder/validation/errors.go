@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorKind classifies a validation failure, the packages that validate
+// declare their own kinds
+type ErrorKind string
+
+// FieldError identifies the field that failed validation and, for query
+// validation, the clause it was found in
+type FieldError struct {
+	Kind   ErrorKind `json:"kind"`
+	Clause string    `json:"clause,omitempty"` // e.g. SELECT, WHERE, HAVING, empty for config errors
+	Field  string    `json:"field"`            // Offending table, column or expression, or the JSON path of a config field
+	Detail string    `json:"detail,omitempty"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Clause == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Detail)
+	}
+	message := fmt.Sprintf("%s: %s %s", e.Clause, strings.ToLower(strings.ReplaceAll(string(e.Kind), "_", " ")), e.Field)
+	if e.Detail != "" {
+		message = fmt.Sprintf("%s (%s)", message, e.Detail)
+	}
+	return message
+}
+
+// FieldErrors collects every failure found in a query or config
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,33 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldErrors_Error(t *testing.T) {
+	errs := FieldErrors{
+		{Kind: "UNKNOWN_COLUMN", Clause: "WHERE", Field: "users.password"},
+		{Kind: "OPERATOR_NOT_ALLOWED", Clause: "WHERE", Field: "users.name", Detail: "> is not allowed"},
+		{Kind: "MISSING_PARAMETER", Field: "parameters.months", Detail: "months parameter required for BACK_MONTH"},
+	}
+
+	assert.EqualError(t, errs, "WHERE: unknown column users.password; "+
+		"WHERE: operator not allowed users.name (> is not allowed); "+
+		"parameters.months: months parameter required for BACK_MONTH")
+}
+
+func TestFieldErrors_JSON(t *testing.T) {
+	errs := FieldErrors{
+		{Kind: "UNKNOWN_COLUMN", Clause: "WHERE", Field: "users.password"},
+		{Kind: "MISSING_PARAMETER", Field: "parameters.months", Detail: "months parameter required for BACK_MONTH"},
+	}
+
+	data, err := json.Marshal(errs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"kind": "UNKNOWN_COLUMN", "clause": "WHERE", "field": "users.password"},
+		{"kind": "MISSING_PARAMETER", "field": "parameters.months", "detail": "months parameter required for BACK_MONTH"}]`, string(data))
+}
@@ -1,6 +1,7 @@
 package querybuilder
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
 	"fmt"
 	"strings"
 )
@@ -13,11 +14,11 @@ const (
 	OR  LogicalOperator = "OR"
 )
 
-// WhereGroup represents a group of conditions with a logical operator
+// WhereGroup represents a group of conditions with a logical operator. In a
+// WHERE clause the operator also joins the group to the previous one.
 type WhereGroup struct {
 	Conditions []QueryCondition
 	Operator   LogicalOperator
-	IsNew      bool // Track if this group has been added to whereGroups
 }
 
 // NewWhereGroup creates a new where group with specified operator
@@ -25,7 +26,6 @@ func NewWhereGroup(operator LogicalOperator) *WhereGroup {
 	return &WhereGroup{
 		Conditions: make([]QueryCondition, 0),
 		Operator:   operator,
-		IsNew:      true,
 	}
 }
 
@@ -36,7 +36,7 @@ func (wg *WhereGroup) Add(condition QueryCondition) *WhereGroup {
 }
 
 // Build implements QueryCondition interface
-func (wg *WhereGroup) Build(paramOffset int) (string, []interface{}, error) {
+func (wg *WhereGroup) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
 	if len(wg.Conditions) == 0 {
 		return "", nil, nil
 	}
@@ -45,7 +45,7 @@ func (wg *WhereGroup) Build(paramOffset int) (string, []interface{}, error) {
 	var args []interface{}
 
 	for _, cond := range wg.Conditions {
-		clause, condArgs, err := cond.Build(paramOffset + len(args))
+		clause, condArgs, err := cond.Build(d, paramOffset+len(args))
 		if err != nil {
 			return "", nil, err
 		}
@@ -1,6 +1,7 @@
 package querybuilder
 
 import (
+	"dynamic-sqlbuilder/querybuilder/dialect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,7 +14,7 @@ type MockCondition struct {
 	err  error
 }
 
-func (m MockCondition) Build(paramOffset int) (string, []interface{}, error) {
+func (m MockCondition) Build(d dialect.Dialect, paramOffset int) (string, []interface{}, error) {
 	if m.err != nil {
 		return "", nil, m.err
 	}
@@ -171,7 +172,7 @@ func TestWhereGroup_Build(t *testing.T) {
 				whereGroup.Add(condition)
 			}
 
-			sql, args, err := whereGroup.Build(tt.paramOffset)
+			sql, args, err := whereGroup.Build(dialect.Postgres, tt.paramOffset)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -200,7 +201,6 @@ func TestNewWhereGroup(t *testing.T) {
 			expectedGroup: &WhereGroup{
 				Conditions: make([]QueryCondition, 0),
 				Operator:   AND,
-				IsNew:      true,
 			},
 		},
 		{
@@ -209,7 +209,6 @@ func TestNewWhereGroup(t *testing.T) {
 			expectedGroup: &WhereGroup{
 				Conditions: make([]QueryCondition, 0),
 				Operator:   OR,
-				IsNew:      true,
 			},
 		},
 	}
@@ -218,7 +217,6 @@ func TestNewWhereGroup(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			group := NewWhereGroup(tt.operator)
 			assert.Equal(t, tt.expectedGroup.Operator, group.Operator)
-			assert.Equal(t, tt.expectedGroup.IsNew, group.IsNew)
 			assert.Empty(t, group.Conditions)
 		})
 	}